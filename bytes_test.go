@@ -0,0 +1,132 @@
+package goschema
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/chasingcarrots/gobinary"
+)
+
+// the number of objects written by writeObjects
+const benchmarkObjects = 1000
+
+// writeObjects writes objects with a few numbers and a referenced string, like
+// generated code would.
+func writeObjects(writer *SchemaWriter) {
+	for i := 0; i < benchmarkObjects; i++ {
+		base := writer.Base()
+		startOffset := writer.BeginObject()
+		writer.WriteInt32(int32(i))
+		writer.WriteFloat64(float64(i) / 2)
+		writer.WriteBool(i%2 == 0)
+		writer.WriteUInt32(0) // reference to the string
+		stringOffset := writer.Offset()
+		writer.WriteUInt32(5)
+		writer.WriteString("hello")
+		end := writer.Offset()
+		writer.Seek(13, io.SeekStart)
+		writer.WriteUInt32(uint32(stringOffset))
+		writer.Seek(end, io.SeekStart)
+		writer.EndObject(startOffset)
+		writer.View(writer.Local(base))
+	}
+}
+
+func readObjects(reader *SchemaReader) error {
+	for i := 0; i < benchmarkObjects; i++ {
+		base := reader.Base()
+		nextOffset, err := reader.BeginObject("Object")
+		if err != nil {
+			return err
+		}
+		reader.ReadInt32()
+		reader.ReadFloat64()
+		reader.ReadBool()
+		returnOffset := reader.ReadReference()
+		reader.ReadString(reader.ReadStringLength())
+		reader.Seek(reader.Local(returnOffset), io.SeekStart)
+		reader.Seek(reader.Local(nextOffset), io.SeekStart)
+		reader.EndObject()
+		reader.View(reader.Local(base))
+	}
+	return reader.Err()
+}
+
+func writeStream() []byte {
+	var buf gobinary.WriteBuffer
+	writer := MakeSchemaWriter(nil, gobinary.MakeStreamWriterView(gobinary.NewStreamWriter(&buf)))
+	writeObjects(&writer)
+	return buf.Bytes()
+}
+
+func TestByteBackendMatchesStream(t *testing.T) {
+	writer := MakeByteSchemaWriter(nil, nil)
+	writeObjects(&writer)
+	data := writer.Bytes()
+	if !bytes.Equal(data, writeStream()) {
+		t.Fatal("byte slice and stream writers produced different data")
+	}
+	reader := MakeByteSchemaReader(nil, data)
+	if err := readObjects(&reader); err != nil {
+		t.Fatal(err)
+	}
+	streamReader := MakeSchemaReader(nil, gobinary.MakeStreamReaderView(gobinary.NewStreamReader(bytes.NewReader(data))))
+	if err := readObjects(&streamReader); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestByteBackendUnsupported(t *testing.T) {
+	writer := MakeByteSchemaWriter(nil, nil)
+	if _, err := writer.StreamWriterView.Write([]byte{1}); err != ErrByteBackend {
+		t.Fatalf("got %v", err)
+	}
+	reader := MakeByteSchemaReader(nil, []byte{1})
+	if _, err := reader.StreamReaderView.Read(make([]byte, 1)); err != ErrByteBackend {
+		t.Fatalf("got %v", err)
+	}
+}
+
+func BenchmarkWriteStream(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		writeStream()
+	}
+}
+
+func BenchmarkWriteBytes(b *testing.B) {
+	var buf []byte
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		writer := MakeByteSchemaWriter(nil, buf)
+		writeObjects(&writer)
+		buf = writer.Bytes()
+	}
+}
+
+func BenchmarkReadStream(b *testing.B) {
+	data := writeStream()
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		reader := MakeSchemaReader(nil, gobinary.MakeStreamReaderView(gobinary.NewStreamReader(bytes.NewReader(data))))
+		if err := readObjects(&reader); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkReadBytes(b *testing.B) {
+	data := writeStream()
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		reader := MakeByteSchemaReader(nil, data)
+		if err := readObjects(&reader); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,249 @@
+package goschema
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"unsafe"
+
+	"github.com/chasingcarrots/gobinary"
+)
+
+// OutOfRangeError is reported by readers over byte slices when data beyond
+// the end of the slice is requested.
+type OutOfRangeError struct {
+	Offset int64 // global offset of the read
+	Length int   // number of bytes requested
+	Size   int   // size of the data
+}
+
+func (e *OutOfRangeError) Error() string {
+	return fmt.Sprintf("goschema: cannot read %v bytes at offset %v, data has only %v bytes", e.Length, e.Offset, e.Size)
+}
+
+// byteReader holds the data of a SchemaReader that reads from a byte slice.
+// Reads outside of the data yield zero values and record an error.
+type byteReader struct {
+	data         []byte
+	position     int64
+	base         int64
+	err          error
+	aliasStrings bool
+}
+
+// next returns the next n bytes at the current position and advances the
+// position. It returns nil if there are not enough bytes left.
+func (br *byteReader) next(n int) []byte {
+	end := br.position + int64(n)
+	if n < 0 || br.position > int64(len(br.data)) || end > int64(len(br.data)) {
+		if br.err == nil {
+			br.err = &OutOfRangeError{Offset: br.position, Length: n, Size: len(br.data)}
+		}
+		return nil
+	}
+	p := br.data[br.position:end]
+	br.position = end
+	return p
+}
+
+func (br *byteReader) Read(p []byte) (int, error) {
+	if br.position >= int64(len(br.data)) {
+		return 0, io.EOF
+	}
+	n := copy(p, br.data[br.position:])
+	br.position += int64(n)
+	return n, nil
+}
+
+// MakeByteSchemaReader creates a SchemaReader that reads directly from a byte
+// slice instead of going through a gobinary stream. Reading past the end of the
+// data does not panic; instead, zero values are returned and the error is
+// reported by Err and by the generated reading methods.
+func MakeByteSchemaReader(schemaDB *SchemaDB, data []byte) SchemaReader {
+	br := &byteReader{data: data}
+	return SchemaReader{
+		HighLevelReader:  gobinary.MakeHighLevelReader(br),
+		StreamReaderView: gobinary.MakeStreamReaderView(gobinary.NewStreamReader(unsupportedStream{})),
+		schemaDB:         schemaDB,
+		bytes:            br,
+	}
+}
+
+// AliasStrings makes a reader over a byte slice return strings that share
+// their memory with the slice instead of copying it. The slice must not be
+// modified while any of these strings are in use.
+func (sr *SchemaReader) AliasStrings() {
+	if sr.bytes != nil {
+		sr.bytes.aliasStrings = true
+	}
+}
+
+func (sr *SchemaReader) ReadUInt8() uint8 {
+	if sr.bytes == nil {
+		return sr.HighLevelReader.ReadUInt8()
+	}
+	if p := sr.bytes.next(1); p != nil {
+		return p[0]
+	}
+	return 0
+}
+
+func (sr *SchemaReader) ReadUInt16() uint16 {
+	if sr.bytes == nil {
+		return sr.HighLevelReader.ReadUInt16()
+	}
+	if p := sr.bytes.next(2); p != nil {
+		return binary.LittleEndian.Uint16(p)
+	}
+	return 0
+}
+
+func (sr *SchemaReader) ReadUInt32() uint32 {
+	if sr.bytes == nil {
+		return sr.HighLevelReader.ReadUInt32()
+	}
+	if p := sr.bytes.next(4); p != nil {
+		return binary.LittleEndian.Uint32(p)
+	}
+	return 0
+}
+
+func (sr *SchemaReader) ReadUInt64() uint64 {
+	if sr.bytes == nil {
+		return sr.HighLevelReader.ReadUInt64()
+	}
+	if p := sr.bytes.next(8); p != nil {
+		return binary.LittleEndian.Uint64(p)
+	}
+	return 0
+}
+
+func (sr *SchemaReader) ReadInt8() int8 {
+	if sr.bytes == nil {
+		return sr.HighLevelReader.ReadInt8()
+	}
+	return int8(sr.ReadUInt8())
+}
+
+func (sr *SchemaReader) ReadInt16() int16 {
+	if sr.bytes == nil {
+		return sr.HighLevelReader.ReadInt16()
+	}
+	return int16(sr.ReadUInt16())
+}
+
+func (sr *SchemaReader) ReadInt32() int32 {
+	if sr.bytes == nil {
+		return sr.HighLevelReader.ReadInt32()
+	}
+	return int32(sr.ReadUInt32())
+}
+
+func (sr *SchemaReader) ReadInt64() int64 {
+	if sr.bytes == nil {
+		return sr.HighLevelReader.ReadInt64()
+	}
+	return int64(sr.ReadUInt64())
+}
+
+func (sr *SchemaReader) ReadFloat32() float32 {
+	if sr.bytes == nil {
+		return sr.HighLevelReader.ReadFloat32()
+	}
+	return math.Float32frombits(sr.ReadUInt32())
+}
+
+func (sr *SchemaReader) ReadFloat64() float64 {
+	if sr.bytes == nil {
+		return sr.HighLevelReader.ReadFloat64()
+	}
+	return math.Float64frombits(sr.ReadUInt64())
+}
+
+func (sr *SchemaReader) ReadBool() bool {
+	if sr.bytes == nil {
+		return sr.HighLevelReader.ReadBool()
+	}
+	return sr.ReadUInt8() != 0
+}
+
+func (sr *SchemaReader) ReadString(length int) string {
+	if sr.bytes == nil {
+		return sr.HighLevelReader.ReadString(length)
+	}
+	p := sr.bytes.next(length)
+	if len(p) == 0 {
+		return ""
+	}
+	if sr.bytes.aliasStrings {
+		return unsafe.String(&p[0], len(p))
+	}
+	return string(p)
+}
+
+func (sr *SchemaReader) Seek(offset int64, whence int) (int64, error) {
+	if sr.bytes == nil {
+		return sr.StreamReaderView.Seek(offset, whence)
+	}
+	br := sr.bytes
+	switch whence {
+	case io.SeekStart:
+		offset += br.base
+	case io.SeekCurrent:
+		offset += br.position
+	case io.SeekEnd:
+		offset += int64(len(br.data))
+	default:
+		return br.position - br.base, fmt.Errorf("goschema: invalid whence %v", whence)
+	}
+	if offset < 0 {
+		return br.position - br.base, fmt.Errorf("goschema: negative offset %v", offset)
+	}
+	br.position = offset
+	return offset - br.base, nil
+}
+
+func (sr *SchemaReader) Offset() int64 {
+	if sr.bytes == nil {
+		return sr.StreamReaderView.Offset()
+	}
+	return sr.bytes.position - sr.bytes.base
+}
+
+func (sr *SchemaReader) GlobalOffset() int64 {
+	if sr.bytes == nil {
+		return sr.StreamReaderView.GlobalOffset()
+	}
+	return sr.bytes.position
+}
+
+func (sr *SchemaReader) Base() int64 {
+	if sr.bytes == nil {
+		return sr.StreamReaderView.Base()
+	}
+	return sr.bytes.base
+}
+
+func (sr *SchemaReader) Local(global int64) int64 {
+	if sr.bytes == nil {
+		return sr.StreamReaderView.Local(global)
+	}
+	return global - sr.bytes.base
+}
+
+func (sr *SchemaReader) View(local int64) {
+	if sr.bytes == nil {
+		sr.StreamReaderView.View(local)
+		return
+	}
+	sr.bytes.base += local
+}
+
+func (sr *SchemaReader) ViewHere() {
+	if sr.bytes == nil {
+		sr.StreamReaderView.ViewHere()
+		return
+	}
+	sr.bytes.base = sr.bytes.position
+}
@@ -0,0 +1,248 @@
+package goschema
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/chasingcarrots/gobinary"
+)
+
+// ErrByteBackend is returned by stream operations that SchemaWriters and
+// SchemaReaders over byte slices do not support.
+var ErrByteBackend = errors.New("goschema: operation is not supported by SchemaWriters and SchemaReaders over byte slices")
+
+// unsupportedStream is the stream below the gobinary views of writers and
+// readers over byte slices, so that methods of the views which these do not
+// override fail with ErrByteBackend.
+type unsupportedStream struct{}
+
+func (unsupportedStream) Read(p []byte) (int, error)                   { return 0, ErrByteBackend }
+func (unsupportedStream) Write(p []byte) (int, error)                  { return 0, ErrByteBackend }
+func (unsupportedStream) Seek(offset int64, whence int) (int64, error) { return 0, ErrByteBackend }
+
+// byteWriter holds the data of a SchemaWriter that writes to a byte slice.
+// Offsets are global offsets into data, the view is described by base.
+type byteWriter struct {
+	data     []byte
+	position int64
+	base     int64
+}
+
+// reserve returns the next n bytes at the current position and advances the
+// position, growing the data as required.
+func (bw *byteWriter) reserve(n int) []byte {
+	end := bw.position + int64(n)
+	if end > int64(len(bw.data)) {
+		if end > int64(cap(bw.data)) {
+			grown := make([]byte, end, 2*end)
+			copy(grown, bw.data)
+			bw.data = grown
+		} else {
+			// the capacity beyond len may contain data from an earlier use
+			tail := bw.data[len(bw.data):end]
+			for i := range tail {
+				tail[i] = 0
+			}
+			bw.data = bw.data[:end]
+		}
+	}
+	p := bw.data[bw.position:end]
+	bw.position = end
+	return p
+}
+
+func (bw *byteWriter) Write(p []byte) (int, error) {
+	return copy(bw.reserve(len(p)), p), nil
+}
+
+// MakeByteSchemaWriter creates a SchemaWriter that writes directly to a byte
+// slice instead of going through a gobinary stream. Data is written starting
+// at the beginning of buf, reusing its capacity; the result can be retrieved
+// with Bytes. The data is identical to that written by a SchemaWriter over a
+// stream, so generated schemata work with either kind of writer.
+func MakeByteSchemaWriter(schemaData *SchemaDBWriter, buf []byte) SchemaWriter {
+	bw := &byteWriter{data: buf[:0]}
+	return SchemaWriter{
+		HighLevelWriter:  gobinary.MakeHighLevelWriter(bw),
+		StreamWriterView: gobinary.MakeStreamWriterView(gobinary.NewStreamWriter(unsupportedStream{})),
+		schemaData:       schemaData,
+		bytes:            bw,
+	}
+}
+
+// Bytes returns the data written to a writer created by MakeByteSchemaWriter.
+func (sw *SchemaWriter) Bytes() []byte {
+	if sw.bytes == nil {
+		return nil
+	}
+	return sw.bytes.data
+}
+
+func (sw *SchemaWriter) WriteUInt8(value uint8) {
+	if sw.bytes == nil {
+		sw.HighLevelWriter.WriteUInt8(value)
+		return
+	}
+	sw.bytes.reserve(1)[0] = value
+}
+
+func (sw *SchemaWriter) WriteUInt16(value uint16) {
+	if sw.bytes == nil {
+		sw.HighLevelWriter.WriteUInt16(value)
+		return
+	}
+	binary.LittleEndian.PutUint16(sw.bytes.reserve(2), value)
+}
+
+func (sw *SchemaWriter) WriteUInt32(value uint32) {
+	if sw.bytes == nil {
+		sw.HighLevelWriter.WriteUInt32(value)
+		return
+	}
+	binary.LittleEndian.PutUint32(sw.bytes.reserve(4), value)
+}
+
+func (sw *SchemaWriter) WriteUInt64(value uint64) {
+	if sw.bytes == nil {
+		sw.HighLevelWriter.WriteUInt64(value)
+		return
+	}
+	binary.LittleEndian.PutUint64(sw.bytes.reserve(8), value)
+}
+
+func (sw *SchemaWriter) WriteInt8(value int8) {
+	if sw.bytes == nil {
+		sw.HighLevelWriter.WriteInt8(value)
+		return
+	}
+	sw.bytes.reserve(1)[0] = uint8(value)
+}
+
+func (sw *SchemaWriter) WriteInt16(value int16) {
+	if sw.bytes == nil {
+		sw.HighLevelWriter.WriteInt16(value)
+		return
+	}
+	binary.LittleEndian.PutUint16(sw.bytes.reserve(2), uint16(value))
+}
+
+func (sw *SchemaWriter) WriteInt32(value int32) {
+	if sw.bytes == nil {
+		sw.HighLevelWriter.WriteInt32(value)
+		return
+	}
+	binary.LittleEndian.PutUint32(sw.bytes.reserve(4), uint32(value))
+}
+
+func (sw *SchemaWriter) WriteInt64(value int64) {
+	if sw.bytes == nil {
+		sw.HighLevelWriter.WriteInt64(value)
+		return
+	}
+	binary.LittleEndian.PutUint64(sw.bytes.reserve(8), uint64(value))
+}
+
+func (sw *SchemaWriter) WriteFloat32(value float32) {
+	if sw.bytes == nil {
+		sw.HighLevelWriter.WriteFloat32(value)
+		return
+	}
+	binary.LittleEndian.PutUint32(sw.bytes.reserve(4), math.Float32bits(value))
+}
+
+func (sw *SchemaWriter) WriteFloat64(value float64) {
+	if sw.bytes == nil {
+		sw.HighLevelWriter.WriteFloat64(value)
+		return
+	}
+	binary.LittleEndian.PutUint64(sw.bytes.reserve(8), math.Float64bits(value))
+}
+
+func (sw *SchemaWriter) WriteBool(value bool) {
+	if sw.bytes == nil {
+		sw.HighLevelWriter.WriteBool(value)
+		return
+	}
+	if value {
+		sw.bytes.reserve(1)[0] = 1
+	} else {
+		sw.bytes.reserve(1)[0] = 0
+	}
+}
+
+func (sw *SchemaWriter) WriteString(value string) {
+	if sw.bytes == nil {
+		sw.HighLevelWriter.WriteString(value)
+		return
+	}
+	copy(sw.bytes.reserve(len(value)), value)
+}
+
+func (sw *SchemaWriter) Seek(offset int64, whence int) (int64, error) {
+	if sw.bytes == nil {
+		return sw.StreamWriterView.Seek(offset, whence)
+	}
+	bw := sw.bytes
+	switch whence {
+	case io.SeekStart:
+		offset += bw.base
+	case io.SeekCurrent:
+		offset += bw.position
+	case io.SeekEnd:
+		offset += int64(len(bw.data))
+	default:
+		return bw.position - bw.base, fmt.Errorf("goschema: invalid whence %v", whence)
+	}
+	if offset < 0 {
+		return bw.position - bw.base, fmt.Errorf("goschema: negative offset %v", offset)
+	}
+	bw.position = offset
+	return offset - bw.base, nil
+}
+
+func (sw *SchemaWriter) Offset() int64 {
+	if sw.bytes == nil {
+		return sw.StreamWriterView.Offset()
+	}
+	return sw.bytes.position - sw.bytes.base
+}
+
+func (sw *SchemaWriter) GlobalOffset() int64 {
+	if sw.bytes == nil {
+		return sw.StreamWriterView.GlobalOffset()
+	}
+	return sw.bytes.position
+}
+
+func (sw *SchemaWriter) Base() int64 {
+	if sw.bytes == nil {
+		return sw.StreamWriterView.Base()
+	}
+	return sw.bytes.base
+}
+
+func (sw *SchemaWriter) Local(global int64) int64 {
+	if sw.bytes == nil {
+		return sw.StreamWriterView.Local(global)
+	}
+	return global - sw.bytes.base
+}
+
+func (sw *SchemaWriter) View(local int64) {
+	if sw.bytes == nil {
+		sw.StreamWriterView.View(local)
+		return
+	}
+	sw.bytes.base += local
+}
+
+func (sw *SchemaWriter) ViewHere() {
+	if sw.bytes == nil {
+		sw.StreamWriterView.ViewHere()
+		return
+	}
+	sw.bytes.base = sw.bytes.position
+}
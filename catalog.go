@@ -0,0 +1,236 @@
+package goschema
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+const catalogLatestFile = "LATEST"
+const catalogExtension = ".schemadb"
+
+// CatalogVersion identifies a version of a schema catalog. It is the SHA-256
+// hash of the schema DB of that version.
+type CatalogVersion [sha256.Size]byte
+
+func (v CatalogVersion) String() string {
+	return hex.EncodeToString(v[:])
+}
+
+func ParseCatalogVersion(s string) (CatalogVersion, error) {
+	var v CatalogVersion
+	decoded, err := hex.DecodeString(s)
+	if err != nil || len(decoded) != len(v) {
+		return v, fmt.Errorf("goschema: invalid catalog version %q", s)
+	}
+	copy(v[:], decoded)
+	return v, nil
+}
+
+var ErrCatalogVersionMismatch = errors.New("goschema: catalog file does not match its version")
+
+// SchemaCatalog is a directory of schema DBs that is shared by many data files.
+// Every version of the catalog is stored in a file named after its hash, and
+// each version contains all schemata of the version it was derived from with
+// the same indices. Data files record the version they were written against,
+// so they can always be read, even if the catalog has grown since.
+type SchemaCatalog struct {
+	dir    string
+	mutex  sync.Mutex
+	loaded map[CatalogVersion]*SchemaDB
+}
+
+// OpenSchemaCatalog opens the catalog in the given directory, creating the
+// directory if it does not exist yet.
+func OpenSchemaCatalog(dir string) (*SchemaCatalog, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	return &SchemaCatalog{
+		dir:    dir,
+		loaded: make(map[CatalogVersion]*SchemaDB),
+	}, nil
+}
+
+// Latest returns the most recently committed version of the catalog. The
+// second return value is false if nothing has been committed yet.
+func (c *SchemaCatalog) Latest() (CatalogVersion, bool, error) {
+	content, err := os.ReadFile(filepath.Join(c.dir, catalogLatestFile))
+	if os.IsNotExist(err) {
+		return CatalogVersion{}, false, nil
+	} else if err != nil {
+		return CatalogVersion{}, false, err
+	}
+	version, err := ParseCatalogVersion(strings.TrimSpace(string(content)))
+	return version, err == nil, err
+}
+
+func (c *SchemaCatalog) path(version CatalogVersion) string {
+	return filepath.Join(c.dir, version.String()+catalogExtension)
+}
+
+func (c *SchemaCatalog) readVersion(version CatalogVersion) ([]byte, error) {
+	content, err := os.ReadFile(c.path(version))
+	if err != nil {
+		return nil, err
+	}
+	if sha256.Sum256(content) != version {
+		return nil, ErrCatalogVersionMismatch
+	}
+	return content, nil
+}
+
+// Load returns the schema DB of the given version. Schema DBs are cached, so
+// all readers of the same version share the schemata they have filled, even
+// if they run in different goroutines.
+func (c *SchemaCatalog) Load(version CatalogVersion) (*SchemaDB, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if db, ok := c.loaded[version]; ok {
+		return db, nil
+	}
+	content, err := c.readVersion(version)
+	if err != nil {
+		return nil, err
+	}
+	db := MakeSchemaDB()
+	if err := db.Fill(bytes.NewReader(content)); err != nil {
+		return nil, err
+	}
+	c.loaded[version] = &db
+	return &db, nil
+}
+
+// NewWriter returns a writer that continues the latest version of the
+// catalog. Schemata registered with it are only stored in the catalog once
+// Commit is called.
+func (c *SchemaCatalog) NewWriter() (*CatalogWriter, error) {
+	file := &memoryFile{}
+	version, ok, err := c.Latest()
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		if file.data, err = c.readVersion(version); err != nil {
+			return nil, err
+		}
+	}
+	dbWriter, err := OpenSchemaDBWriter(file)
+	if err != nil {
+		return nil, err
+	}
+	return &CatalogWriter{
+		SchemaDBWriter: dbWriter,
+		catalog:        c,
+		file:           file,
+	}, nil
+}
+
+// CatalogWriter is a SchemaDBWriter whose schemata are stored in a catalog.
+type CatalogWriter struct {
+	SchemaDBWriter
+	catalog *SchemaCatalog
+	file    *memoryFile
+}
+
+// Commit stores the current state of the writer as a new version of the
+// catalog and makes it the latest version. If the catalog already contains
+// that version, nothing is written. The writer can still be used afterwards
+// and committed again.
+func (cw *CatalogWriter) Commit() (CatalogVersion, error) {
+	cw.Close()
+	version := CatalogVersion(sha256.Sum256(cw.file.data))
+	c := cw.catalog
+	if _, err := os.Stat(c.path(version)); os.IsNotExist(err) {
+		if err := writeFileAtomic(c.path(version), cw.file.data); err != nil {
+			return version, err
+		}
+	} else if err != nil {
+		return version, err
+	}
+	return version, writeFileAtomic(filepath.Join(c.dir, catalogLatestFile), []byte(version.String()+"\n"))
+}
+
+// WriteCatalogVersion writes the version that the following data is written
+// against.
+func WriteCatalogVersion(writer *SchemaWriter, version CatalogVersion) {
+	writer.Write(version[:])
+}
+
+// ReadCatalogVersion reads a version written by WriteCatalogVersion and makes
+// the reader use the schema DB of that version from the catalog.
+func (c *SchemaCatalog) ReadCatalogVersion(reader *SchemaReader) (CatalogVersion, error) {
+	var version CatalogVersion
+	if _, err := io.ReadFull(reader, version[:]); err != nil {
+		return version, err
+	}
+	db, err := c.Load(version)
+	if err != nil {
+		return version, err
+	}
+	reader.schemaDB = db
+	return version, nil
+}
+
+func writeFileAtomic(path string, data []byte) error {
+	temp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := temp.Write(data); err != nil {
+		temp.Close()
+		os.Remove(temp.Name())
+		return err
+	}
+	if err := temp.Close(); err != nil {
+		os.Remove(temp.Name())
+		return err
+	}
+	return os.Rename(temp.Name(), path)
+}
+
+// memoryFile is an in-memory io.ReadWriteSeeker.
+type memoryFile struct {
+	data     []byte
+	position int64
+}
+
+func (f *memoryFile) Read(p []byte) (int, error) {
+	if f.position >= int64(len(f.data)) {
+		return 0, io.EOF
+	}
+	n := copy(p, f.data[f.position:])
+	f.position += int64(n)
+	return n, nil
+}
+
+func (f *memoryFile) Write(p []byte) (int, error) {
+	end := f.position + int64(len(p))
+	if end > int64(len(f.data)) {
+		f.data = append(f.data, make([]byte, end-int64(len(f.data)))...)
+	}
+	copy(f.data[f.position:], p)
+	f.position = end
+	return len(p), nil
+}
+
+func (f *memoryFile) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekCurrent:
+		offset += f.position
+	case io.SeekEnd:
+		offset += int64(len(f.data))
+	}
+	if offset < 0 {
+		return f.position, fmt.Errorf("goschema: seek to negative offset %v", offset)
+	}
+	f.position = offset
+	return offset, nil
+}
@@ -0,0 +1,64 @@
+package goschema
+
+import (
+	"sync"
+	"testing"
+)
+
+type catalogPoint struct {
+	X, Y int32
+}
+
+func TestCatalogSharedByReaders(t *testing.T) {
+	catalog, err := OpenSchemaCatalog(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	catalogWriter, err := catalog.NewWriter()
+	if err != nil {
+		t.Fatal(err)
+	}
+	point := catalogPoint{X: 1, Y: 2}
+	writer := MakeByteSchemaWriter(&catalogWriter.SchemaDBWriter, nil)
+	if err := Marshal(&writer, &point); err != nil {
+		t.Fatal(err)
+	}
+	version, err := catalogWriter.Commit()
+	if err != nil {
+		t.Fatal(err)
+	}
+	blobWriter := MakeByteSchemaWriter(&catalogWriter.SchemaDBWriter, nil)
+	WriteCatalogVersion(&blobWriter, version)
+	if err := Marshal(&blobWriter, &point); err != nil {
+		t.Fatal(err)
+	}
+	blob := blobWriter.Bytes()
+
+	// readers of the same version share its schema DB
+	var wait sync.WaitGroup
+	errs := make(chan error, 8)
+	for i := 0; i < cap(errs); i++ {
+		wait.Add(1)
+		go func() {
+			defer wait.Done()
+			reader := MakeByteSchemaReader(nil, blob)
+			if _, err := catalog.ReadCatalogVersion(&reader); err != nil {
+				errs <- err
+				return
+			}
+			var result catalogPoint
+			if err := Unmarshal(&reader, &result); err != nil {
+				errs <- err
+				return
+			}
+			if result != point {
+				t.Errorf("read %v", result)
+			}
+		}()
+	}
+	wait.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,95 @@
+package goschema
+
+import (
+	"fmt"
+	"hash/crc32"
+	"io"
+
+	"github.com/chasingcarrots/gobinary"
+)
+
+// ChecksumFlag is set in the length of an object if the object's data is
+// followed by a CRC32C checksum of that data.
+const ChecksumFlag uint32 = 1 << 31
+
+const ChecksumSize = 4
+
+var castagnoliTable = crc32.MakeTable(crc32.Castagnoli)
+
+// ChecksumError is returned when reading an object whose data does not match
+// its stored checksum.
+type ChecksumError struct {
+	Schema   string // name of the schema that was used to read the object
+	Offset   int64  // global offset of the object
+	Expected uint32
+	Actual   uint32
+}
+
+func (e *ChecksumError) Error() string {
+	return fmt.Sprintf("goschema: checksum mismatch in %v object at offset %v (stored %08x, computed %08x)",
+		e.Schema, e.Offset, e.Expected, e.Actual)
+}
+
+// checksumMirror keeps a copy of the data written to a SchemaWriter so that the
+// checksum of an object can be computed once all of its data (including the
+// backpatched references) has been written. Data is only retained while an
+// object is open. For writers that write to a byte slice, the checksum is
+// computed from that slice directly.
+type checksumMirror struct {
+	view   gobinary.StreamWriterView
+	output io.Writer // writes to the view and keeps its errors
+	buffer *byteWriter
+	origin int64
+	data   []byte
+	depth  int
+}
+
+func (cm *checksumMirror) Write(p []byte) (int, error) {
+	position := cm.view.GlobalOffset()
+	n, err := cm.output.Write(p)
+	cm.store(position, p[:n])
+	return n, err
+}
+
+func (cm *checksumMirror) store(position int64, p []byte) {
+	if position < cm.origin {
+		skip := cm.origin - position
+		if skip >= int64(len(p)) {
+			return
+		}
+		p = p[skip:]
+		position = cm.origin
+	}
+	start := int(position - cm.origin)
+	end := start + len(p)
+	cm.grow(end)
+	copy(cm.data[start:end], p)
+}
+
+func (cm *checksumMirror) begin() {
+	cm.depth++
+}
+
+// end returns the checksum of the data between the two global offsets.
+func (cm *checksumMirror) end(startOffset, endOffset int64) uint32 {
+	cm.depth--
+	if cm.buffer != nil {
+		return crc32.Checksum(cm.buffer.data[startOffset:endOffset], castagnoliTable)
+	}
+	cm.grow(int(endOffset - cm.origin))
+	return crc32.Checksum(cm.data[startOffset-cm.origin:endOffset-cm.origin], castagnoliTable)
+}
+
+func (cm *checksumMirror) grow(size int) {
+	if size > len(cm.data) {
+		cm.data = append(cm.data, make([]byte, size-len(cm.data))...)
+	}
+}
+
+// reset drops all mirrored data once no object is open anymore.
+func (cm *checksumMirror) reset(offset int64) {
+	if cm.depth == 0 {
+		cm.origin = offset
+		cm.data = cm.data[:0]
+	}
+}
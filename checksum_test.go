@@ -0,0 +1,58 @@
+package goschema
+
+import (
+	"encoding/binary"
+	"hash/crc32"
+	"testing"
+)
+
+// writeNested writes an object containing a number and a nested object that
+// contains another number, both with checksums.
+func writeNested() []byte {
+	writer := MakeByteSchemaWriter(nil, nil)
+	writer.EnableChecksums()
+	outer := writer.BeginObject()
+	writer.WriteUInt32(1)
+	inner := writer.BeginObject()
+	writer.WriteUInt32(2)
+	writer.EndObject(inner)
+	writer.View(writer.Local(outer))
+	writer.EndObject(outer)
+	return writer.Bytes()
+}
+
+func readNested(data []byte) error {
+	reader := MakeByteSchemaReader(nil, data)
+	if _, err := reader.BeginObject("Outer"); err != nil {
+		return err
+	}
+	defer reader.EndObject()
+	reader.ReadUInt32()
+	if _, err := reader.BeginObject("Inner"); err != nil {
+		return err
+	}
+	reader.EndObject()
+	return reader.Err()
+}
+
+func TestNestedChecksums(t *testing.T) {
+	data := writeNested()
+	if err := readNested(data); err != nil {
+		t.Fatal(err)
+	}
+
+	// corrupting the inner object is detected by the outer one
+	corrupt := append([]byte(nil), data...)
+	corrupt[12]++
+	err := readNested(corrupt)
+	if checksumErr, ok := err.(*ChecksumError); !ok || checksumErr.Schema != "Outer" {
+		t.Fatalf("got %v", err)
+	}
+
+	// the inner checksum is not verified again once the outer one matches
+	outerEnd := len(corrupt) - ChecksumSize
+	binary.LittleEndian.PutUint32(corrupt[outerEnd:], crc32.Checksum(corrupt[4:outerEnd], castagnoliTable))
+	if err := readNested(corrupt); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,76 @@
+// Command goschema provides tools for working with schema DBs.
+//
+//	goschema compat [-strict] old.schemadb new.schemadb
+//
+// compat reports the changes between the schemata of two schema DBs that
+// affect reading data written with the old one. It exits with status 1 if old
+// data can no longer be read correctly, or with -strict, if fields have been
+// removed.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/chasingcarrots/goschema"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+	switch os.Args[1] {
+	case "compat":
+		os.Exit(compat(os.Args[2:]))
+	default:
+		usage()
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: goschema compat [-strict] old.schemadb new.schemadb")
+	os.Exit(2)
+}
+
+func compat(args []string) int {
+	flags := flag.NewFlagSet("compat", flag.ExitOnError)
+	strict := flags.Bool("strict", false, "treat removed fields as breaking")
+	flags.Parse(args)
+	if flags.NArg() != 2 {
+		usage()
+	}
+
+	old, err := loadSchemaDB(flags.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	current, err := loadSchemaDB(flags.Arg(1))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	status := 0
+	for _, issue := range goschema.CheckSchemaDBCompatibility(old, current) {
+		fmt.Println(issue)
+		if issue.Kind.Breaking() || (*strict && issue.Kind == goschema.FieldRemoved) {
+			status = 1
+		}
+	}
+	return status
+}
+
+func loadSchemaDB(path string) (*goschema.SchemaDB, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	db := goschema.MakeSchemaDB()
+	if err := db.Fill(file); err != nil {
+		return nil, fmt.Errorf("%v: %v", path, err)
+	}
+	return &db, nil
+}
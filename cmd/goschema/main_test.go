@@ -0,0 +1,66 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/chasingcarrots/gobinary"
+	"github.com/chasingcarrots/goschema"
+)
+
+// describedSchema is a schema that only consists of its entries.
+type describedSchema struct {
+	entries, deprecated []goschema.SchemaEntry
+}
+
+func (s *describedSchema) Fill([]goschema.SchemaEntry)                {}
+func (s *describedSchema) Describe() []goschema.SchemaEntry           { return s.entries }
+func (s *describedSchema) DescribeDeprecated() []goschema.SchemaEntry { return s.deprecated }
+func (s *describedSchema) ID() goschema.SchemaID                      { return 0 }
+func (s *describedSchema) Fingerprint() uint64                        { return 0 }
+func (s *describedSchema) Name() string                               { return "Test" }
+func (s *describedSchema) GoType() string                             { return "" }
+
+// writeSchemaDB writes a schema DB that holds the schema to a file and returns
+// its path.
+func writeSchemaDB(t *testing.T, name string, schema goschema.Schema) string {
+	path := filepath.Join(t.TempDir(), name)
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	dbWriter := goschema.MakeSchemaDBWriter(gobinary.NewStreamWriter(file))
+	dbWriter.RegisterSchema(schema)
+	dbWriter.Close()
+	return path
+}
+
+func TestCompat(t *testing.T) {
+	level := goschema.SchemaEntry{Name: "Level", Type: goschema.Int32Type}
+	legacy := goschema.SchemaEntry{Name: "Legacy", Type: goschema.Int32Type, Offset: 4}
+	old := writeSchemaDB(t, "old.schemadb", &describedSchema{entries: []goschema.SchemaEntry{level, legacy}})
+	deprecated := writeSchemaDB(t, "deprecated.schemadb", &describedSchema{
+		entries:    []goschema.SchemaEntry{level},
+		deprecated: []goschema.SchemaEntry{{Name: "Legacy", Type: goschema.Int32Type}},
+	})
+	removed := writeSchemaDB(t, "removed.schemadb", &describedSchema{entries: []goschema.SchemaEntry{level}})
+	changed := writeSchemaDB(t, "changed.schemadb", &describedSchema{entries: []goschema.SchemaEntry{
+		{Name: "Level", Type: goschema.StringType},
+	}})
+
+	for _, test := range []struct {
+		args   []string
+		status int
+	}{
+		{[]string{"-strict", old, deprecated}, 0},
+		{[]string{old, removed}, 0},
+		{[]string{"-strict", old, removed}, 1},
+		{[]string{old, changed}, 1},
+	} {
+		if status := compat(test.args); status != test.status {
+			t.Errorf("compat %v: status %v instead of %v", test.args, status, test.status)
+		}
+	}
+}
@@ -0,0 +1,208 @@
+package goschema
+
+import "fmt"
+
+type CompatKind int
+
+const (
+	// FieldRemoved means that a field of the old schema is neither present in
+	// the new one nor covered by an alias. Its data is ignored when reading.
+	FieldRemoved CompatKind = iota
+	// FieldTypeChanged means that the type of a field has changed in a way
+	// that cannot be read. The field is treated as missing when reading old
+	// data.
+	FieldTypeChanged
+	// RequiredFieldMissing means that a required field of the new schema is
+	// not present in the old one, so reading old data fails.
+	RequiredFieldMissing
+	// FieldRenamed means that a field of the old schema is found through an
+	// alias of a field of the new schema.
+	FieldRenamed
+	// SchemaRemoved means that a schema of the old schema DB has no
+	// counterpart among the new schemata.
+	SchemaRemoved
+	// FieldWidened means that a number field has changed to a type that can
+	// represent all values of its previous type, see CanWiden.
+	FieldWidened
+)
+
+var compatKindNames = [...]string{
+	FieldRemoved:         "field removed",
+	FieldTypeChanged:     "field type changed",
+	RequiredFieldMissing: "required field missing",
+	FieldRenamed:         "field renamed",
+	SchemaRemoved:        "schema removed",
+	FieldWidened:         "field widened",
+}
+
+func (k CompatKind) String() string {
+	if k < 0 || int(k) >= len(compatKindNames) {
+		return fmt.Sprintf("CompatKind(%v)", int(k))
+	}
+	return compatKindNames[k]
+}
+
+// Breaking reports whether old data can no longer be read correctly.
+func (k CompatKind) Breaking() bool {
+	return k == FieldTypeChanged || k == RequiredFieldMissing
+}
+
+// CompatIssue is a difference between an old and a new schema that affects
+// reading old data.
+type CompatIssue struct {
+	Kind     CompatKind
+	Schema   string
+	Field    string // name of the field in the new schema, or in the old one if it was removed
+	OldField string // name of the field in the old schema if it was renamed
+	OldType  *TypeDescriptor
+	NewType  *TypeDescriptor
+}
+
+func (issue CompatIssue) String() string {
+	switch issue.Kind {
+	case FieldTypeChanged, FieldWidened:
+		return fmt.Sprintf("%v.%v: %v from %v to %v", issue.Schema, issue.Field, issue.Kind, issue.OldType, issue.NewType)
+	case FieldRenamed:
+		return fmt.Sprintf("%v.%v: %v from %v", issue.Schema, issue.Field, issue.Kind, issue.OldField)
+	case SchemaRemoved:
+		return fmt.Sprintf("%v: %v", issue.Schema, issue.Kind)
+	}
+	return fmt.Sprintf("%v.%v: %v", issue.Schema, issue.Field, issue.Kind)
+}
+
+// CheckCompatibility compares the schemata of an old schema DB with the given
+// schemata, usually those of the current build, and reports everything that
+// affects reading the old data with them. Schemata are matched by name, so
+// only schema DBs that record names can be checked. If the old schema DB holds
+// several versions of a schema, all of them are compared. Deprecated fields
+// of the schemata (see DeprecatedDescriber) can still be read, so they do not
+// count as removed.
+func CheckCompatibility(old *SchemaDB, schemata []Schema) []CompatIssue {
+	records := make([]schemaRecord, len(schemata))
+	for i, schema := range schemata {
+		records[i] = schemaRecord{
+			entries:     schema.Describe(),
+			deprecated:  describeDeprecated(schema),
+			fingerprint: schema.Fingerprint(),
+			name:        schema.Name(),
+		}
+	}
+	return checkCompatibility(old, records)
+}
+
+// CheckSchemaDBCompatibility is like CheckCompatibility, but takes the new
+// schemata from a schema DB. If it holds several versions of a schema, the
+// most recent one is used. The deprecated fields recorded by the current schema
+// DB do not count as removed.
+func CheckSchemaDBCompatibility(old, current *SchemaDB) []CompatIssue {
+	var records []schemaRecord
+	positions := make(map[string]int)
+	for i := 0; i < current.NumSchemata(); i++ {
+		record := schemaRecord{
+			entries:     current.rawSchemata[i],
+			deprecated:  current.deprecated[i],
+			fingerprint: current.fingerprints[i],
+		}
+		record.name, record.goType = current.SchemaName(i)
+		if position, ok := positions[record.name]; ok {
+			records[position] = record
+		} else {
+			positions[record.name] = len(records)
+			records = append(records, record)
+		}
+	}
+	return checkCompatibility(old, records)
+}
+
+func checkCompatibility(old *SchemaDB, schemata []schemaRecord) []CompatIssue {
+	var issues []CompatIssue
+	seen := make(map[string]bool)
+	report := func(issue CompatIssue) {
+		key := issue.String()
+		if !seen[key] {
+			seen[key] = true
+			issues = append(issues, issue)
+		}
+	}
+
+	byName := make(map[string]*schemaRecord, len(schemata))
+	for i := range schemata {
+		byName[schemata[i].name] = &schemata[i]
+	}
+	for i := 0; i < old.NumSchemata(); i++ {
+		name, _ := old.SchemaName(i)
+		if name == "" {
+			continue
+		}
+		schema, ok := byName[name]
+		if !ok {
+			report(CompatIssue{Kind: SchemaRemoved, Schema: name})
+			continue
+		}
+		if fingerprint := old.fingerprints[i]; fingerprint != 0 && fingerprint == schema.fingerprint {
+			continue
+		}
+		// deprecated fields can still be read, so they are not removed
+		readable := append(append([]SchemaEntry(nil), schema.entries...), schema.deprecated...)
+		compareFields(name, old.rawSchemata[i], readable, report)
+	}
+	return issues
+}
+
+// CompareSchemaEntries compares the entries of two versions of a schema like
+// CheckCompatibility.
+func CompareSchemaEntries(schema string, oldEntries, newEntries []SchemaEntry) []CompatIssue {
+	var issues []CompatIssue
+	compareFields(schema, oldEntries, newEntries, func(issue CompatIssue) {
+		issues = append(issues, issue)
+	})
+	return issues
+}
+
+func compareFields(schema string, oldEntries, newEntries []SchemaEntry, report func(CompatIssue)) {
+	used := make([]bool, len(oldEntries))
+	find := func(name string) int {
+		for i := range oldEntries {
+			if oldEntries[i].Name == name {
+				return i
+			}
+		}
+		return -1
+	}
+
+	for _, entry := range newEntries {
+		index := find(entry.Name)
+		for a := 0; index == -1 && a < len(entry.Aliases); a++ {
+			if index = find(entry.Aliases[a]); index != -1 {
+				report(CompatIssue{Kind: FieldRenamed, Schema: schema, Field: entry.Name, OldField: entry.Aliases[a]})
+			}
+		}
+		if index == -1 {
+			if entry.Required {
+				report(CompatIssue{Kind: RequiredFieldMissing, Schema: schema, Field: entry.Name})
+			}
+			continue
+		}
+		used[index] = true
+		oldEntry := &oldEntries[index]
+		kind := FieldTypeChanged
+		if entry.CanRead(oldEntry) {
+			kind = FieldWidened
+		}
+		if oldEntry.Type != entry.Type || kind == FieldTypeChanged {
+			report(CompatIssue{
+				Kind:    kind,
+				Schema:  schema,
+				Field:   entry.Name,
+				OldType: oldEntry.Descriptor,
+				NewType: entry.Descriptor,
+			})
+		}
+	}
+
+	for i := range oldEntries {
+		if !used[i] {
+			report(CompatIssue{Kind: FieldRemoved, Schema: schema, Field: oldEntries[i].Name})
+		}
+	}
+}
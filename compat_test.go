@@ -0,0 +1,62 @@
+package goschema_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/chasingcarrots/gobinary"
+	"github.com/chasingcarrots/goschema"
+	"github.com/chasingcarrots/goschema/generator/testdata/generated"
+)
+
+// describedSchema is a schema that only consists of its entries, like one that
+// was generated by an older build.
+type describedSchema struct {
+	name    string
+	entries []goschema.SchemaEntry
+}
+
+func (s *describedSchema) Fill([]goschema.SchemaEntry)      {}
+func (s *describedSchema) Describe() []goschema.SchemaEntry { return s.entries }
+func (s *describedSchema) ID() goschema.SchemaID            { return 0 }
+func (s *describedSchema) Fingerprint() uint64              { return 0 }
+func (s *describedSchema) Name() string                     { return s.name }
+func (s *describedSchema) GoType() string                   { return "" }
+
+// schemaDBOf returns a schema DB that holds the given schemata.
+func schemaDBOf(t *testing.T, schemata ...goschema.Schema) *goschema.SchemaDB {
+	var buf gobinary.WriteBuffer
+	dbWriter := goschema.MakeSchemaDBWriter(gobinary.NewStreamWriter(&buf))
+	for _, schema := range schemata {
+		dbWriter.RegisterSchema(schema)
+	}
+	dbWriter.Close()
+	schemaDB := goschema.MakeSchemaDB()
+	if err := schemaDB.Fill(bytes.NewReader(buf.Bytes())); err != nil {
+		t.Fatal(err)
+	}
+	return &schemaDB
+}
+
+func TestCompatibilityOfDeprecatedFields(t *testing.T) {
+	// an old version of Ranged wrote Legacy, which is deprecated now, and Gone,
+	// which has been removed
+	old := schemaDBOf(t, &describedSchema{name: generated.RangedSchemaName, entries: []goschema.SchemaEntry{
+		{Name: "Level", Type: goschema.Int32Type, Offset: 0},
+		{Name: "Legacy", Type: goschema.Int32Type, Offset: 4},
+		{Name: "Gone", Type: goschema.Int32Type, Offset: 8},
+	}})
+	expected := generated.RangedSchemaName + ".Gone: field removed"
+	check := func(issues []goschema.CompatIssue) {
+		t.Helper()
+		if len(issues) != 1 || issues[0].String() != expected {
+			t.Fatalf("got %v", issues)
+		}
+	}
+	check(goschema.CheckCompatibility(old, []goschema.Schema{generated.NewRangedSchema()}))
+	current := schemaDBOf(t, generated.NewRangedSchema())
+	if deprecated := current.DeprecatedEntries(0); len(deprecated) != 1 || deprecated[0].Name != "Legacy" {
+		t.Fatalf("deprecated entries are %v", deprecated)
+	}
+	check(goschema.CheckSchemaDBCompatibility(old, current))
+}
@@ -0,0 +1,317 @@
+package goschema
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// DefaultBlockSize is the number of uncompressed bytes per block used by
+// NewCompressedWriter when no block size is given.
+const DefaultBlockSize = 64 * 1024
+
+const compressedMagic = "GSZ1"
+
+// the footer consists of the index offset, the number of blocks, the block size
+// and the magic bytes
+const compressedFooterSize = 8 + 4 + 4 + len(compressedMagic)
+
+// each index entry holds the offset and compressed size of a block and its
+// uncompressed size
+const compressedIndexEntrySize = 8 + 4 + 4
+
+var ErrBlockFlushed = errors.New("goschema: cannot write to a compressed block that has already been flushed")
+var ErrInvalidCompressedStream = errors.New("goschema: invalid compressed stream")
+
+type compressedBlock struct {
+	offset         int64
+	compressedSize uint32
+	size           uint32
+}
+
+// CompressedWriter is an io.WriteSeeker that compresses its contents in blocks
+// of fixed size. It can be used as the stream below a SchemaWriter or a
+// SchemaDBWriter. Blocks are kept uncompressed in memory until Flush or Close
+// is called, so references can be patched as usual. After a call to Flush,
+// the flushed part of the stream must not be written to anymore; a good point
+// to call Flush is after each top-level SingleWrite. Writing to it fails with
+// ErrBlockFlushed, which a SchemaWriter reports from Err.
+// The output only ever grows at its end, so any io.Writer can be used as the
+// destination.
+type CompressedWriter struct {
+	output     io.Writer
+	compressor *flate.Writer
+	scratch    bytes.Buffer
+	blockSize  int
+	pending    []byte // uncompressed data starting at flushedOffset
+	flushed    int64  // logical offset up to which data has been compressed
+	position   int64  // logical offset of the next write
+	written    int64  // number of bytes written to output
+	index      []compressedBlock
+	closed     bool
+}
+
+// NewCompressedWriter creates a CompressedWriter writing to output. A blockSize
+// of 0 selects DefaultBlockSize, level is a compression level as understood by
+// compress/flate.
+func NewCompressedWriter(output io.Writer, blockSize, level int) (*CompressedWriter, error) {
+	if blockSize <= 0 {
+		blockSize = DefaultBlockSize
+	}
+	cw := &CompressedWriter{
+		output:    output,
+		blockSize: blockSize,
+	}
+	compressor, err := flate.NewWriter(&cw.scratch, level)
+	if err != nil {
+		return nil, err
+	}
+	cw.compressor = compressor
+	return cw, nil
+}
+
+func (cw *CompressedWriter) Write(p []byte) (int, error) {
+	if cw.closed {
+		return 0, errors.New("goschema: write to closed CompressedWriter")
+	}
+	if cw.position < cw.flushed {
+		return 0, ErrBlockFlushed
+	}
+	start := int(cw.position - cw.flushed)
+	end := start + len(p)
+	if end > len(cw.pending) {
+		if end > cap(cw.pending) {
+			grown := make([]byte, end, 2*end)
+			copy(grown, cw.pending)
+			cw.pending = grown
+		} else {
+			// the capacity beyond len may contain stale data from earlier blocks
+			tail := cw.pending[len(cw.pending):end]
+			for i := range tail {
+				tail[i] = 0
+			}
+			cw.pending = cw.pending[:end]
+		}
+	}
+	copy(cw.pending[start:end], p)
+	cw.position += int64(len(p))
+	return len(p), nil
+}
+
+func (cw *CompressedWriter) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += cw.position
+	case io.SeekEnd:
+		offset += cw.flushed + int64(len(cw.pending))
+	default:
+		return cw.position, fmt.Errorf("goschema: invalid whence %v", whence)
+	}
+	if offset < 0 {
+		return cw.position, fmt.Errorf("goschema: negative offset %v", offset)
+	}
+	cw.position = offset
+	return offset, nil
+}
+
+// Flush compresses all complete blocks that are pending and writes them to the
+// output.
+func (cw *CompressedWriter) Flush() error {
+	for len(cw.pending) >= cw.blockSize {
+		if err := cw.writeBlock(cw.pending[:cw.blockSize]); err != nil {
+			return err
+		}
+		n := copy(cw.pending, cw.pending[cw.blockSize:])
+		cw.pending = cw.pending[:n]
+	}
+	return nil
+}
+
+// Close flushes all pending data including the last incomplete block and
+// writes the block index. It does not close the underlying writer.
+func (cw *CompressedWriter) Close() error {
+	if cw.closed {
+		return nil
+	}
+	if err := cw.Flush(); err != nil {
+		return err
+	}
+	if len(cw.pending) > 0 {
+		if err := cw.writeBlock(cw.pending); err != nil {
+			return err
+		}
+		cw.pending = cw.pending[:0]
+	}
+	cw.closed = true
+
+	indexOffset := cw.written
+	buf := make([]byte, 0, len(cw.index)*compressedIndexEntrySize+compressedFooterSize)
+	for _, block := range cw.index {
+		buf = binary.LittleEndian.AppendUint64(buf, uint64(block.offset))
+		buf = binary.LittleEndian.AppendUint32(buf, block.compressedSize)
+		buf = binary.LittleEndian.AppendUint32(buf, block.size)
+	}
+	buf = binary.LittleEndian.AppendUint64(buf, uint64(indexOffset))
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(len(cw.index)))
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(cw.blockSize))
+	buf = append(buf, compressedMagic...)
+	n, err := cw.output.Write(buf)
+	cw.written += int64(n)
+	return err
+}
+
+func (cw *CompressedWriter) writeBlock(data []byte) error {
+	cw.scratch.Reset()
+	cw.compressor.Reset(&cw.scratch)
+	if _, err := cw.compressor.Write(data); err != nil {
+		return err
+	}
+	if err := cw.compressor.Close(); err != nil {
+		return err
+	}
+	block := compressedBlock{
+		offset:         cw.written,
+		compressedSize: uint32(cw.scratch.Len()),
+		size:           uint32(len(data)),
+	}
+	n, err := cw.output.Write(cw.scratch.Bytes())
+	cw.written += int64(n)
+	if err != nil {
+		return err
+	}
+	cw.index = append(cw.index, block)
+	cw.flushed += int64(len(data))
+	return nil
+}
+
+// CompressedReader is an io.ReadSeeker over the uncompressed contents of a
+// stream written by a CompressedWriter. Only the blocks that are actually
+// read from are decompressed, so it can be used as the stream below a
+// SchemaReader without decompressing the whole file.
+type CompressedReader struct {
+	source       io.ReaderAt
+	blockSize    int64
+	index        []compressedBlock
+	size         int64
+	position     int64
+	cachedBlock  int
+	block        []byte
+	decompressor io.ReadCloser
+}
+
+// NewCompressedReader reads the block index of the compressed stream of the
+// given size from source.
+func NewCompressedReader(source io.ReaderAt, size int64) (*CompressedReader, error) {
+	if size < int64(compressedFooterSize) {
+		return nil, ErrInvalidCompressedStream
+	}
+	footer := make([]byte, compressedFooterSize)
+	if _, err := source.ReadAt(footer, size-int64(compressedFooterSize)); err != nil {
+		return nil, err
+	}
+	if string(footer[16:]) != compressedMagic {
+		return nil, ErrInvalidCompressedStream
+	}
+	indexOffset := int64(binary.LittleEndian.Uint64(footer[0:8]))
+	numBlocks := int64(binary.LittleEndian.Uint32(footer[8:12]))
+	blockSize := int64(binary.LittleEndian.Uint32(footer[12:16]))
+	indexSize := numBlocks * compressedIndexEntrySize
+	if blockSize == 0 || indexOffset < 0 || indexOffset+indexSize != size-int64(compressedFooterSize) {
+		return nil, ErrInvalidCompressedStream
+	}
+
+	raw := make([]byte, indexSize)
+	if _, err := source.ReadAt(raw, indexOffset); err != nil {
+		return nil, err
+	}
+	cr := &CompressedReader{
+		source:      source,
+		blockSize:   blockSize,
+		index:       make([]compressedBlock, numBlocks),
+		cachedBlock: -1,
+	}
+	for i := range cr.index {
+		entry := raw[i*compressedIndexEntrySize:]
+		block := compressedBlock{
+			offset:         int64(binary.LittleEndian.Uint64(entry[0:8])),
+			compressedSize: binary.LittleEndian.Uint32(entry[8:12]),
+			size:           binary.LittleEndian.Uint32(entry[12:16]),
+		}
+		// Read locates blocks by dividing by the block size, so only the last
+		// block may be shorter
+		last := i == len(cr.index)-1
+		if (!last && int64(block.size) != blockSize) || (last && (block.size == 0 || int64(block.size) > blockSize)) ||
+			block.offset+int64(block.compressedSize) > indexOffset {
+			return nil, ErrInvalidCompressedStream
+		}
+		cr.index[i] = block
+		cr.size += int64(block.size)
+	}
+	return cr, nil
+}
+
+// Size returns the size of the uncompressed data.
+func (cr *CompressedReader) Size() int64 {
+	return cr.size
+}
+
+func (cr *CompressedReader) Read(p []byte) (int, error) {
+	read := 0
+	for read < len(p) {
+		if cr.position >= cr.size {
+			return read, io.EOF
+		}
+		blockIndex := int(cr.position / cr.blockSize)
+		if err := cr.loadBlock(blockIndex); err != nil {
+			return read, err
+		}
+		n := copy(p[read:], cr.block[cr.position-int64(blockIndex)*cr.blockSize:])
+		read += n
+		cr.position += int64(n)
+	}
+	return read, nil
+}
+
+func (cr *CompressedReader) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += cr.position
+	case io.SeekEnd:
+		offset += cr.size
+	default:
+		return cr.position, fmt.Errorf("goschema: invalid whence %v", whence)
+	}
+	if offset < 0 {
+		return cr.position, fmt.Errorf("goschema: negative offset %v", offset)
+	}
+	cr.position = offset
+	return offset, nil
+}
+
+func (cr *CompressedReader) loadBlock(blockIndex int) error {
+	if blockIndex == cr.cachedBlock {
+		return nil
+	}
+	block := cr.index[blockIndex]
+	compressed := io.NewSectionReader(cr.source, block.offset, int64(block.compressedSize))
+	if cr.decompressor == nil {
+		cr.decompressor = flate.NewReader(compressed)
+	} else if err := cr.decompressor.(flate.Resetter).Reset(compressed, nil); err != nil {
+		return err
+	}
+	if cap(cr.block) < int(block.size) {
+		cr.block = make([]byte, block.size)
+	}
+	cr.block = cr.block[:block.size]
+	cr.cachedBlock = -1
+	if _, err := io.ReadFull(cr.decompressor, cr.block); err != nil {
+		return fmt.Errorf("goschema: could not decompress block %v: %v", blockIndex, err)
+	}
+	cr.cachedBlock = blockIndex
+	return nil
+}
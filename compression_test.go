@@ -0,0 +1,96 @@
+package goschema
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/binary"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/chasingcarrots/gobinary"
+)
+
+func compress(t *testing.T, data []byte, blockSize int) []byte {
+	var out bytes.Buffer
+	cw, err := NewCompressedWriter(&out, blockSize, flate.BestSpeed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cw.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := cw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return out.Bytes()
+}
+
+func TestCompressedRoundTrip(t *testing.T) {
+	data := make([]byte, 100)
+	for i := range data {
+		data[i] = byte(i)
+	}
+	compressed := compress(t, data, 16)
+	cr, err := NewCompressedReader(bytes.NewReader(compressed), int64(len(compressed)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cr.Size() != int64(len(data)) {
+		t.Fatalf("size is %v instead of %v", cr.Size(), len(data))
+	}
+	for _, offset := range []int64{90, 3, 47, 0} {
+		if _, err := cr.Seek(offset, io.SeekStart); err != nil {
+			t.Fatal(err)
+		}
+		buf := make([]byte, 10)
+		if _, err := io.ReadFull(cr, buf); err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(buf, data[offset:offset+10]) {
+			t.Fatalf("read %v at offset %v", buf, offset)
+		}
+	}
+}
+
+func TestCompressedReaderRejectsShortBlocks(t *testing.T) {
+	compressed := compress(t, make([]byte, 40), 16)
+	footer := compressed[len(compressed)-compressedFooterSize:]
+	indexOffset := int(binary.LittleEndian.Uint64(footer))
+	// the uncompressed size of the first of three blocks
+	sizeOffset := indexOffset + 12
+	for _, size := range []uint32{8, 17} {
+		corrupt := append([]byte(nil), compressed...)
+		binary.LittleEndian.PutUint32(corrupt[sizeOffset:], size)
+		if _, err := NewCompressedReader(bytes.NewReader(corrupt), int64(len(corrupt))); err != ErrInvalidCompressedStream {
+			t.Fatalf("block size %v: got %v", size, err)
+		}
+	}
+}
+
+func TestSchemaWriterReportsFlushedBlocks(t *testing.T) {
+	var out bytes.Buffer
+	cw, err := NewCompressedWriter(&out, 4, flate.BestSpeed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writer := MakeSchemaWriter(nil, gobinary.MakeStreamWriterView(gobinary.NewStreamWriter(cw)))
+	writer.WriteUInt32(1)
+	if err := cw.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	writer.WriteUInt32(2)
+	if writer.Err() != nil {
+		t.Fatal(writer.Err())
+	}
+	// patching the flushed block fails the writer
+	writer.Seek(0, io.SeekStart)
+	writer.WriteUInt32(3)
+	if !errors.Is(writer.Err(), ErrBlockFlushed) {
+		t.Fatalf("got %v", writer.Err())
+	}
+	point := catalogPoint{X: 1}
+	if err := Marshal(&writer, &point); !errors.Is(err, ErrBlockFlushed) {
+		t.Fatalf("marshaled after the failure: %v", err)
+	}
+}
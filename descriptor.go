@@ -0,0 +1,68 @@
+package goschema
+
+import "fmt"
+
+// TypeDescriptor describes the serialized type of a field, including the types
+// of the elements of lists, maps and pointers.
+type TypeDescriptor struct {
+	Code   TypeCode        `json:"code"`
+	Schema string          `json:"schema,omitempty"` // name of the schema if Code is SchemaType
+	Key    *TypeDescriptor `json:"key,omitempty"`    // key type of maps
+	Elem   *TypeDescriptor `json:"elem,omitempty"`   // element type of lists and pointers, value type of maps
+}
+
+// Matches reports whether data described by one descriptor can be read as the
+// other. Nested schemata match regardless of their names since they are
+// matched field by field when they are read. A nil descriptor matches any other.
+func (d *TypeDescriptor) Matches(other *TypeDescriptor) bool {
+	if d == nil || other == nil {
+		return true
+	}
+	if d.Code != other.Code {
+		return false
+	}
+	if (d.Key == nil) != (other.Key == nil) || (d.Elem == nil) != (other.Elem == nil) {
+		return false
+	}
+	return d.Key.Matches(other.Key) && d.Elem.Matches(other.Elem)
+}
+
+var typeCodeNames = [NumTypeCodes]string{
+	SchemaType:  "schema",
+	MapType:     "map",
+	ListType:    "list",
+	UInt8Type:   "uint8",
+	UInt16Type:  "uint16",
+	UInt32Type:  "uint32",
+	UInt64Type:  "uint64",
+	UIntType:    "uint",
+	Int8Type:    "int8",
+	Int16Type:   "int16",
+	Int32Type:   "int32",
+	Int64Type:   "int64",
+	IntType:     "int",
+	Float32Type: "float32",
+	Float64Type: "float64",
+	BoolType:    "bool",
+	StringType:  "string",
+	PointerType: "pointer",
+}
+
+func (d *TypeDescriptor) String() string {
+	if d == nil {
+		return "unknown"
+	}
+	name := fmt.Sprintf("custom(%v)", uint8(d.Code))
+	if d.Code < NumTypeCodes {
+		name = typeCodeNames[d.Code]
+	}
+	switch {
+	case d.Code == SchemaType:
+		return fmt.Sprintf("%v %v", name, d.Schema)
+	case d.Key != nil:
+		return fmt.Sprintf("%v(%v, %v)", name, d.Key, d.Elem)
+	case d.Elem != nil:
+		return fmt.Sprintf("%v(%v)", name, d.Elem)
+	}
+	return name
+}
@@ -0,0 +1,13 @@
+package goschema
+
+import "reflect"
+
+// ReflectSchemaOf returns the schema that Marshal writes for the struct that v
+// points to.
+func ReflectSchemaOf(v interface{}) (Schema, error) {
+	rt, err := reflectTypeOf(reflect.TypeOf(v).Elem())
+	if err != nil {
+		return nil, err
+	}
+	return rt.native, nil
+}
@@ -0,0 +1,68 @@
+package goschema
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/chasingcarrots/gobinary"
+)
+
+var ErrNonSequentialWrite = errors.New("goschema: forward writers only support writing at the end of the stream")
+
+// forwardStream adapts an io.Writer to the io.WriteSeeker that gobinary's
+// stream writers require. Seeking only moves the position; writing is only
+// allowed at the end of the data written so far. The first error is retained
+// and returned for all subsequent writes.
+type forwardStream struct {
+	output   io.Writer
+	position int64
+	end      int64
+	err      error
+}
+
+func (fs *forwardStream) Write(p []byte) (int, error) {
+	if fs.err != nil {
+		return 0, fs.err
+	}
+	if fs.position != fs.end {
+		fs.err = ErrNonSequentialWrite
+		return 0, fs.err
+	}
+	n, err := fs.output.Write(p)
+	fs.position += int64(n)
+	fs.end = fs.position
+	if err != nil {
+		fs.err = err
+	}
+	return n, err
+}
+
+func (fs *forwardStream) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += fs.position
+	case io.SeekEnd:
+		offset += fs.end
+	default:
+		return fs.position, fmt.Errorf("goschema: invalid whence %v", whence)
+	}
+	if offset < 0 {
+		return fs.position, fmt.Errorf("goschema: negative offset %v", offset)
+	}
+	fs.position = offset
+	return offset, nil
+}
+
+// MakeForwardSchemaWriter creates a SchemaWriter that writes to an io.Writer
+// that does not need to support seeking, such as a network connection or a
+// compressing writer. Generated schemata compute the size of all objects
+// before writing them in this case, so the data is identical to that written
+// by a regular SchemaWriter. Errors of the output are reported by Err.
+func MakeForwardSchemaWriter(schemaData *SchemaDBWriter, output io.Writer) SchemaWriter {
+	stream := &forwardStream{output: output}
+	writer := MakeSchemaWriter(schemaData, gobinary.MakeStreamWriterView(gobinary.NewStreamWriter(stream)))
+	writer.forward = stream
+	return writer
+}
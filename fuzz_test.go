@@ -0,0 +1,119 @@
+package goschema_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/chasingcarrots/gobinary"
+	"github.com/chasingcarrots/goschema"
+	"github.com/chasingcarrots/goschema/generator/testdata/fixtures"
+	"github.com/chasingcarrots/goschema/generator/testdata/generated"
+)
+
+// writeFixtures writes values of the fixtures of the generator with the code
+// generated for them and returns the schema DB and the data. Together, they
+// contain numbers of every type, inline structs, lists and maps of objects,
+// pointers to collections, renamed and constrained fields and fields that can
+// be widened.
+func writeFixtures(checksums bool) (schemaDB, data []byte) {
+	var dbBuf gobinary.WriteBuffer
+	dbWriter := goschema.MakeSchemaDBWriter(gobinary.NewStreamWriter(&dbBuf))
+	writer := goschema.MakeByteSchemaWriter(&dbWriter, nil)
+	if checksums {
+		writer.EnableChecksums()
+	}
+	doc := fixtures.Document{
+		Title:    "doc",
+		Main:     &fixtures.Tag{Name: "main"},
+		Tags:     []fixtures.Tag{{Name: "a"}},
+		ByName:   map[string]fixtures.Tag{"b": {Name: "b"}},
+		Sections: []fixtures.Section{{Tags: []fixtures.Tag{{Name: "c"}}}},
+	}
+	generated.WriteDocumentSchema(&writer).SingleWrite(&writer, &doc, 0)
+	node := fixtures.Node{Value: 1, Children: []fixtures.Node{{Value: 2, Children: []fixtures.Node{{Value: 3}}}}}
+	generated.WriteNodeSchema(&writer).SingleWrite(&writer, &node, 0)
+	catalog := sampleCatalog()
+	generated.WriteCatalogSchema(&writer).SingleWrite(&writer, &catalog, 0)
+	counts := []int32{1, 2}
+	lookup := map[string]int16{"a": 3}
+	scalars := fixtures.Scalars{
+		Flag:     true,
+		Int16:    -16,
+		UInt64:   1 << 63,
+		Float32:  0.5,
+		Text:     "text",
+		Position: fixtures.Vector2{X: 1, Y: 2},
+		Path:     []fixtures.Vector2{{X: 3}},
+		Origin:   &fixtures.Vector2{Y: 4},
+		Counts:   &counts,
+		Lookup:   &lookup,
+	}
+	generated.WriteScalarsSchema(&writer).SingleWrite(&writer, &scalars, 0)
+	limits := fixtures.Limits{Small: 1, Count: 10, Offset: -1, Ratio: 0.5, Weight: 1, Profile: fixtures.Profile{Name: "p"}}
+	generated.WriteLimitsSchema(&writer).SingleWrite(&writer, &limits, 0)
+	dbWriter.Close()
+	return dbBuf.Bytes(), writer.Bytes()
+}
+
+// readFixtures reads values like they are written by writeFixtures until
+// reading fails.
+func readFixtures(reader *goschema.SchemaReader) {
+	var doc fixtures.Document
+	if generated.ReadDocumentSchema(reader).SingleRead(reader, &doc, 0) != nil {
+		return
+	}
+	var node fixtures.Node
+	if generated.ReadNodeSchema(reader).SingleRead(reader, &node, 0) != nil {
+		return
+	}
+	var catalog fixtures.Catalog
+	if generated.ReadCatalogSchema(reader).SingleRead(reader, &catalog, 0) != nil {
+		return
+	}
+	var scalars fixtures.Scalars
+	if generated.ReadScalarsSchema(reader).SingleRead(reader, &scalars, 0) != nil {
+		return
+	}
+	var limits fixtures.Limits
+	generated.ReadLimitsSchema(reader).SingleRead(reader, &limits, 0)
+}
+
+func FuzzSingleRead(f *testing.F) {
+	for _, checksums := range []bool{false, true} {
+		schemaDB, data := writeFixtures(checksums)
+		f.Add(schemaDB, data)
+		f.Add(schemaDB, data[:len(data)/2])
+	}
+	f.Fuzz(func(t *testing.T, dbData, data []byte) {
+		schemaDB := goschema.MakeSchemaDB()
+		if err := schemaDB.Fill(bytes.NewReader(dbData)); err != nil {
+			return
+		}
+		reader := goschema.MakeByteSchemaReader(&schemaDB, data)
+		reader.SetReadLimits(goschema.ReadLimits{MaxDepth: 32})
+		readFixtures(&reader)
+
+		// without limits, lengths are only bounded by the size of the stream
+		streamReader := goschema.MakeSchemaReader(&schemaDB, gobinary.MakeStreamReaderView(gobinary.NewStreamReader(bytes.NewReader(data))))
+		readFixtures(&streamReader)
+	})
+}
+
+func FuzzSchemaDBFill(f *testing.F) {
+	for _, checksums := range []bool{false, true} {
+		schemaDB, _ := writeFixtures(checksums)
+		f.Add(schemaDB)
+		f.Add(schemaDB[:len(schemaDB)/2])
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		schemaDB := goschema.MakeSchemaDB()
+		if err := schemaDB.Fill(bytes.NewReader(data)); err != nil {
+			return
+		}
+		for i := 0; i < schemaDB.NumSchemata(); i++ {
+			schemaDB.SchemaName(i)
+			schemaDB.FindSchema(i)
+			schemaDB.DeprecatedEntries(i)
+		}
+	})
+}
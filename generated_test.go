@@ -0,0 +1,180 @@
+package goschema_test
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/chasingcarrots/gobinary"
+	"github.com/chasingcarrots/goschema"
+	"github.com/chasingcarrots/goschema/generator/testdata/fixtures"
+	"github.com/chasingcarrots/goschema/generator/testdata/generated"
+)
+
+// sampleCatalog returns a catalog that uses every field. Its maps have a single
+// entry each, since the order in which maps are written is random.
+func sampleCatalog() fixtures.Catalog {
+	return fixtures.Catalog{
+		Title:    "catalog",
+		Featured: &fixtures.Item{Name: "featured", Count: 1, Weight: 2},
+		Items:    []fixtures.Item{{Name: "a", Count: 2}, {Name: "b", Weight: -1}},
+		Links:    []*fixtures.Item{{Name: "c"}, nil},
+		ByName:   map[string]fixtures.Item{"d": {Name: "d", Count: 3}},
+		Groups:   map[int32][]fixtures.Item{7: {{Name: "e"}, {Name: "f"}}},
+		Labels:   []string{"x", ""},
+		Hidden:   5,
+	}
+}
+
+// writeWith writes values with write using the byte slice, stream or forward
+// writer and returns the schema DB and the data.
+func writeWith(kind string, checksums bool, write func(writer *goschema.SchemaWriter)) (schemaDB, data []byte) {
+	var dbBuf, buf gobinary.WriteBuffer
+	dbWriter := goschema.MakeSchemaDBWriter(gobinary.NewStreamWriter(&dbBuf))
+	var writer goschema.SchemaWriter
+	switch kind {
+	case "bytes":
+		writer = goschema.MakeByteSchemaWriter(&dbWriter, nil)
+	case "stream":
+		writer = goschema.MakeSchemaWriter(&dbWriter, gobinary.MakeStreamWriterView(gobinary.NewStreamWriter(&buf)))
+	case "forward":
+		writer = goschema.MakeForwardSchemaWriter(&dbWriter, &buf)
+	}
+	if checksums {
+		writer.EnableChecksums()
+	}
+	write(&writer)
+	dbWriter.Close()
+	if kind == "bytes" {
+		return dbBuf.Bytes(), writer.Bytes()
+	}
+	return dbBuf.Bytes(), buf.Bytes()
+}
+
+func TestMarshalMatchesGeneratedCode(t *testing.T) {
+	catalog := sampleCatalog()
+	node := fixtures.Node{Value: 1, Children: []fixtures.Node{{Value: 2}, {Value: 3, Children: []fixtures.Node{{Value: 4}}}}}
+	for _, kind := range []string{"bytes", "stream", "forward"} {
+		for _, checksums := range []bool{false, true} {
+			generatedDB, generatedData := writeWith(kind, checksums, func(writer *goschema.SchemaWriter) {
+				generated.WriteCatalogSchema(writer).SingleWrite(writer, &catalog, 0)
+				generated.WriteNodeSchema(writer).SingleWrite(writer, &node, 0)
+			})
+			marshalDB, marshalData := writeWith(kind, checksums, func(writer *goschema.SchemaWriter) {
+				if err := goschema.Marshal(writer, &catalog); err != nil {
+					t.Fatal(err)
+				}
+				if err := goschema.Marshal(writer, &node); err != nil {
+					t.Fatal(err)
+				}
+			})
+			if !bytes.Equal(generatedData, marshalData) {
+				t.Errorf("%v writer, checksums %v: data differs:\n%v\n%v", kind, checksums, generatedData, marshalData)
+			}
+			if !bytes.Equal(generatedDB, marshalDB) {
+				t.Errorf("%v writer, checksums %v: schema DB differs:\n%v\n%v", kind, checksums, generatedDB, marshalDB)
+			}
+		}
+	}
+
+	for _, test := range []struct {
+		generated goschema.Schema
+		value     interface{}
+	}{
+		{generated.NewCatalogSchema(), &fixtures.Catalog{}},
+		{generated.NewItemSchema(), &fixtures.Item{}},
+		{generated.NewNodeSchema(), &fixtures.Node{}},
+	} {
+		schema, err := goschema.ReflectSchemaOf(test.value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(test.generated.Describe(), schema.Describe()) {
+			t.Errorf("%v: Describe differs:\n%#v\n%#v", test.generated.Name(), test.generated.Describe(), schema.Describe())
+		}
+		if test.generated.Name() != schema.Name() || test.generated.GoType() != schema.GoType() || test.generated.Fingerprint() != schema.Fingerprint() {
+			t.Errorf("%v: schema is %v, %v, %x instead of %v, %v, %x", test.generated.Name(),
+				schema.Name(), schema.GoType(), schema.Fingerprint(),
+				test.generated.Name(), test.generated.GoType(), test.generated.Fingerprint())
+		}
+	}
+}
+
+func TestViews(t *testing.T) {
+	catalog := sampleCatalog()
+	node := fixtures.Node{Value: 1}
+	for _, checksums := range []bool{false, true} {
+		schemaDBData, data := writeWith("bytes", checksums, func(writer *goschema.SchemaWriter) {
+			generated.WriteCatalogSchema(writer).SingleWrite(writer, &catalog, 0)
+			generated.WriteNodeSchema(writer).SingleWrite(writer, &node, 0)
+		})
+		schemaDB := goschema.MakeSchemaDB()
+		if err := schemaDB.Fill(bytes.NewReader(schemaDBData)); err != nil {
+			t.Fatal(err)
+		}
+		for _, kind := range []string{"bytes", "stream"} {
+			reader := goschema.MakeByteSchemaReader(&schemaDB, data)
+			if kind == "stream" {
+				reader = goschema.MakeSchemaReader(&schemaDB, gobinary.MakeStreamReaderView(gobinary.NewStreamReader(bytes.NewReader(data))))
+			}
+			schema := generated.ReadCatalogSchema(&reader)
+			// reading a single field leaves the reader at the object
+			if name, err := schema.GetName(&reader, 0); err != nil || name != catalog.Title {
+				t.Fatalf("%v reader, checksums %v: GetName = %q, %v", kind, checksums, name, err)
+			}
+			view, err := schema.NakedView(&reader)
+			if err != nil {
+				t.Fatalf("%v reader, checksums %v: %v", kind, checksums, err)
+			}
+			// fields are read in any order
+			labels, err := view.GetLabels(0)
+			if err != nil || !reflect.DeepEqual(labels, catalog.Labels) {
+				t.Errorf("%v reader, checksums %v: GetLabels = %q, %v", kind, checksums, labels, err)
+			}
+			items, err := view.GetItems(0)
+			if err != nil || !reflect.DeepEqual(items, catalog.Items) {
+				t.Errorf("%v reader, checksums %v: GetItems = %+v, %v", kind, checksums, items, err)
+			}
+			featured, err := view.GetFeatured(0)
+			if err != nil || !reflect.DeepEqual(featured, catalog.Featured) {
+				t.Errorf("%v reader, checksums %v: GetFeatured = %+v, %v", kind, checksums, featured, err)
+			}
+			// the view moved the reader past the object
+			var result fixtures.Node
+			if err := generated.ReadNodeSchema(&reader).SingleRead(&reader, &result, 0); err != nil || result.Value != node.Value {
+				t.Errorf("%v reader, checksums %v: read %+v, %v", kind, checksums, result, err)
+			}
+		}
+	}
+}
+
+func TestViewsVerifyChecksums(t *testing.T) {
+	catalog := sampleCatalog()
+	for _, checksums := range []bool{false, true} {
+		schemaDBData, data := writeWith("bytes", checksums, func(writer *goschema.SchemaWriter) {
+			generated.WriteCatalogSchema(writer).SingleWrite(writer, &catalog, 0)
+		})
+		schemaDB := goschema.MakeSchemaDB()
+		if err := schemaDB.Fill(bytes.NewReader(schemaDBData)); err != nil {
+			t.Fatal(err)
+		}
+		// corrupt the labels, which are not requested from the view
+		data[bytes.IndexByte(data, 'x')] = 'y'
+		reader := goschema.MakeByteSchemaReader(&schemaDB, data)
+		view, err := generated.ReadCatalogSchema(&reader).NakedView(&reader)
+		var checksumErr *goschema.ChecksumError
+		if checksums {
+			if !errors.As(err, &checksumErr) {
+				t.Errorf("checksummed view failed with %v", err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if name, err := view.GetName(0); err != nil || name != catalog.Title {
+			t.Errorf("GetName = %q, %v", name, err)
+		}
+	}
+}
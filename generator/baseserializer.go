@@ -106,6 +106,10 @@ func (b *BaseSerializer) MakeWritingCode(context *Context, ptrValueTarget bool,
 	return buf.String()
 }
 
+func (b *BaseSerializer) MakeSizingCode(context *Context, ptrValueTarget bool, target Target, sizeName, valueName string) string {
+	return makeFixedSizingCode(sizeName, b.SizeOf(context, target))
+}
+
 func (b *BaseSerializer) SizeOf(*Context, Target) uint32 {
 	return uint32(b.Type.Size())
 }
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"reflect"
+	"sort"
 	"strings"
 	"text/template"
 
@@ -15,7 +16,13 @@ type SchemaMetaData struct {
 	Name                 string
 	ID                   int
 	HeaderSize           uint32
+	Fingerprint          uint64
+	Entries              []goschema.SchemaEntry
+	DeprecatedEntries    []goschema.SchemaEntry // fields that are read, but not written
 	Imports              map[string]struct{}
+	importAliases        map[string]string // names of the packages imported by default funcs by path
+	output               *bytes.Buffer
+	testOutput           *bytes.Buffer
 	ready, inPreparation bool
 }
 
@@ -30,11 +37,24 @@ type Context struct {
 	tokenCounter int
 
 	schemaMetaData map[reflect.Type]*SchemaMetaData
+	schemata       []*SchemaMetaData // in the order in which they were requested
 	schemaTemplate *template.Template
 	schemaStack    []*SchemaMetaData
+	fingerprints   map[reflect.Type]uint64
 
 	writeMethod, readMethod   *template.Template
+	getMethod                 *template.Template
 	writeContext, readContext reflect.Type
+
+	lockFilePath string
+	acknowledged map[string]bool
+	defaultFuncs map[string]reflect.Type // by qualified name
+
+	generateTests bool
+
+	// err is the first error of generating a schema that was requested while
+	// generating another one
+	err error
 }
 
 func NewContext(outputWriter SchemaOutputWriter, packagePath, schemaTemplatePath string, writeContext, readContext reflect.Type) *Context {
@@ -42,21 +62,26 @@ func NewContext(outputWriter SchemaOutputWriter, packagePath, schemaTemplatePath
 		schemaTemplate: template.Must(template.ParseFiles(schemaTemplatePath)),
 		writeMethod:    template.Must(template.New("WriteMethod").Parse(writingMethodSchema)),
 		readMethod:     template.Must(template.New("ReadMethod").Parse(readingMethodSchema)),
+		getMethod:      template.Must(template.New("GetMethod").Parse(getMethodSchema)),
 		outputWriter:   outputWriter,
 		packagePath:    packagePath,
 		schemaMetaData: make(map[reflect.Type]*SchemaMetaData),
+		fingerprints:   make(map[reflect.Type]uint64),
+		defaultFuncs:   make(map[string]reflect.Type),
 		writeContext:   writeContext,
 		readContext:    readContext,
 	}
 }
 
 func (c *Context) RequestSchema(typ reflect.Type, name string) {
-	c.schemaMetaData[typ] = &SchemaMetaData{
+	data := &SchemaMetaData{
 		Type:    typ,
 		Name:    name,
 		ID:      len(c.schemaMetaData),
 		Imports: make(map[string]struct{}),
 	}
+	c.schemaMetaData[typ] = data
+	c.schemata = append(c.schemata, data)
 }
 
 func (c *Context) AddDefaultSerializers() {
@@ -89,16 +114,68 @@ func (c *Context) Generate() error {
 	for _, s := range c.serializers {
 		s.Initialize(c)
 	}
-	for _, v := range c.schemaMetaData {
-		if !v.ready {
+	// the schemata are generated in a fixed order so that the IDs of the
+	// schemata that are requested along the way do not change between runs
+	for i := 0; i < len(c.schemata); i++ {
+		v := c.schemata[i]
+		if c.schemaMetaData[v.Type] == v && !v.ready {
 			if err := c.generateSchema(v); err != nil {
 				return err
 			}
+			if c.err != nil {
+				return c.err
+			}
+		}
+	}
+	if c.lockFilePath != "" {
+		if err := c.updateLockFile(); err != nil {
+			return err
+		}
+	}
+	// the output is only written once all schemata have been checked
+	for _, data := range c.sortedSchemata() {
+		if data.output != nil {
+			if err := c.outputWriter.Write(data.Name, data.output); err != nil {
+				return err
+			}
+			data.output = nil
+		}
+		if data.testOutput != nil {
+			if err := c.outputWriter.Write(data.Name+"_schema_test", data.testOutput); err != nil {
+				return err
+			}
+			data.testOutput = nil
 		}
 	}
 	return nil
 }
 
+// importSpecs returns the import declarations for the given imports in a fixed
+// order. Imports are import paths or, if they are imported under a different
+// name, complete import declarations.
+func importSpecs(imports map[string]struct{}) []string {
+	specs := make([]string, 0, len(imports))
+	for k := range imports {
+		if strings.HasSuffix(k, `"`) {
+			specs = append(specs, k)
+		} else {
+			specs = append(specs, `"`+k+`"`)
+		}
+	}
+	sort.Strings(specs)
+	return specs
+}
+
+// sortedSchemata returns the meta data of all schemata sorted by name.
+func (c *Context) sortedSchemata() []*SchemaMetaData {
+	schemata := make([]*SchemaMetaData, 0, len(c.schemaMetaData))
+	for _, data := range c.schemaMetaData {
+		schemata = append(schemata, data)
+	}
+	sort.Slice(schemata, func(i, j int) bool { return schemata[i].Name < schemata[j].Name })
+	return schemata
+}
+
 func (c *Context) UniqueToken() string {
 	token := c.tokenCounter
 	c.tokenCounter++
@@ -145,19 +222,36 @@ func (c *Context) GetSchema(typ reflect.Type) *SchemaMetaData {
 		data = c.schemaMetaData[typ]
 	}
 	if !data.inPreparation && !data.ready {
-		c.generateSchema(data)
+		if err := c.generateSchema(data); err != nil && c.err == nil {
+			c.err = err
+		}
 	}
 	return data
 }
 
 type schemaField struct {
-	Name      string            // name used for serialization
-	FieldName string            // name of the field in the struct
-	Offset    uint32            // offset of the field in the schema
-	TypeCode  goschema.TypeCode // typecode in the schema
-	Reference string            // "&" when writing should proceed by pointer
+	Name              string                   // name used for serialization
+	FieldName         string                   // name of the field in the struct
+	Offset            uint32                   // offset of the field in the schema
+	TypeCode          goschema.TypeCode        // typecode in the schema
+	Descriptor        string                   // code for the type descriptor in the schema
+	Type              *goschema.TypeDescriptor // type descriptor of the field
+	Aliases           []string                 // previous names of the field
+	Required          bool                     // whether the field must be present when reading
+	Widen             string                   // "Int", "UInt" or "Float" if narrower numbers can be read
+	Reference         string                   // "&" when writing should proceed by pointer
+	InPlace           bool                     // whether the field is stored in the header
+	SizeCode          string                   // adds the size of referenced data to "size"
+	ReferenceSizeCode string                   // adds the size of referenced data to "reference"
+	Deprecated        bool                     // whether the field is only read, but not written
+	Index             int                      // index among the written or among the deprecated fields
+	Nested            bool                     // whether the field may contain other objects
+	DefaulterType     string                   // type of the field if it implements SchemaDefaulter
+	BeforeWriteCode   string                   // runs the BeforeSchemaWrite hooks of objects in the field
 }
 
+var schemaDefaulterType = reflect.TypeOf((*goschema.SchemaDefaulter)(nil)).Elem()
+
 const writingMethodSchema = `func (schema *{{ .SchemaName }}Schema) Write{{ .Name }}(writer *goschema.SchemaWriter, value {{ .WritingType }}, context {{ .WritingContextType }}) {
 	offset := writer.Offset()
 	writer.Seek(int64(schema.{{ .Name }}Offset), io.SeekStart)
@@ -171,20 +265,40 @@ const writingMethodSchema = `func (schema *{{ .SchemaName }}Schema) Write{{ .Nam
 {{- end -}}
 }
 
+func (schema *{{ .SchemaName }}Schema) forwardWrite{{ .Name }}(writer *goschema.SchemaWriter, value {{ .WritingType }}, context {{ .WritingContextType }}) {
+	{{ .WriteCode }}
+}
+
 `
 
-const readingMethodSchema = `func (schema *{{ .SchemaName }}Schema) Read{{ .Name }}Into(reader *goschema.SchemaReader, value *{{ .ReadingType }}, context {{ .ReadingContextType }}) {
+const readingMethodSchema = `func (schema *{{ .SchemaName }}Schema) Read{{ .Name }}Into(reader *goschema.SchemaReader, value *{{ .ReadingType }}, context {{ .ReadingContextType }}) error {
 	if schema.{{ .Name }}Offset == -1 {
+{{- if .Required }}
+		return &goschema.MissingFieldError{Schema: "{{ .SchemaName }}", Field: "{{ .Name }}"}
+{{- else }}
 {{- if .Default }}
-		*value = {{ .ReadingType }}({{ .Default }})
+		*value = {{ .Default }}
+{{- else if .KeepMissing }}
+		// keep the value set by SetSchemaDefaults
 {{- else }}
 		var tmp {{ .ReadingType }}
+{{- if .Defaulter }}
+		tmp.SetSchemaDefaults()
+{{- end }}
 		*value = tmp
 {{- end }}
-		return
+		return nil
+{{- end }}
 	}
 	offset := reader.Offset()
 	reader.Seek(int64(schema.{{ .Name }}Offset), io.SeekStart)
+{{- if .Widen }}
+	if schema.{{ .Name }}Type != goschema.TypeCode({{ .TypeCode }}) {
+		*value = {{ .ReadingType }}(reader.ReadWidened{{ .Widen }}(schema.{{ .Name }}Type))
+		reader.Seek(offset, io.SeekStart)
+		return reader.Err()
+	}
+{{- end }}
 {{- if .InPlace -}}
 {{ else }}
 	fieldOffset := reader.ReadUInt32()
@@ -192,6 +306,32 @@ const readingMethodSchema = `func (schema *{{ .SchemaName }}Schema) Read{{ .Name
 {{- end }}
 	{{ .ReadCode }}
 	reader.Seek(offset, io.SeekStart)
+	return reader.Err()
+}
+
+`
+
+const getMethodSchema = `func (view {{ .SchemaName }}View) Get{{ .Name }}(context {{ .ReadingContextType }}) ({{ .ReadingType }}, error) {
+	var value {{ .ReadingType }}
+	reader := view.reader
+	originalBase := reader.Base()
+	offset := reader.GlobalOffset()
+	reader.View(reader.Local(view.base))
+	err := view.schema.Read{{ .Name }}Into(reader, &value, context)
+	reader.View(reader.Local(originalBase))
+	reader.Seek(reader.Local(offset), io.SeekStart)
+	return value, err
+}
+
+func (schema *{{ .SchemaName }}Schema) Get{{ .Name }}(reader *goschema.SchemaReader, context {{ .ReadingContextType }}) ({{ .ReadingType }}, error) {
+	offset := reader.Offset()
+	view, err := schema.NakedView(reader)
+	reader.Seek(offset, io.SeekStart)
+	if err != nil {
+		var value {{ .ReadingType }}
+		return value, err
+	}
+	return view.Get{{ .Name }}(context)
 }
 
 `
@@ -199,10 +339,35 @@ const readingMethodSchema = `func (schema *{{ .SchemaName }}Schema) Read{{ .Name
 func (c *Context) generateSchema(data *SchemaMetaData) error {
 	data.inPreparation = true
 	c.schemaStack = append(c.schemaStack, data)
+	defer func() {
+		c.schemaStack = c.schemaStack[0 : len(c.schemaStack)-1]
+		data.inPreparation = false
+	}()
+	// tokens are counted per schema so that the output of a schema does not
+	// depend on the schemata generated before it
+	tokenCounter := c.tokenCounter
+	c.tokenCounter = 0
+	defer func() { c.tokenCounter = tokenCounter }()
 	size := uint32(0)
+	hasReferences := false
 	n := data.Type.NumField()
 	schemaFields := make([]schemaField, 0, n)
-
+	var deprecatedFields []schemaField
+	var schemaConstraints []constraint
+	validateWrites := false
+
+	hasDefaulter := reflect.PtrTo(data.Type).Implements(schemaDefaulterType)
+	beforeWrite, err := findHook(data.Type, "BeforeSchemaWrite", c.writeContext, false)
+	if err != nil {
+		return err
+	}
+	afterRead, err := findHook(data.Type, "AfterSchemaRead", c.readContext, true)
+	if err != nil {
+		return err
+	}
+	if err := checkMapKeyHooks(data.Type, make(map[reflect.Type]bool)); err != nil {
+		return fmt.Errorf("schema %v: %v", data.Name, err)
+	}
 	writingContextType := c.GetTypeName(c.writeContext)
 	readingContextType := c.GetTypeName(c.readContext)
 
@@ -232,73 +397,162 @@ func (c *Context) generateSchema(data *SchemaMetaData) error {
 			reference = "&"
 		}
 
-		defaultValue := tag(field.Tag, "schemaDefault", "")
+		defaultValue, err := c.defaultCode(data.Type, field)
+		if err != nil {
+			return err
+		}
+		_, required := field.Tag.Lookup("schemaRequired")
+		if required && defaultValue != "" {
+			return fmt.Errorf("field %v of %v is required and has a default value", field.Name, data.Type.String())
+		}
+		_, deprecated := field.Tag.Lookup("schemaDeprecated")
+		if required && deprecated {
+			return fmt.Errorf("field %v of %v is required and deprecated", field.Name, data.Type.String())
+		}
+		widen := widenMethod(serializer, target)
+		descriptor := c.typeDescriptor(target)
+		fieldConstraints, err := constraints(data.Type, field, serializedName)
+		if err != nil {
+			return err
+		}
+		for i := range fieldConstraints {
+			// deprecated fields are not written, so they are only validated
+			// when they are read
+			fieldConstraints[i].Deprecated = deprecated
+			validateWrites = validateWrites || !deprecated
+		}
+		schemaConstraints = append(schemaConstraints, fieldConstraints...)
+		var aliases []string
+		if aliasTag := tag(field.Tag, "schemaAlias", ""); aliasTag != "" {
+			aliases = strings.Split(aliasTag, ",")
+		}
 		readingType := c.GetTypeName(field.Type)
+		defaulter := field.Type.Kind() != reflect.Ptr && reflect.PtrTo(field.Type).Implements(schemaDefaulterType)
 		isInPlace := "yes"
 		variableSize := serializer.IsVariableSize(c, target)
+		sizeCode := ""
+		referenceSizeCode := ""
 		if variableSize {
 			isInPlace = ""
 		}
+		if variableSize && !deprecated {
+			sizeCode = serializer.MakeSizingCode(c, false, target, "size", "value."+field.Name)
+			referenceSizeCode = serializer.MakeSizingCode(c, false, target, "reference", "value."+field.Name)
+		}
 
-		c.writeMethod.Execute(&methodBuf,
+		// deprecated fields are read from old data, but never written
+		if !deprecated {
+			c.writeMethod.Execute(&methodBuf,
+				Lookup{
+					"SchemaName":         data.Name,
+					"Name":               serializedName,
+					"WritingType":        writingType,
+					"WritingContextType": writingContextType,
+					"WriteCode":          writeCode,
+					"InPlace":            isInPlace,
+				},
+			)
+		}
+		c.readMethod.Execute(&methodBuf,
 			Lookup{
 				"SchemaName":         data.Name,
 				"Name":               serializedName,
-				"WritingType":        writingType,
-				"WritingContextType": writingContextType,
-				"WriteCode":          writeCode,
+				"ReadingType":        readingType,
+				"ReadCode":           readCode,
+				"Default":            defaultValue,
+				"Required":           required,
+				"KeepMissing":        hasDefaulter,
+				"Defaulter":          defaulter,
+				"TypeCode":           serializer.TypeCode(c, target),
+				"Widen":              widen,
+				"ReadingContextType": readingContextType,
 				"InPlace":            isInPlace,
 			},
 		)
-		c.readMethod.Execute(&methodBuf,
+		c.getMethod.Execute(&methodBuf,
 			Lookup{
 				"SchemaName":         data.Name,
 				"Name":               serializedName,
 				"ReadingType":        readingType,
-				"ReadCode":           readCode,
-				"Default":            defaultValue,
 				"ReadingContextType": readingContextType,
-				"InPlace":            isInPlace,
 			},
 		)
 
-		schemaFields = append(schemaFields,
-			schemaField{
-				Name:      serializedName,
-				FieldName: field.Name,
-				Offset:    size,
-				TypeCode:  serializer.TypeCode(c, target),
-				Reference: reference,
-			},
-		)
+		fieldData := schemaField{
+			Name:              serializedName,
+			FieldName:         field.Name,
+			Offset:            size,
+			TypeCode:          serializer.TypeCode(c, target),
+			Descriptor:        descriptorCode(descriptor),
+			Type:              descriptor,
+			Aliases:           aliases,
+			Required:          required,
+			Widen:             widen,
+			Reference:         reference,
+			InPlace:           !variableSize,
+			SizeCode:          sizeCode,
+			ReferenceSizeCode: referenceSizeCode,
+			Deprecated:        deprecated,
+			Nested:            containsSchema(descriptor),
+		}
+		if defaulter {
+			fieldData.DefaulterType = readingType
+		}
+		if !deprecated {
+			fieldData.BeforeWriteCode = c.MakeBeforeWriteCode(false, target, "value."+field.Name)
+		}
+		if deprecated {
+			fieldData.Index = len(deprecatedFields)
+			deprecatedFields = append(deprecatedFields, fieldData)
+			continue
+		}
+		fieldData.Index = len(schemaFields)
+		schemaFields = append(schemaFields, fieldData)
 
 		if variableSize {
+			hasReferences = true
 			size += goschema.ReferenceSize
 		} else {
 			size += serializer.SizeOf(c, target)
 		}
 	}
 	data.HeaderSize = size
+	readFields := append(append([]schemaField(nil), schemaFields...), deprecatedFields...)
+	if err := checkAliases(readFields); err != nil {
+		return fmt.Errorf("schema %v: %v", data.Name, err)
+	}
+	data.Fingerprint, _ = c.fingerprint(data.Type, nil)
+	data.Entries = makeEntries(schemaFields)
+	data.DeprecatedEntries = makeEntries(deprecatedFields)
 
 	targetTypeName := c.GetTypeName(data.Type)
-	var imports []string
-	for k := range data.Imports {
-		imports = append(imports, `"`+k+`"`)
-	}
+	imports := importSpecs(data.Imports)
 
 	var buf bytes.Buffer
 	c.schemaTemplate.Execute(&buf,
 		Lookup{
-			"SchemaName":         data.Name,
-			"SchemaSize":         data.HeaderSize,
-			"Fields":             schemaFields,
-			"NumFields":          len(schemaFields),
-			"WritingContextType": writingContextType,
-			"ReadingContextType": readingContextType,
-			"TargetType":         targetTypeName,
-			"Imports":            imports,
-			"Package":            c.packageName(),
-			"ID":                 data.ID,
+			"SchemaName":          data.Name,
+			"SchemaSize":          data.HeaderSize,
+			"HasReferences":       hasReferences,
+			"HasDefaulter":        hasDefaulter,
+			"BeforeWrite":         beforeWrite,
+			"HasBeforeWriteHooks": hasBeforeWriteHook(data.Type, make(map[reflect.Type]bool)),
+			"AfterRead":           afterRead,
+			"Constraints":         schemaConstraints,
+			"ValidateWrites":      validateWrites,
+			"Fields":              schemaFields,
+			"DeprecatedFields":    deprecatedFields,
+			"ReadFields":          readFields,
+			"NumFields":           len(schemaFields),
+			"WritingContextType":  writingContextType,
+			"ReadingContextType":  readingContextType,
+			"TargetType":          targetTypeName,
+			"Imports":             imports,
+			"Package":             c.packageName(),
+			"ID":                  data.ID,
+			"Fingerprint":         fmt.Sprintf("0x%016x", data.Fingerprint),
+			"TrustFingerprint":    data.Fingerprint != 0,
+			"GoType":              data.Type.PkgPath() + "." + data.Type.Name(),
 		},
 	)
 
@@ -307,11 +561,58 @@ func (c *Context) generateSchema(data *SchemaMetaData) error {
 	methodBuf.WriteTo(&buf)
 
 	data.ready = true
+	if c.generateTests {
+		data.testOutput = c.generateTest(data, schemaFields)
+	}
 
-	c.schemaStack = c.schemaStack[0 : len(c.schemaStack)-1]
-	data.inPreparation = false
+	data.output = &buf
+	return nil
+}
 
-	return c.outputWriter.Write(data.Name, &buf)
+func makeEntries(fields []schemaField) []goschema.SchemaEntry {
+	entries := make([]goschema.SchemaEntry, len(fields))
+	for i, field := range fields {
+		entries[i] = goschema.SchemaEntry{
+			Name:       field.Name,
+			Offset:     field.Offset,
+			Type:       field.TypeCode,
+			Descriptor: field.Type,
+			Aliases:    field.Aliases,
+			Required:   field.Required,
+		}
+	}
+	return entries
+}
+
+// widenMethod returns the suffix of the SchemaReader method that reads numbers
+// stored with a narrower type for the target, or "" if it cannot be widened.
+func widenMethod(serializer TypeSerializer, target Target) string {
+	if _, ok := serializer.(*BaseSerializer); !ok {
+		return ""
+	}
+	switch target.Type.Kind() {
+	case reflect.Int, reflect.Int16, reflect.Int32, reflect.Int64:
+		return "Int"
+	case reflect.Uint, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "UInt"
+	case reflect.Float32, reflect.Float64:
+		return "Float"
+	}
+	return ""
+}
+
+// checkAliases makes sure that every name refers to at most one field.
+func checkAliases(fields []schemaField) error {
+	names := make(map[string]string)
+	for _, field := range fields {
+		for _, name := range append([]string{field.Name}, field.Aliases...) {
+			if other, ok := names[name]; ok {
+				return fmt.Errorf("name %v is used by fields %v and %v", name, other, field.Name)
+			}
+			names[name] = field.Name
+		}
+	}
+	return nil
 }
 
 func tag(tags reflect.StructTag, key, defaultValue string) string {
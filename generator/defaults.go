@@ -0,0 +1,280 @@
+package generator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/parser"
+	"go/token"
+	"net/url"
+	"reflect"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// RegisterDefaultFuncs makes functions available to the schemaDefaultFunc tag.
+// Each function must be a top-level function without parameters that returns
+// a single value; it is referred to by its name, qualified by the import path
+// of its package unless it is in the package of the struct.
+func (c *Context) RegisterDefaultFuncs(funcs ...interface{}) {
+	for _, function := range funcs {
+		value := reflect.ValueOf(function)
+		if value.Kind() != reflect.Func || value.IsNil() {
+			panic(fmt.Sprintf("%v is not a function", function))
+		}
+		// the runtime escapes dots in the last element of the import path
+		name := runtime.FuncForPC(value.Pointer()).Name()
+		nameStart := strings.LastIndex(name, "/") + 1
+		pkgEnd := nameStart + strings.Index(name[nameStart:], ".")
+		if pkgPath, err := url.PathUnescape(name[:pkgEnd]); err == nil {
+			name = pkgPath + name[pkgEnd:]
+		}
+		c.defaultFuncs[name] = value.Type()
+	}
+}
+
+// defaultCode returns the code for the default value of a field as specified
+// by its schemaDefault or schemaDefaultFunc tag, or an empty string if the
+// field has no default value.
+func (c *Context) defaultCode(owner reflect.Type, field reflect.StructField) (string, error) {
+	value, hasDefault := field.Tag.Lookup("schemaDefault")
+	function, hasFunc := field.Tag.Lookup("schemaDefaultFunc")
+	switch {
+	case hasDefault && hasFunc:
+		return "", fmt.Errorf("field %v of %v has both a default value and a default function", field.Name, owner.String())
+	case hasFunc:
+		code, err := c.defaultFuncCode(owner, field.Type, function)
+		if err != nil {
+			return "", fmt.Errorf("default function of field %v of %v: %v", field.Name, owner.String(), err)
+		}
+		return code, nil
+	case !hasDefault || value == "":
+		return "", nil
+	}
+
+	switch field.Type.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map, reflect.Struct, reflect.Ptr:
+		// composite defaults are given as JSON and turned into a literal
+		target := reflect.New(field.Type)
+		decoder := json.NewDecoder(strings.NewReader(value))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(target.Interface()); err != nil {
+			return "", fmt.Errorf("default value of field %v of %v: %v", field.Name, owner.String(), err)
+		}
+		code, err := c.literal(target.Elem())
+		if err != nil {
+			return "", fmt.Errorf("default value of field %v of %v: %v", field.Name, owner.String(), err)
+		}
+		return code, nil
+	}
+	if err := checkLiteral(field.Type, value); err != nil {
+		return "", fmt.Errorf("default value of field %v of %v: %v", field.Name, owner.String(), err)
+	}
+	return c.GetTypeName(field.Type) + "(" + value + ")", nil
+}
+
+// defaultFuncCode returns the code that calls the given default function. The
+// name of the function is either qualified by an import path, as in
+// "path/to/pkg.Func", or refers to a function in the package of the owner.
+// The function must have been registered with RegisterDefaultFuncs and return
+// a value that can be assigned to the field.
+func (c *Context) defaultFuncCode(owner, fieldType reflect.Type, function string) (string, error) {
+	pkgPath := owner.PkgPath()
+	if idx := strings.LastIndex(function, "."); idx != -1 && idx > strings.LastIndex(function, "/") {
+		pkgPath = function[:idx]
+		function = function[idx+1:]
+	}
+	if !token.IsIdentifier(function) {
+		return "", fmt.Errorf("%q is not a function name", function)
+	}
+	funcType, ok := c.defaultFuncs[pkgPath+"."+function]
+	if !ok {
+		return "", fmt.Errorf("%v.%v is not registered with RegisterDefaultFuncs", pkgPath, function)
+	}
+	if funcType.NumIn() != 0 || funcType.NumOut() != 1 || !funcType.Out(0).AssignableTo(fieldType) {
+		return "", fmt.Errorf("%v.%v has type %v, expected func() %v", pkgPath, function, funcType, fieldType)
+	}
+	if pkgPath == c.packagePath {
+		return function + "()", nil
+	}
+	if !token.IsExported(function) {
+		return "", fmt.Errorf("%v is not exported from %v", function, pkgPath)
+	}
+	// the name of a package may differ from the last element of its path, so
+	// it is imported under a name derived from the whole path
+	top := c.schemaStack[len(c.schemaStack)-1]
+	alias := top.importAlias(pkgPath)
+	top.Imports[alias+" "+strconv.Quote(pkgPath)] = struct{}{}
+	return alias + "." + function + "()", nil
+}
+
+// importAlias returns the package name under which the schema imports the
+// given import path. The name is derived from the whole path; since different
+// paths may map to the same name, e.g. "a-b" and "a_b", a number is appended
+// to names that are taken by another path already.
+func (data *SchemaMetaData) importAlias(pkgPath string) string {
+	if alias, ok := data.importAliases[pkgPath]; ok {
+		return alias
+	}
+	if data.importAliases == nil {
+		data.importAliases = make(map[string]string)
+	}
+	base := "pkg_" + strings.Map(func(r rune) rune {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			return r
+		}
+		return '_'
+	}, pkgPath)
+	used := make(map[string]bool, len(data.importAliases))
+	for _, alias := range data.importAliases {
+		used[alias] = true
+	}
+	alias := base
+	for n := 2; used[alias]; n++ {
+		alias = base + "_" + strconv.Itoa(n)
+	}
+	data.importAliases[pkgPath] = alias
+	return alias
+}
+
+// checkLiteral verifies that a value given as a literal fits the type. Other
+// expressions, e.g. names of constants, are left to the compiler.
+func checkLiteral(typ reflect.Type, value string) error {
+	expr, err := parser.ParseExpr(value)
+	if err != nil {
+		return err
+	}
+	negate := false
+	if unary, ok := expr.(*ast.UnaryExpr); ok && (unary.Op == token.SUB || unary.Op == token.ADD) {
+		negate = unary.Op == token.SUB
+		expr = unary.X
+	}
+	lit, ok := expr.(*ast.BasicLit)
+	if !ok {
+		return nil
+	}
+	constValue := constant.MakeFromLiteral(lit.Value, lit.Kind, 0)
+	if negate {
+		constValue = constant.UnaryOp(token.SUB, constValue, 0)
+	}
+	invalid := fmt.Errorf("%v is not a valid %v", value, typ.String())
+	switch typ.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		constValue = constant.ToInt(constValue)
+		v, exact := constant.Int64Val(constValue)
+		if constValue.Kind() != constant.Int || !exact || reflect.Zero(typ).OverflowInt(v) {
+			return invalid
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		constValue = constant.ToInt(constValue)
+		v, exact := constant.Uint64Val(constValue)
+		if constValue.Kind() != constant.Int || !exact || reflect.Zero(typ).OverflowUint(v) {
+			return invalid
+		}
+	case reflect.Float32, reflect.Float64:
+		if constant.ToFloat(constValue).Kind() != constant.Float {
+			return invalid
+		}
+	case reflect.String:
+		if constValue.Kind() != constant.String {
+			return invalid
+		}
+	case reflect.Bool:
+		return invalid
+	}
+	return nil
+}
+
+// literal returns a Go literal for the given value.
+func (c *Context) literal(value reflect.Value) (string, error) {
+	typ := value.Type()
+	switch typ.Kind() {
+	case reflect.Bool:
+		return c.convertedLiteral(typ, strconv.FormatBool(value.Bool())), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return c.convertedLiteral(typ, strconv.FormatInt(value.Int(), 10)), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return c.convertedLiteral(typ, strconv.FormatUint(value.Uint(), 10)), nil
+	case reflect.Float32, reflect.Float64:
+		return c.convertedLiteral(typ, strconv.FormatFloat(value.Float(), 'g', -1, typ.Bits())), nil
+	case reflect.String:
+		return c.convertedLiteral(typ, strconv.Quote(value.String())), nil
+	case reflect.Ptr:
+		if value.IsNil() {
+			return "nil", nil
+		}
+		elem, err := c.literal(value.Elem())
+		if err != nil {
+			return "", err
+		}
+		if typ.Elem().Kind() == reflect.Struct {
+			return "&" + elem, nil
+		}
+		return fmt.Sprintf("func() %v { v := %v; return &v }()", c.GetTypeName(typ), elem), nil
+	case reflect.Slice, reflect.Array:
+		if typ.Kind() == reflect.Slice && value.IsNil() {
+			return "nil", nil
+		}
+		var buf bytes.Buffer
+		buf.WriteString(c.GetTypeName(typ) + "{")
+		for i := 0; i < value.Len(); i++ {
+			elem, err := c.literal(value.Index(i))
+			if err != nil {
+				return "", err
+			}
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			buf.WriteString(elem)
+		}
+		buf.WriteString("}")
+		return buf.String(), nil
+	case reflect.Map:
+		if value.IsNil() {
+			return "nil", nil
+		}
+		entries := make([]string, 0, value.Len())
+		for _, key := range value.MapKeys() {
+			keyCode, err := c.literal(key)
+			if err != nil {
+				return "", err
+			}
+			elemCode, err := c.literal(value.MapIndex(key))
+			if err != nil {
+				return "", err
+			}
+			entries = append(entries, keyCode+": "+elemCode)
+		}
+		// the order of the keys is fixed so that the output is deterministic
+		sort.Strings(entries)
+		return c.GetTypeName(typ) + "{" + strings.Join(entries, ", ") + "}", nil
+	case reflect.Struct:
+		var fields []string
+		for i := 0; i < typ.NumField(); i++ {
+			field := typ.Field(i)
+			if field.PkgPath != "" || value.Field(i).IsZero() {
+				continue
+			}
+			code, err := c.literal(value.Field(i))
+			if err != nil {
+				return "", err
+			}
+			fields = append(fields, field.Name+": "+code)
+		}
+		return c.GetTypeName(typ) + "{" + strings.Join(fields, ", ") + "}", nil
+	}
+	return "", fmt.Errorf("values of type %v cannot be used as defaults", typ.String())
+}
+
+// convertedLiteral converts a literal of a basic type to the named type typ.
+func (c *Context) convertedLiteral(typ reflect.Type, code string) string {
+	if typ.PkgPath() == "" {
+		return code
+	}
+	return c.GetTypeName(typ) + "(" + code + ")"
+}
@@ -0,0 +1,85 @@
+package generator
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/chasingcarrots/goschema/generator/testdata/fixtures"
+)
+
+type withPid struct {
+	Pid int `schemaDefaultFunc:"os.Getpid"`
+}
+
+type withName struct {
+	Name string `schemaDefaultFunc:"os.Getpid"`
+}
+
+func TestDefaultFuncs(t *testing.T) {
+	output := memoryOutput{}
+	c := newTestContext(output)
+	c.RegisterDefaultFuncs(os.Getpid)
+	c.RequestSchema(reflect.TypeOf(withPid{}), "WithPid")
+	if err := c.Generate(); err != nil {
+		t.Fatal(err)
+	}
+	code := output["WithPid"]
+	for _, expected := range []string{`pkg_os "os"`, "pkg_os.Getpid()"} {
+		if !strings.Contains(code, expected) {
+			t.Errorf("generated code does not contain %v", expected)
+		}
+	}
+}
+
+func TestDefaultFuncErrors(t *testing.T) {
+	for _, test := range []struct {
+		typ      reflect.Type
+		register bool
+		err      string
+	}{
+		{reflect.TypeOf(withPid{}), false, "os.Getpid is not registered"},
+		{reflect.TypeOf(withName{}), true, "os.Getpid has type func() int, expected func() string"},
+	} {
+		c := newTestContext(memoryOutput{})
+		if test.register {
+			c.RegisterDefaultFuncs(os.Getpid)
+		}
+		c.RequestSchema(test.typ, "Defaulted")
+		if err := c.Generate(); err == nil || !strings.Contains(err.Error(), test.err) {
+			t.Errorf("%v: got %v", test.typ, err)
+		}
+	}
+}
+
+func TestMissingNestedDefaulters(t *testing.T) {
+	output := runGenerated(t, "defaults", func(c *Context) {
+		c.RequestSchema(reflect.TypeOf(fixtures.Account{}), "Account")
+	})
+	if strings.TrimSpace(output) != "ok" {
+		t.Fatal(output)
+	}
+}
+
+func TestImportAliases(t *testing.T) {
+	data := &SchemaMetaData{}
+	paths := []string{"example.com/a-b", "example.com/a_b", "example.com/a_b_2", "example.com/a.b"}
+	aliases := make(map[string]string)
+	for _, path := range paths {
+		alias := data.importAlias(path)
+		if other, ok := aliases[alias]; ok {
+			t.Errorf("%v and %v are both imported as %v", other, path, alias)
+		}
+		aliases[alias] = path
+	}
+	// a path keeps its alias
+	for alias, path := range aliases {
+		if again := data.importAlias(path); again != alias {
+			t.Errorf("%v is imported as %v and %v", path, alias, again)
+		}
+	}
+	if alias := data.importAlias("example.com/a-b"); alias != "pkg_example_com_a_b" {
+		t.Errorf("alias %v", alias)
+	}
+}
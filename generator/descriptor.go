@@ -0,0 +1,41 @@
+package generator
+
+import (
+	"fmt"
+
+	"github.com/chasingcarrots/goschema"
+)
+
+// typeDescriptor returns the goschema.TypeDescriptor of the target.
+func (c *Context) typeDescriptor(target Target) *goschema.TypeDescriptor {
+	serializer := c.FindSerializer(target)
+	descriptor := &goschema.TypeDescriptor{Code: serializer.TypeCode(c, target)}
+	switch serializer.(type) {
+	case *SchemaSerializer:
+		descriptor.Schema = c.GetSchema(target.Type).Name
+	case *ListSerializer, *PointerSerializer:
+		descriptor.Elem = c.typeDescriptor(TypeTarget(target.Type.Elem()))
+	case *MapSerializer:
+		descriptor.Key = c.typeDescriptor(TypeTarget(target.Type.Key()))
+		descriptor.Elem = c.typeDescriptor(TypeTarget(target.Type.Elem()))
+	}
+	return descriptor
+}
+
+// descriptorCode returns code that constructs the given type descriptor.
+func descriptorCode(descriptor *goschema.TypeDescriptor) string {
+	if descriptor == nil {
+		return "nil"
+	}
+	code := fmt.Sprintf("&goschema.TypeDescriptor{Code: goschema.TypeCode(%v)", descriptor.Code)
+	if descriptor.Schema != "" {
+		code += fmt.Sprintf(", Schema: %q", descriptor.Schema)
+	}
+	if descriptor.Key != nil {
+		code += ", Key: " + descriptorCode(descriptor.Key)
+	}
+	if descriptor.Elem != nil {
+		code += ", Elem: " + descriptorCode(descriptor.Elem)
+	}
+	return code + "}"
+}
@@ -0,0 +1,112 @@
+package generator
+
+import (
+	"fmt"
+	"hash/fnv"
+	"reflect"
+	"strings"
+)
+
+// fingerprint computes a hash of the serialized form of the given schema type:
+// its name, the names and type codes of its fields and the fingerprints of all
+// schemata that are nested in its fields. Schemata that are currently being
+// fingerprinted further up in the stack are only represented by their names so
+// that recursive types terminate. The second return value is the lowest index
+// in the stack that the fingerprint depends on, or len(stack) if it does not
+// depend on the stack. Schemata with a field whose serialized form cannot be
+// described, e.g. because it uses a custom serializer, have the fingerprint 0,
+// which never counts as a match.
+func (c *Context) fingerprint(typ reflect.Type, stack []reflect.Type) (uint64, int) {
+	if fp, ok := c.fingerprints[typ]; ok {
+		return fp, len(stack)
+	}
+	stack = append(stack, typ)
+	lowest := len(stack)
+
+	opaque := false
+	var description strings.Builder
+	fmt.Fprintf(&description, "schema %v\n", c.GetSchema(typ).Name)
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if _, ignore := field.Tag.Lookup("schemaIgnore"); ignore {
+			continue
+		}
+		if _, deprecated := field.Tag.Lookup("schemaDeprecated"); deprecated {
+			continue
+		}
+		target := Target{Type: field.Type, Tags: field.Tag}
+		if c.FindSerializer(target) == nil {
+			continue
+		}
+		signature, depth := c.typeSignature(target, stack)
+		if depth < lowest {
+			lowest = depth
+		}
+		if signature == "" {
+			opaque = true
+		}
+		fmt.Fprintf(&description, "%v %v\n", tag(field.Tag, "schemaName", field.Name), signature)
+	}
+
+	hash := fnv.New64a()
+	hash.Write([]byte(description.String()))
+	fp := hash.Sum64()
+	if opaque {
+		fp = 0
+	}
+	// fingerprints of recursive schemata depend on where the computation
+	// started, so only those that did not run into the stack are cached
+	if lowest == len(stack) {
+		c.fingerprints[typ] = fp
+	}
+	return fp, lowest
+}
+
+// typeSignature describes the serialized form of the target for fingerprints,
+// or returns "" if the serializer of the target is unknown. It also returns
+// the lowest index in the stack that the signature depends on, or len(stack)
+// if it does not depend on the stack at all.
+func (c *Context) typeSignature(target Target, stack []reflect.Type) (string, int) {
+	serializer := c.FindSerializer(target)
+	typeCode := serializer.TypeCode(c, target)
+	switch serializer := serializer.(type) {
+	case *BaseSerializer, *StringSerializer:
+		return fmt.Sprint(typeCode), len(stack)
+	case *InlineSerializer:
+		// inline values are read in place, so their layout is part of the schema
+		signatures := make([]string, len(serializer.fields))
+		for i, entry := range serializer.fields {
+			signature, _ := c.typeSignature(Target{Type: entry.field.Type, Tags: entry.field.Tag}, stack)
+			if signature == "" {
+				return "", len(stack)
+			}
+			signatures[i] = signature
+		}
+		return fmt.Sprintf("%v[%v](%v)", typeCode, serializer.size, strings.Join(signatures, ",")), len(stack)
+	case *SchemaSerializer:
+		for i := range stack {
+			if stack[i] == target.Type {
+				return fmt.Sprintf("%v(%v)", typeCode, c.GetSchema(target.Type).Name), i
+			}
+		}
+		fp, depth := c.fingerprint(target.Type, stack)
+		return fmt.Sprintf("%v(%016x)", typeCode, fp), depth
+	case *ListSerializer, *PointerSerializer:
+		element, depth := c.typeSignature(TypeTarget(target.Type.Elem()), stack)
+		if element == "" {
+			return "", depth
+		}
+		return fmt.Sprintf("%v(%v)", typeCode, element), depth
+	case *MapSerializer:
+		key, keyDepth := c.typeSignature(TypeTarget(target.Type.Key()), stack)
+		value, valueDepth := c.typeSignature(TypeTarget(target.Type.Elem()), stack)
+		if keyDepth < valueDepth {
+			valueDepth = keyDepth
+		}
+		if key == "" || value == "" {
+			return "", valueDepth
+		}
+		return fmt.Sprintf("%v(%v,%v)", typeCode, key, value), valueDepth
+	}
+	return "", len(stack)
+}
@@ -0,0 +1,43 @@
+package generator
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/chasingcarrots/goschema"
+)
+
+type vector2 struct{ X, Y float32 }
+type vector2Wide struct{ X, Y float64 }
+type vector3 struct{ X, Y, Z float32 }
+
+type placed2 struct{ Position vector2 }
+type placed2Wide struct{ Position vector2Wide }
+type placed3 struct{ Position vector3 }
+
+// opaqueSerializer is a custom serializer whose layout the generator does
+// not know.
+type opaqueSerializer struct{ *InlineSerializer }
+
+func TestFingerprintCoversInlineLayout(t *testing.T) {
+	const code = goschema.TypeCode(200)
+	fingerprints := []uint64{
+		generateFingerprint(t, reflect.TypeOf(placed2{}), "Placed", NewInlineSerializer(reflect.TypeOf(vector2{}), code)),
+		generateFingerprint(t, reflect.TypeOf(placed2Wide{}), "Placed", NewInlineSerializer(reflect.TypeOf(vector2Wide{}), code)),
+		generateFingerprint(t, reflect.TypeOf(placed3{}), "Placed", NewInlineSerializer(reflect.TypeOf(vector3{}), code)),
+	}
+	for i := range fingerprints {
+		for j := i + 1; j < len(fingerprints); j++ {
+			if fingerprints[i] == fingerprints[j] {
+				t.Errorf("layouts %v and %v have the same fingerprint %016x", i, j, fingerprints[i])
+			}
+		}
+	}
+}
+
+func TestFingerprintOfCustomSerializers(t *testing.T) {
+	serializer := opaqueSerializer{NewInlineSerializer(reflect.TypeOf(vector2{}), 200)}
+	if fp := generateFingerprint(t, reflect.TypeOf(placed2{}), "Placed", serializer); fp != 0 {
+		t.Fatalf("fingerprint is %016x instead of 0", fp)
+	}
+}
@@ -0,0 +1,56 @@
+package generator
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// memoryOutput collects the generated files by name.
+type memoryOutput map[string]string
+
+func (m memoryOutput) Write(name string, buf *bytes.Buffer) error {
+	m[name] = buf.String()
+	return nil
+}
+
+// newTestContext creates a context with the default serializers that uses the
+// template of this repository and writes to the given output.
+func newTestContext(output memoryOutput) *Context {
+	c := NewContext(output, "example.com/fixtures", "../schemaimpl.got", reflect.TypeOf(0), reflect.TypeOf(0))
+	c.AddDefaultSerializers()
+	return c
+}
+
+// generateFingerprint generates the schema for typ under the given name and
+// returns its fingerprint.
+func generateFingerprint(t *testing.T, typ reflect.Type, name string, serializers ...TypeSerializer) uint64 {
+	c := newTestContext(memoryOutput{})
+	c.AddSerializers(serializers...)
+	c.RequestSchema(typ, name)
+	if err := c.Generate(); err != nil {
+		t.Fatal(err)
+	}
+	return c.GetSchema(typ).Fingerprint
+}
+
+type invalidNested struct {
+	Name string `schemaMin:"1"`
+}
+
+type withInvalidNested struct {
+	Nested invalidNested
+	Count  int32
+}
+
+func TestErrorsOfNestedSchemata(t *testing.T) {
+	c := newTestContext(memoryOutput{})
+	c.RequestSchema(reflect.TypeOf(withInvalidNested{}), "Outer")
+	if err := c.Generate(); err == nil || !strings.Contains(err.Error(), "has schemaMin, but is not a number") {
+		t.Fatalf("got %v", err)
+	}
+	if len(c.schemaStack) != 0 || c.schemaMetaData[reflect.TypeOf(invalidNested{})].inPreparation {
+		t.Fatal("the failed schema was not removed from the stack")
+	}
+}
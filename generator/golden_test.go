@@ -0,0 +1,95 @@
+package generator
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/chasingcarrots/goschema"
+	"github.com/chasingcarrots/goschema/generator/testdata/fixtures"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata/generated")
+
+// goldenPackage is the package of the golden files. The fuzz tests of goschema
+// compile it.
+const goldenPackage = "github.com/chasingcarrots/goschema/generator/testdata/generated"
+
+// requestFixtures requests the schemata for the fixtures that the golden files
+// are generated for.
+func requestFixtures(c *Context) {
+	c.AddSerializers(NewInlineSerializer(reflect.TypeOf(fixtures.Vector2{}), goschema.TypeCode(255)))
+	c.RequestSchema(reflect.TypeOf(fixtures.Document{}), "Document")
+	c.RequestSchema(reflect.TypeOf(fixtures.Node{}), "Node")
+	c.RequestSchema(reflect.TypeOf(fixtures.Item{}), "Item")
+	c.RequestSchema(reflect.TypeOf(fixtures.Catalog{}), "Catalog")
+	c.RequestSchema(reflect.TypeOf(fixtures.Ranged{}), "Ranged")
+	c.RequestSchema(reflect.TypeOf(fixtures.Scalars{}), "Scalars")
+	c.RequestSchema(reflect.TypeOf(fixtures.Limits{}), "Limits")
+}
+
+func TestGolden(t *testing.T) {
+	output := memoryOutput{}
+	c := NewContext(output, goldenPackage, "../schemaimpl.got", reflect.TypeOf(0), reflect.TypeOf(0))
+	c.AddDefaultSerializers()
+	requestFixtures(c)
+	if err := c.Generate(); err != nil {
+		t.Fatal(err)
+	}
+
+	dir := filepath.Join("testdata", "generated")
+	if *update {
+		if err := os.RemoveAll(dir); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Mkdir(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		for name, code := range output {
+			if err := dirOutput(dir).Write(name, bytes.NewBufferString(code)); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return
+	}
+
+	golden, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var generated []string
+	for name := range output {
+		generated = append(generated, filepath.Join(dir, name+".go"))
+	}
+	sort.Strings(generated)
+	if strings.Join(generated, " ") != strings.Join(golden, " ") {
+		t.Fatalf("generated %v instead of %v, run the tests with -update", generated, golden)
+	}
+	for name, code := range output {
+		source, err := formatGenerated(name, []byte(code))
+		if err != nil {
+			t.Fatal(err)
+		}
+		path := filepath.Join(dir, name+".go")
+		expected, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// the files may have been checked out with CRLF line endings
+		if !bytes.Equal(source, bytes.ReplaceAll(expected, []byte("\r\n"), []byte("\n"))) {
+			t.Errorf("%v differs from the generated code, run the tests with -update", path)
+		}
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	output := runGenerated(t, "roundtrip", requestFixtures)
+	if strings.TrimSpace(output) != "ok" {
+		t.Fatal(output)
+	}
+}
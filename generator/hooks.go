@@ -0,0 +1,105 @@
+package generator
+
+import (
+	"fmt"
+	"reflect"
+)
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// hook describes an optional method of a type that the generated code calls
+// when the type is read or written.
+type hook struct {
+	Found        bool
+	ReturnsError bool
+}
+
+// hasBeforeWriteHook reports whether values of typ may contain objects with a
+// BeforeSchemaWrite method. Types in visited are not looked at again.
+func hasBeforeWriteHook(typ reflect.Type, visited map[reflect.Type]bool) bool {
+	if visited[typ] {
+		return false
+	}
+	visited[typ] = true
+	switch typ.Kind() {
+	case reflect.Struct:
+		if _, ok := reflect.PtrTo(typ).MethodByName("BeforeSchemaWrite"); ok {
+			return true
+		}
+		for i := 0; i < typ.NumField(); i++ {
+			if hasBeforeWriteHook(typ.Field(i).Type, visited) {
+				return true
+			}
+		}
+	case reflect.Slice, reflect.Array, reflect.Ptr:
+		return hasBeforeWriteHook(typ.Elem(), visited)
+	case reflect.Map:
+		return hasBeforeWriteHook(typ.Key(), visited) || hasBeforeWriteHook(typ.Elem(), visited)
+	}
+	return false
+}
+
+// checkMapKeyHooks returns an error if values of typ contain maps whose keys
+// may contain objects with a BeforeSchemaWrite method. Forward writers run the
+// hooks before computing sizes, which they cannot do for copies of keys.
+func checkMapKeyHooks(typ reflect.Type, visited map[reflect.Type]bool) error {
+	if visited[typ] {
+		return nil
+	}
+	visited[typ] = true
+	switch typ.Kind() {
+	case reflect.Struct:
+		for i := 0; i < typ.NumField(); i++ {
+			if err := checkMapKeyHooks(typ.Field(i).Type, visited); err != nil {
+				return err
+			}
+		}
+	case reflect.Slice, reflect.Array, reflect.Ptr:
+		return checkMapKeyHooks(typ.Elem(), visited)
+	case reflect.Map:
+		if hasBeforeWriteHook(typ.Key(), make(map[reflect.Type]bool)) {
+			return fmt.Errorf("keys of %v must not have a BeforeSchemaWrite method", typ.String())
+		}
+		return checkMapKeyHooks(typ.Elem(), visited)
+	}
+	return nil
+}
+
+// MakeBeforeWriteCode returns the code that runs the BeforeSchemaWrite hooks of
+// all objects in the value, or an empty string if it contains no such objects
+// or its serializer does not implement HookSerializer.
+func (c *Context) MakeBeforeWriteCode(ptrValueTarget bool, target Target, valueName string) string {
+	if !hasBeforeWriteHook(target.Type, make(map[reflect.Type]bool)) {
+		return ""
+	}
+	serializer, ok := c.FindSerializer(target).(HookSerializer)
+	if !ok {
+		return ""
+	}
+	return serializer.MakeBeforeWriteCode(c, ptrValueTarget, target, valueName)
+}
+
+// findHook looks for the method name on a pointer to typ. The method must take
+// a value of the given context type and may return an error if allowError is
+// set.
+func findHook(typ reflect.Type, name string, context reflect.Type, allowError bool) (hook, error) {
+	method, ok := reflect.PtrTo(typ).MethodByName(name)
+	if !ok {
+		return hook{}, nil
+	}
+	// the receiver is the first argument
+	methodType := method.Type
+	valid := methodType.NumIn() == 2 && context.AssignableTo(methodType.In(1))
+	returnsError := methodType.NumOut() == 1 && methodType.Out(0) == errorType
+	if methodType.NumOut() > 1 || (methodType.NumOut() == 1 && (!returnsError || !allowError)) {
+		valid = false
+	}
+	if !valid {
+		results := ""
+		if allowError {
+			results = " and return nothing or an error"
+		}
+		return hook{}, fmt.Errorf("method %v of %v must take a %v%v", name, typ.String(), context.String(), results)
+	}
+	return hook{Found: true, ReturnsError: returnsError}, nil
+}
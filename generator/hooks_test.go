@@ -0,0 +1,30 @@
+package generator
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/chasingcarrots/goschema/generator/testdata/fixtures"
+)
+
+func TestNestedHooksOnForwardWriter(t *testing.T) {
+	output := runGenerated(t, "hooks", func(c *Context) {
+		c.RequestSchema(reflect.TypeOf(fixtures.Document{}), "Document")
+	})
+	if strings.TrimSpace(output) != "ok" {
+		t.Fatal(output)
+	}
+}
+
+type keyedByTag struct {
+	Counts map[fixtures.Tag]int
+}
+
+func TestHooksInMapKeys(t *testing.T) {
+	c := newTestContext(memoryOutput{})
+	c.RequestSchema(reflect.TypeOf(keyedByTag{}), "Keyed")
+	if err := c.Generate(); err == nil || !strings.Contains(err.Error(), "must not have a BeforeSchemaWrite method") {
+		t.Fatalf("got %v", err)
+	}
+}
@@ -87,6 +87,10 @@ func (is *InlineSerializer) MakeWritingCode(context *Context, ptrValueTarget boo
 	return buf.String()
 }
 
+func (is *InlineSerializer) MakeSizingCode(context *Context, ptrValueTarget bool, target Target, sizeName, valueName string) string {
+	return makeFixedSizingCode(sizeName, is.size)
+}
+
 func (is *InlineSerializer) SizeOf(*Context, Target) uint32 {
 	return is.size
 }
@@ -8,7 +8,7 @@ import (
 	"github.com/chasingcarrots/goschema"
 )
 
-const listReadCoreTemplate_A = `{{ .Token }}Entries := int({{ .Reader }}.ReadUInt32())
+const listReadCoreTemplate_A = `{{ .Token }}Entries := {{ .Reader }}.ReadCollectionLength({{ .ElementSize }})
 {{ .Token }}Slice := make([]{{ .InnerType }}, {{ .Token }}Entries, {{ .Token }}Entries)
 for {{ .Token }}I := 0; {{ .Token }}I < {{ .Token }}Entries; {{ .Token }}I++ {
 `
@@ -48,11 +48,27 @@ const schemaListWriteTemplate = "{{ .Writer }}.WriteUInt8(uint8(goschema.TypeCod
 	"}\n" +
 	writeRestoreBase
 
+const listSizeTemplate = `{{ .Size }} += 1 + 4{{ if .IsSchema }} + 4{{ end }}
+{{ if .ElementSize -}}
+{{ .Size }} += len({{ .Dereference }}{{ .ListValue }}) * {{ .ElementSize }}
+{{ else -}}
+for {{ .Token }}I := range {{ .Dereference }}{{ .ListValue }} {
+{{ .InnerSizingCode }}}
+{{ end -}}
+`
+
+const listBeforeWriteTemplate = `for {{ .Token }}I := range {{ .Dereference }}{{ .ListValue }} {
+{{ .InnerCode }}}
+`
+
 type ListSerializer struct {
 	readTemplate        *template.Template
 	readSchemaTemplate  *template.Template
 	writeTemplate       *template.Template
 	writeSchemaTemplate *template.Template
+	sizeTemplate        *template.Template
+	sizeSchemaTemplate  *template.Template
+	hookTemplate        *template.Template
 }
 
 func NewListSerializer() *ListSerializer {
@@ -61,6 +77,9 @@ func NewListSerializer() *ListSerializer {
 		readSchemaTemplate:  template.Must(template.New("ReadSchema").Parse(schemaListReadTemplate)),
 		writeTemplate:       template.Must(template.New("Write").Parse(basicListWriteTemplate)),
 		writeSchemaTemplate: template.Must(template.New("WriteSchema").Parse(schemaListWriteTemplate)),
+		sizeTemplate:        template.Must(template.New("Size").Parse(listSizeTemplate)),
+		sizeSchemaTemplate:  template.Must(template.New("SizeSchema").Parse(schemaSizeCoreTemplate)),
+		hookTemplate:        template.Must(template.New("BeforeWrite").Parse(listBeforeWriteTemplate)),
 	}
 }
 
@@ -88,6 +107,7 @@ func (ls *ListSerializer) MakeReadingCode(context *Context, ptrValueTarget bool,
 				"Dereference": makeDeref(ptrValueTarget),
 				"SchemaName":  schema.Name,
 				"Reference":   "&",
+				"ElementSize": innerType.Type.Size(),
 			},
 		)
 	} else {
@@ -100,6 +120,7 @@ func (ls *ListSerializer) MakeReadingCode(context *Context, ptrValueTarget bool,
 				"InnerType":        context.GetTypeName(innerType.Type),
 				"InnerReadingCode": innerReadingCode,
 				"Dereference":      makeDeref(ptrValueTarget),
+				"ElementSize":      innerType.Type.Size(),
 			},
 		)
 	}
@@ -115,6 +136,9 @@ func (ls *ListSerializer) MakeWritingCode(context *Context, ptrValueTarget bool,
 	token := context.UniqueToken()
 	var buf bytes.Buffer
 	innerValueName := valueName + "[" + token + "I]"
+	if ptrValueTarget {
+		innerValueName = "(*" + valueName + ")[" + token + "I]"
+	}
 	if serializer.TypeCode(context, innerType) == goschema.SchemaType {
 		schema := context.GetSchema(target.Type.Elem())
 		ls.writeSchemaTemplate.Execute(&buf,
@@ -147,6 +171,75 @@ func (ls *ListSerializer) MakeWritingCode(context *Context, ptrValueTarget bool,
 	return buf.String()
 }
 
+func (ls *ListSerializer) MakeSizingCode(context *Context, ptrValueTarget bool, target Target, sizeName, valueName string) string {
+	innerType := TypeTarget(target.Type.Elem())
+	serializer := context.FindSerializer(innerType)
+	if serializer == nil {
+		panic("Could not find serializer")
+	}
+	token := context.UniqueToken()
+	innerValueName := valueName + "[" + token + "I]"
+	if ptrValueTarget {
+		innerValueName = "(*" + valueName + ")[" + token + "I]"
+	}
+	isSchema := serializer.TypeCode(context, innerType) == goschema.SchemaType
+	var elementSize uint32
+	var innerSizingCode string
+	if isSchema {
+		schema := context.GetSchema(target.Type.Elem())
+		var innerBuf bytes.Buffer
+		ls.sizeSchemaTemplate.Execute(&innerBuf,
+			Lookup{
+				"Size":        sizeName,
+				"SchemaValue": innerValueName,
+				"SchemaName":  schema.Name,
+				"Reference":   "&",
+			},
+		)
+		innerSizingCode = innerBuf.String()
+	} else if serializer.IsVariableSize(context, innerType) {
+		innerSizingCode = serializer.MakeSizingCode(context, false, innerType, sizeName, innerValueName)
+	} else {
+		elementSize = serializer.SizeOf(context, innerType)
+	}
+
+	var buf bytes.Buffer
+	ls.sizeTemplate.Execute(&buf,
+		Lookup{
+			"Token":           token,
+			"ListValue":       valueName,
+			"Size":            sizeName,
+			"IsSchema":        isSchema,
+			"ElementSize":     elementSize,
+			"InnerSizingCode": innerSizingCode,
+			"Dereference":     makeDeref(ptrValueTarget),
+		},
+	)
+	return buf.String()
+}
+
+func (ls *ListSerializer) MakeBeforeWriteCode(context *Context, ptrValueTarget bool, target Target, valueName string) string {
+	token := context.UniqueToken()
+	innerValueName := valueName + "[" + token + "I]"
+	if ptrValueTarget {
+		innerValueName = "(*" + valueName + ")[" + token + "I]"
+	}
+	innerCode := context.MakeBeforeWriteCode(false, TypeTarget(target.Type.Elem()), innerValueName)
+	if innerCode == "" {
+		return ""
+	}
+	var buf bytes.Buffer
+	ls.hookTemplate.Execute(&buf,
+		Lookup{
+			"Token":       token,
+			"ListValue":   valueName,
+			"InnerCode":   innerCode,
+			"Dereference": makeDeref(ptrValueTarget),
+		},
+	)
+	return buf.String()
+}
+
 func (*ListSerializer) SizeOf(*Context, Target) uint32 {
 	return 4
 }
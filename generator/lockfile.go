@@ -0,0 +1,170 @@
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/chasingcarrots/goschema"
+)
+
+// lockFile records the history of all generated schemata.
+type lockFile struct {
+	Schemata map[string]*lockedSchema `json:"schemata"`
+}
+
+type lockedSchema struct {
+	Versions []*lockedVersion `json:"versions"` // oldest first
+}
+
+type lockedVersion struct {
+	Fields []lockedField `json:"fields"`
+	// fields for which breaking changes with respect to this version have
+	// been acknowledged
+	Acknowledged []string `json:"acknowledged,omitempty"`
+}
+
+type lockedField struct {
+	Name        string                   `json:"name"`
+	Description string                   `json:"description"`
+	Type        *goschema.TypeDescriptor `json:"type"`
+	Aliases     []string                 `json:"aliases,omitempty"`
+	Required    bool                     `json:"required,omitempty"`
+}
+
+// BreakingChangeError is returned by Generate if a schema has changed in a way
+// that prevents reading data written with a version recorded in the lock file.
+type BreakingChangeError struct {
+	Issues []goschema.CompatIssue
+}
+
+func (e *BreakingChangeError) Error() string {
+	lines := make([]string, len(e.Issues))
+	for i, issue := range e.Issues {
+		lines[i] = issue.String()
+	}
+	return "breaking schema changes (use AcknowledgeChanges to allow them):\n\t" + strings.Join(lines, "\n\t")
+}
+
+// SetLockFile makes Generate record all versions of the generated schemata in
+// the lock file at the given path (usually goschema.lock.json). Generate fails
+// with a *BreakingChangeError if a schema has changed in a way that prevents
+// reading data written with any of its previous versions, i.e. if a field has
+// been removed or its type has changed and cannot be widened.
+func (c *Context) SetLockFile(path string) {
+	c.lockFilePath = path
+}
+
+// AcknowledgeChanges allows breaking changes of the given fields, named as
+// "Schema.Field". The acknowledgement is recorded in the lock file, so it only
+// needs to be given once.
+func (c *Context) AcknowledgeChanges(fields ...string) {
+	if c.acknowledged == nil {
+		c.acknowledged = make(map[string]bool)
+	}
+	for _, field := range fields {
+		c.acknowledged[field] = true
+	}
+}
+
+func (c *Context) updateLockFile() error {
+	lock := lockFile{Schemata: make(map[string]*lockedSchema)}
+	content, err := os.ReadFile(c.lockFilePath)
+	if err == nil {
+		if err := json.Unmarshal(content, &lock); err != nil {
+			return fmt.Errorf("%v: %v", c.lockFilePath, err)
+		}
+		if lock.Schemata == nil {
+			lock.Schemata = make(map[string]*lockedSchema)
+		}
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	var breaking []goschema.CompatIssue
+	for _, data := range c.sortedSchemata() {
+		locked, ok := lock.Schemata[data.Name]
+		if !ok {
+			locked = &lockedSchema{}
+			lock.Schemata[data.Name] = locked
+		}
+		// deprecated fields can still be read, so they are not removed
+		readable := append(append([]goschema.SchemaEntry(nil), data.Entries...), data.DeprecatedEntries...)
+		for _, version := range locked.Versions {
+			issues := goschema.CompareSchemaEntries(data.Name, version.entries(), readable)
+			for _, issue := range issues {
+				if !issue.Kind.Breaking() && issue.Kind != goschema.FieldRemoved {
+					continue
+				}
+				if version.isAcknowledged(issue.Field) {
+					continue
+				}
+				if c.acknowledged[data.Name+"."+issue.Field] {
+					version.Acknowledged = append(version.Acknowledged, issue.Field)
+					continue
+				}
+				breaking = append(breaking, issue)
+			}
+		}
+
+		current := makeLockedVersion(data.Entries)
+		n := len(locked.Versions)
+		if n == 0 || !reflect.DeepEqual(locked.Versions[n-1].Fields, current.Fields) {
+			locked.Versions = append(locked.Versions, current)
+		}
+	}
+	if len(breaking) > 0 {
+		return &BreakingChangeError{Issues: breaking}
+	}
+
+	updated, err := json.MarshalIndent(&lock, "", "  ")
+	if err != nil {
+		return err
+	}
+	updated = append(updated, '\n')
+	if string(updated) == string(content) {
+		return nil
+	}
+	return os.WriteFile(c.lockFilePath, updated, 0644)
+}
+
+func makeLockedVersion(entries []goschema.SchemaEntry) *lockedVersion {
+	version := &lockedVersion{Fields: make([]lockedField, len(entries))}
+	for i, entry := range entries {
+		version.Fields[i] = lockedField{
+			Name:        entry.Name,
+			Description: entry.Descriptor.String(),
+			Type:        entry.Descriptor,
+			Aliases:     entry.Aliases,
+			Required:    entry.Required,
+		}
+	}
+	return version
+}
+
+func (version *lockedVersion) entries() []goschema.SchemaEntry {
+	entries := make([]goschema.SchemaEntry, len(version.Fields))
+	for i, field := range version.Fields {
+		entries[i] = goschema.SchemaEntry{
+			Name:       field.Name,
+			Descriptor: field.Type,
+			Aliases:    field.Aliases,
+			Required:   field.Required,
+		}
+		if field.Type != nil {
+			entries[i].Type = field.Type.Code
+		}
+	}
+	return entries
+}
+
+func (version *lockedVersion) isAcknowledged(field string) bool {
+	for _, name := range version.Acknowledged {
+		if name == field {
+			return true
+		}
+	}
+	return false
+}
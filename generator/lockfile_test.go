@@ -0,0 +1,128 @@
+package generator
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+type lockedV1 struct {
+	Count int32
+	Name  string
+}
+
+type lockedWidened struct {
+	Count int64
+	Name  string
+}
+
+type lockedRemoved struct {
+	Count int64
+}
+
+type lockedRetyped struct {
+	Count int64
+	Name  int32
+}
+
+type lockedDeprecated struct {
+	Count int64
+	Name  string `schemaDeprecated:""`
+}
+
+// lockStep generates a version of the schema Locked.
+type lockStep struct {
+	typ         reflect.Type
+	acknowledge []string
+	breaking    []string // fields reported by the *BreakingChangeError
+}
+
+func TestLockFile(t *testing.T) {
+	v1 := lockStep{typ: reflect.TypeOf(lockedV1{})}
+	tests := []struct {
+		name  string
+		steps []lockStep
+	}{
+		{"unchanged", []lockStep{v1, v1}},
+		{"widened", []lockStep{v1, {typ: reflect.TypeOf(lockedWidened{})}}},
+		{"removed", []lockStep{v1, {typ: reflect.TypeOf(lockedRemoved{}), breaking: []string{"Name"}}}},
+		{"retyped", []lockStep{v1, {typ: reflect.TypeOf(lockedRetyped{}), breaking: []string{"Name"}}}},
+		{"deprecated", []lockStep{v1, {typ: reflect.TypeOf(lockedDeprecated{})}}},
+		{"acknowledged", []lockStep{
+			v1,
+			{typ: reflect.TypeOf(lockedRemoved{}), acknowledge: []string{"Locked.Name"}},
+			// the acknowledgement is recorded in the lock file
+			{typ: reflect.TypeOf(lockedRemoved{})},
+		}},
+		{"acknowledged other field", []lockStep{
+			v1,
+			{typ: reflect.TypeOf(lockedRemoved{}), acknowledge: []string{"Locked.Count"}, breaking: []string{"Name"}},
+		}},
+		{"breaking change of an older version", []lockStep{
+			v1,
+			{typ: reflect.TypeOf(lockedWidened{})},
+			{typ: reflect.TypeOf(lockedRetyped{}), breaking: []string{"Name", "Name"}},
+		}},
+	}
+	for _, test := range tests {
+		path := filepath.Join(t.TempDir(), "goschema.lock.json")
+		for i, step := range test.steps {
+			before, _ := os.ReadFile(path)
+			c := newTestContext(memoryOutput{})
+			c.SetLockFile(path)
+			c.AcknowledgeChanges(step.acknowledge...)
+			c.RequestSchema(step.typ, "Locked")
+			err := c.Generate()
+			var breaking []string
+			var breakingErr *BreakingChangeError
+			if errors.As(err, &breakingErr) {
+				for _, issue := range breakingErr.Issues {
+					breaking = append(breaking, issue.Field)
+				}
+			} else if err != nil {
+				t.Fatalf("%v, step %v: %v", test.name, i, err)
+			}
+			if !reflect.DeepEqual(breaking, step.breaking) {
+				t.Errorf("%v, step %v: breaking changes of %v", test.name, i, breaking)
+			}
+			after, readErr := os.ReadFile(path)
+			if readErr != nil {
+				t.Fatalf("%v, step %v: %v", test.name, i, readErr)
+			}
+			if err != nil && string(after) != string(before) {
+				t.Errorf("%v, step %v: the lock file was updated despite breaking changes", test.name, i)
+			}
+		}
+	}
+}
+
+func TestLockFileVersions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "goschema.lock.json")
+	for _, typ := range []reflect.Type{reflect.TypeOf(lockedV1{}), reflect.TypeOf(lockedV1{}), reflect.TypeOf(lockedWidened{})} {
+		c := newTestContext(memoryOutput{})
+		c.SetLockFile(path)
+		c.RequestSchema(typ, "Locked")
+		if err := c.Generate(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var lock lockFile
+	if err := json.Unmarshal(content, &lock); err != nil {
+		t.Fatal(err)
+	}
+	versions := lock.Schemata["Locked"].Versions
+	// unchanged schemata do not add versions
+	if len(versions) != 2 {
+		t.Fatalf("%v versions", len(versions))
+	}
+	if count := versions[1].Fields[0]; count.Name != "Count" || count.Description != "int64" {
+		t.Errorf("recorded %+v", count)
+	}
+}
@@ -9,7 +9,7 @@ import (
 	"github.com/chasingcarrots/goschema"
 )
 
-const mapReadCoreTemplate = `{{ .Token }}Entries := int({{ .Reader }}.ReadUInt32())
+const mapReadCoreTemplate = `{{ .Token }}Entries := {{ .Reader }}.ReadCollectionLength({{ .EntrySize }})
 var {{ .MapKeyName }} {{ .MapKeyType }}
 var {{ .MapValueName }} {{ .MapValueType }}
 {{ .Token }}Map := make(map[{{ .MapKeyType }}]{{ .MapValueType}})
@@ -40,6 +40,25 @@ const mapSchemaWriteTemplate = writeSaveBase +
 	mapWriteCoreTemplate +
 	writeRestoreBase
 
+const mapSizeTemplate = `{{ .Size }} += 1 + 1 + 4{{ if .KeyIsSchema }} + 4{{ end }}{{ if .ValueIsSchema }} + 4{{ end }}
+{{ if .KeySize -}}
+{{ .Size }} += len({{ .Dereference }}{{ .MapValue }}) * {{ .KeySize }}
+{{ end -}}
+{{ if .ValueSize -}}
+{{ .Size }} += len({{ .Dereference }}{{ .MapValue }}) * {{ .ValueSize }}
+{{ end -}}
+{{ if or .KeySizingCode .ValueSizingCode -}}
+for {{ .MapKeyName }}{{ if ne .MapValueName "_" }}, {{ .MapValueName }}{{ end }} := range {{ .Dereference }}{{ .MapValue }} {
+{{ .KeySizingCode }}{{ .ValueSizingCode }}}
+{{ end -}}
+`
+
+// values are copies, so they are stored again after their hooks have run
+const mapBeforeWriteTemplate = `for {{ .MapKeyName }}, {{ .MapValueName }} := range {{ .MapValue }} {
+{{ .InnerCode }}	{{ .MapValue }}[{{ .MapKeyName }}] = {{ .MapValueName }}
+}
+`
+
 type MapSerializer struct {
 	readTemplate               *template.Template
 	readSchemaTemplate         *template.Template
@@ -50,6 +69,11 @@ type MapSerializer struct {
 	writeSchemaTemplate         *template.Template
 	writeSchemaCoreTemplate     *template.Template
 	writeSchemaRegisterTemplate *template.Template
+
+	sizeTemplate           *template.Template
+	sizeSchemaCoreTemplate *template.Template
+
+	hookTemplate *template.Template
 }
 
 func NewMapSerializer() *MapSerializer {
@@ -62,6 +86,9 @@ func NewMapSerializer() *MapSerializer {
 		writeSchemaTemplate:         template.Must(template.New("Write").Parse(mapSchemaWriteTemplate)),
 		writeSchemaCoreTemplate:     template.Must(template.New("Write").Parse(schemaWriteCoreTemplate)),
 		writeSchemaRegisterTemplate: template.Must(template.New("Write").Parse(schemaWriteRegisterTemplate)),
+		sizeTemplate:                template.Must(template.New("Size").Parse(mapSizeTemplate)),
+		sizeSchemaCoreTemplate:      template.Must(template.New("Size").Parse(schemaSizeCoreTemplate)),
+		hookTemplate:                template.Must(template.New("BeforeWrite").Parse(mapBeforeWriteTemplate)),
 	}
 }
 
@@ -132,6 +159,7 @@ func (ms *MapSerializer) MakeReadingCode(context *Context, ptrValueTarget bool,
 			"MapValueReadingCode": valueReadingCode,
 			"MapKeyReadingCode":   keyReadingCode,
 			"Dereference":         makeDeref(ptrValueTarget),
+			"EntrySize":           target.Type.Key().Size() + target.Type.Elem().Size(),
 		},
 	)
 	return buf.String()
@@ -211,6 +239,88 @@ func (ms *MapSerializer) MakeWritingCode(context *Context, ptrValueTarget bool,
 	return buf.String()
 }
 
+// MakeBeforeWriteCode only runs the hooks of the values of the map, since
+// checkMapKeyHooks does not allow hooks in keys.
+func (ms *MapSerializer) MakeBeforeWriteCode(context *Context, ptrValueTarget bool, target Target, valueName string) string {
+	token := context.UniqueToken()
+	mapKeyName := token + "Key"
+	mapValueName := token + "Value"
+	innerCode := context.MakeBeforeWriteCode(false, TypeTarget(target.Type.Elem()), mapValueName)
+	if innerCode == "" {
+		return ""
+	}
+	if ptrValueTarget {
+		valueName = "(*" + valueName + ")"
+	}
+	var buf bytes.Buffer
+	ms.hookTemplate.Execute(&buf,
+		Lookup{
+			"MapValue":     valueName,
+			"MapKeyName":   mapKeyName,
+			"MapValueName": mapValueName,
+			"InnerCode":    innerCode,
+		},
+	)
+	return buf.String()
+}
+
+// makeSizingProlog returns the sizing code for the keys or values of a map. If
+// these are of fixed size, it returns their size instead.
+func (ms *MapSerializer) makeSizingProlog(context *Context, typ reflect.Type, sizeName, valueName string) (string, uint32, bool) {
+	target := TypeTarget(typ)
+	serializer := context.FindSerializer(target)
+	if serializer == nil {
+		panic("Could not find serializer")
+	}
+	if serializer.TypeCode(context, target) == goschema.SchemaType {
+		schema := context.GetSchema(typ)
+		var buf bytes.Buffer
+		ms.sizeSchemaCoreTemplate.Execute(&buf, Lookup{
+			"Size":        sizeName,
+			"SchemaValue": valueName,
+			"SchemaName":  schema.Name,
+			"Reference":   "&",
+		})
+		return buf.String(), 0, true
+	}
+	if serializer.IsVariableSize(context, target) {
+		return serializer.MakeSizingCode(context, false, target, sizeName, valueName), 0, false
+	}
+	return "", serializer.SizeOf(context, target), false
+}
+
+func (ms *MapSerializer) MakeSizingCode(context *Context, ptrValueTarget bool, target Target, sizeName, valueName string) string {
+	token := context.UniqueToken()
+	mapKeyName := token + "Key"
+	mapValueName := token + "Value"
+	keySizingCode, keySize, keyIsSchema := ms.makeSizingProlog(context, target.Type.Key(), sizeName, mapKeyName)
+	valueSizingCode, valueSize, valueIsSchema := ms.makeSizingProlog(context, target.Type.Elem(), sizeName, mapValueName)
+	if keySizingCode == "" {
+		mapKeyName = "_"
+	}
+	if valueSizingCode == "" {
+		mapValueName = "_"
+	}
+
+	var buf bytes.Buffer
+	ms.sizeTemplate.Execute(&buf,
+		Lookup{
+			"MapValue":        valueName,
+			"Size":            sizeName,
+			"MapKeyName":      mapKeyName,
+			"MapValueName":    mapValueName,
+			"KeySizingCode":   keySizingCode,
+			"ValueSizingCode": valueSizingCode,
+			"KeySize":         keySize,
+			"ValueSize":       valueSize,
+			"KeyIsSchema":     keyIsSchema,
+			"ValueIsSchema":   valueIsSchema,
+			"Dereference":     makeDeref(ptrValueTarget),
+		},
+	)
+	return buf.String()
+}
+
 func (*MapSerializer) SizeOf(*Context, Target) uint32 {
 	return 4
 }
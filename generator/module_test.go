@@ -0,0 +1,164 @@
+package generator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// generatedPackage is the import path of the temporary module or GOPATH
+// package that generated code is compiled in.
+const generatedPackage = "example.com/generated"
+
+// dirOutput writes the generated files to a directory.
+type dirOutput string
+
+func (d dirOutput) Write(name string, buf *bytes.Buffer) error {
+	source, err := formatGenerated(name, buf.Bytes())
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(string(d), name+".go"), source, 0644)
+}
+
+// formatGenerated formats the code generated for the file of the given name.
+func formatGenerated(name string, code []byte) ([]byte, error) {
+	source, err := format.Source(code)
+	if err != nil {
+		return nil, fmt.Errorf("%v: %v", name, err)
+	}
+	return source, nil
+}
+
+// runGenerated generates the schemata that request asks for into the package
+// generatedPackage/out and returns the output of running the program in
+// testdata/programs/<program> next to it. Both are compiled in a temporary
+// module that uses this repository, or in a temporary GOPATH if the repository
+// is not a module.
+func runGenerated(t *testing.T, program string, request func(c *Context)) string {
+	if testing.Short() {
+		t.Skip("compiles generated code")
+	}
+	dir, env := makeGeneratedPackage(t)
+	generateOut(t, dir, request)
+	source, err := os.ReadFile(filepath.Join("testdata", "programs", program, "main.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), source, 0644); err != nil {
+		t.Fatal(err)
+	}
+	cmd := exec.Command("go", "run", ".")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), env...)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("%v: %v\n%s", program, err, output)
+	}
+	return string(output)
+}
+
+// testGenerated generates the schemata that request asks for into the package
+// generatedPackage/out together with their tests, and runs the tests.
+func testGenerated(t *testing.T, request func(c *Context)) {
+	if testing.Short() {
+		t.Skip("compiles generated code")
+	}
+	dir, env := makeGeneratedPackage(t)
+	generateOut(t, dir, func(c *Context) {
+		c.GenerateTests()
+		request(c)
+	})
+	cmd := exec.Command("go", "test", "./out")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), env...)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("%v\n%s", err, output)
+	}
+}
+
+// generateOut generates the schemata that request asks for into the directory
+// out of the generated package in dir.
+func generateOut(t *testing.T, dir string, request func(c *Context)) {
+	outDir := filepath.Join(dir, "out")
+	if err := os.Mkdir(outDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	c := NewContext(dirOutput(outDir), generatedPackage+"/out", "../schemaimpl.got", reflect.TypeOf(0), reflect.TypeOf(0))
+	c.AddDefaultSerializers()
+	request(c)
+	if err := c.Generate(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// makeGeneratedPackage creates the directory of generatedPackage and returns
+// it together with the environment that the go command needs to build it.
+func makeGeneratedPackage(t *testing.T) (string, []string) {
+	goMod := goEnv(t, "GOMOD")
+	if goMod == "" || goMod == os.DevNull {
+		gopath := t.TempDir()
+		dir := filepath.Join(gopath, "src", filepath.FromSlash(generatedPackage))
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		return dir, []string{"GO111MODULE=off", "GOPATH=" + gopath + string(filepath.ListSeparator) + goEnv(t, "GOPATH")}
+	}
+
+	// the requirements and replacements of this module are copied, since
+	// those of dependencies are ignored
+	var mod struct {
+		Go      string
+		Module  struct{ Path string }
+		Require []struct{ Path, Version string }
+		Replace []struct {
+			Old, New struct{ Path, Version string }
+		}
+	}
+	modJSON, err := exec.Command("go", "mod", "edit", "-json", goMod).Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(modJSON, &mod); err != nil {
+		t.Fatal(err)
+	}
+	root := filepath.Dir(goMod)
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "module %v\n\ngo %v\n\nrequire %v v0.0.0\n", generatedPackage, mod.Go, mod.Module.Path)
+	for _, require := range mod.Require {
+		fmt.Fprintf(&buf, "require %v %v\n", require.Path, require.Version)
+	}
+	fmt.Fprintf(&buf, "replace %v => %v\n", mod.Module.Path, root)
+	for _, replace := range mod.Replace {
+		path := replace.New.Path
+		if replace.New.Version == "" && !filepath.IsAbs(path) {
+			path = filepath.Join(root, path)
+		}
+		fmt.Fprintf(&buf, "replace %v => %v\n", replace.Old.Path, strings.TrimSpace(path+" "+replace.New.Version))
+	}
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if sum, err := os.ReadFile(filepath.Join(root, "go.sum")); err == nil {
+		if err := os.WriteFile(filepath.Join(dir, "go.sum"), sum, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir, []string{"GOFLAGS=-mod=mod"}
+}
+
+func goEnv(t *testing.T, name string) string {
+	value, err := exec.Command("go", "env", name).Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.TrimSpace(string(value))
+}
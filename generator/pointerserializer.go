@@ -54,11 +54,23 @@ const schemaPointerWriteTemplate = "{{ .Writer }}.WriteUInt8(uint8(goschema.Type
 	pointerWriteCoreTemplate_B +
 	writeRestoreBase
 
+const pointerSizeTemplate = `{{ .Size }} += 1{{ if .IsSchema }} + 4{{ end }} + 1
+if {{ .PointerValue }} != nil {
+{{ .InnerSizingCode }}}
+`
+
+const pointerBeforeWriteTemplate = `if {{ .PointerValue }} != nil {
+{{ .InnerCode }}}
+`
+
 type PointerSerializer struct {
 	readTemplate        *template.Template
 	readSchemaTemplate  *template.Template
 	writeTemplate       *template.Template
 	writeSchemaTemplate *template.Template
+	sizeTemplate        *template.Template
+	sizeSchemaTemplate  *template.Template
+	hookTemplate        *template.Template
 }
 
 func NewPointerSerializer() *PointerSerializer {
@@ -67,6 +79,9 @@ func NewPointerSerializer() *PointerSerializer {
 		readSchemaTemplate:  template.Must(template.New("ReadSchema").Parse(schemaPointerReadTemplate)),
 		writeTemplate:       template.Must(template.New("Write").Parse(basicPointerWriteTemplate)),
 		writeSchemaTemplate: template.Must(template.New("WriteSchema").Parse(schemaPointerWriteTemplate)),
+		sizeTemplate:        template.Must(template.New("Size").Parse(pointerSizeTemplate)),
+		sizeSchemaTemplate:  template.Must(template.New("SizeSchema").Parse(schemaSizeCoreTemplate)),
+		hookTemplate:        template.Must(template.New("BeforeWrite").Parse(pointerBeforeWriteTemplate)),
 	}
 }
 
@@ -156,6 +171,65 @@ func (ls *PointerSerializer) MakeWritingCode(context *Context, ptrValueTarget bo
 	return buf.String()
 }
 
+func (ls *PointerSerializer) MakeSizingCode(context *Context, ptrValueTarget bool, target Target, sizeName, valueName string) string {
+	innerType := TypeTarget(target.Type.Elem())
+	serializer := context.FindSerializer(innerType)
+	if serializer == nil {
+		panic("Could not find serializer")
+	}
+	innerValueName := valueName
+	if ptrValueTarget {
+		innerValueName = "*" + valueName
+	}
+	isSchema := serializer.TypeCode(context, innerType) == goschema.SchemaType
+	var innerSizingCode string
+	if isSchema {
+		schema := context.GetSchema(target.Type.Elem())
+		var innerBuf bytes.Buffer
+		ls.sizeSchemaTemplate.Execute(&innerBuf,
+			Lookup{
+				"Size":        sizeName,
+				"SchemaValue": innerValueName,
+				"SchemaName":  schema.Name,
+				"Reference":   "",
+			},
+		)
+		innerSizingCode = innerBuf.String()
+	} else {
+		innerSizingCode = serializer.MakeSizingCode(context, true, innerType, sizeName, innerValueName)
+	}
+
+	var buf bytes.Buffer
+	ls.sizeTemplate.Execute(&buf,
+		Lookup{
+			"PointerValue":    valueName,
+			"Size":            sizeName,
+			"IsSchema":        isSchema,
+			"InnerSizingCode": innerSizingCode,
+		},
+	)
+	return buf.String()
+}
+
+func (ls *PointerSerializer) MakeBeforeWriteCode(context *Context, ptrValueTarget bool, target Target, valueName string) string {
+	innerValueName := valueName
+	if ptrValueTarget {
+		innerValueName = "*" + valueName
+	}
+	innerCode := context.MakeBeforeWriteCode(true, TypeTarget(target.Type.Elem()), innerValueName)
+	if innerCode == "" {
+		return ""
+	}
+	var buf bytes.Buffer
+	ls.hookTemplate.Execute(&buf,
+		Lookup{
+			"PointerValue": valueName,
+			"InnerCode":    innerCode,
+		},
+	)
+	return buf.String()
+}
+
 func (*PointerSerializer) SizeOf(*Context, Target) uint32 {
 	return 4
 }
@@ -15,7 +15,9 @@ const schemaReadTemplate = schemaReadRegisterTemplate +
 
 const schemaReadRegisterTemplate = "{{ .Token }}Schema := Read{{ .SchemaName }}Schema({{ .Reader }})\n"
 const readSaveBase = "{{ .Token }}ViewBase := {{ .Reader }}.Base()\n"
-const schemaReadCoreTemplate = "{{ .Token }}Schema.NakedRead({{ .Reader }}, {{ .Reference }}{{ .SchemaValue }}, context)\n"
+const schemaReadCoreTemplate = "if err := {{ .Token }}Schema.NakedRead({{ .Reader }}, {{ .Reference }}{{ .SchemaValue }}, context); err != nil {\n" +
+	"\treturn err\n" +
+	"}\n"
 const readRestoreBase = "{{ .Reader }}.View({{ .Reader }}.Local({{ .Token }}ViewBase))\n"
 
 const schemaWriteTemplate = schemaWriteRegisterTemplate +
@@ -25,18 +27,34 @@ const schemaWriteTemplate = schemaWriteRegisterTemplate +
 
 const schemaWriteRegisterTemplate = "{{ .Token }}Schema := Write{{ .SchemaName }}Schema({{ .Writer }})\n"
 const writeSaveBase = "{{ .Token }}ViewBase := {{ .Writer }}.Base()\n"
-const schemaWriteCoreTemplate = "{{ .Token }}Schema.NakedWrite({{ .Writer }}, {{ .Reference }}{{ .SchemaValue }}, context)\n"
+
+// nested objects are written with nakedWrite, since NakedWrite of the outermost
+// object runs all hooks
+const schemaWriteCoreTemplate = "{{ .Token }}Schema.nakedWrite({{ .Writer }}, {{ .Reference }}{{ .SchemaValue }}, context)\n"
 const writeRestoreBase = "{{ .Writer }}.View({{ .Writer }}.Local({{ .Token }}ViewBase))\n"
 
+// the schema index is written once for each field, list, map or pointer, so
+// it is not part of the core template
+const schemaSizeTemplate = "{{ .Size }} += 4\n" +
+	schemaSizeCoreTemplate
+
+const schemaSizeCoreTemplate = "{{ .Size }} += size{{ .SchemaName }}({{ .Reference }}{{ .SchemaValue }}, overhead)\n"
+
+const schemaBeforeWriteTemplate = "beforeWrite{{ .SchemaName }}({{ .Reference }}{{ .SchemaValue }}, context)\n"
+
 type SchemaSerializer struct {
 	readTemplate  *template.Template
 	writeTemplate *template.Template
+	sizeTemplate  *template.Template
+	hookTemplate  *template.Template
 }
 
 func NewSchemaSerializer() *SchemaSerializer {
 	return &SchemaSerializer{
 		readTemplate:  template.Must(template.New("Read").Parse(schemaReadTemplate)),
 		writeTemplate: template.Must(template.New("Write").Parse(schemaWriteTemplate)),
+		sizeTemplate:  template.Must(template.New("Size").Parse(schemaSizeTemplate)),
+		hookTemplate:  template.Must(template.New("BeforeWrite").Parse(schemaBeforeWriteTemplate)),
 	}
 }
 
@@ -74,6 +92,33 @@ func (ss *SchemaSerializer) MakeWritingCode(context *Context, ptrValueTarget boo
 	return buf.String()
 }
 
+func (ss *SchemaSerializer) MakeSizingCode(context *Context, ptrValueTarget bool, target Target, sizeName, valueName string) string {
+	schema := context.GetSchema(target.Type)
+	var buf bytes.Buffer
+	ss.sizeTemplate.Execute(&buf,
+		Lookup{
+			"SchemaValue": valueName,
+			"Size":        sizeName,
+			"Reference":   makeRef(ptrValueTarget),
+			"SchemaName":  schema.Name,
+		},
+	)
+	return buf.String()
+}
+
+func (ss *SchemaSerializer) MakeBeforeWriteCode(context *Context, ptrValueTarget bool, target Target, valueName string) string {
+	schema := context.GetSchema(target.Type)
+	var buf bytes.Buffer
+	ss.hookTemplate.Execute(&buf,
+		Lookup{
+			"SchemaValue": valueName,
+			"Reference":   makeRef(ptrValueTarget),
+			"SchemaName":  schema.Name,
+		},
+	)
+	return buf.String()
+}
+
 func (*SchemaSerializer) SizeOf(*Context, Target) uint32 {
 	return 4
 }
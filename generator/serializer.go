@@ -1,6 +1,7 @@
 package generator
 
 import (
+	"fmt"
 	"reflect"
 
 	"github.com/chasingcarrots/goschema"
@@ -13,12 +14,26 @@ type TypeSerializer interface {
 	WriteByValue(*Context, Target) bool
 	MakeReadingCode(context *Context, ptrValueTarget bool, target Target, readerName, valueName string) string
 	MakeWritingCode(context *Context, ptrValueTarget bool, target Target, writerName, valueName string) string
+	// MakeSizingCode returns code that adds the number of bytes that the value
+	// occupies in the serialized data to the variable named sizeName. Code for
+	// schemata may refer to a variable called overhead that holds the number of
+	// bytes written per object in addition to its data (length and checksum).
+	MakeSizingCode(context *Context, ptrValueTarget bool, target Target, sizeName, valueName string) string
 	SizeOf(*Context, Target) uint32
 	CanSerialize(*Context, Target) bool
 	TypeCode(*Context, Target) goschema.TypeCode
 	Initialize(*Context)
 }
 
+// HookSerializer is implemented by serializers of values that may contain
+// objects, such as lists. The BeforeSchemaWrite hooks of all objects in a value
+// run before any of it is written on every writer, since they may change the
+// sizes that forward writers compute up front. MakeBeforeWriteCode returns the
+// code that runs them.
+type HookSerializer interface {
+	MakeBeforeWriteCode(context *Context, ptrValueTarget bool, target Target, valueName string) string
+}
+
 type Target struct {
 	Type reflect.Type
 	Tags reflect.StructTag
@@ -27,3 +42,7 @@ type Target struct {
 func TypeTarget(typ reflect.Type) Target {
 	return Target{Type: typ}
 }
+
+func makeFixedSizingCode(sizeName string, size uint32) string {
+	return fmt.Sprintf("%v += %v\n", sizeName, size)
+}
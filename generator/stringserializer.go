@@ -8,8 +8,8 @@ import (
 	"github.com/chasingcarrots/goschema"
 )
 
-const stringReadCoreTemplate = `{{ .Token }}Length := {{ .Reader }}.ReadUInt32()
-{{ .Dereference }}{{ .Value }} = {{ .Cast -}} ( {{- .Reader -}} .ReadString(int({{ .Token }}Length)))
+const stringReadCoreTemplate = `{{ .Token }}Length := {{ .Reader }}.ReadStringLength()
+{{ .Dereference }}{{ .Value }} = {{ .Cast -}} ( {{- .Reader -}} .ReadString({{ .Token }}Length))
 `
 
 const stringReadTemplate = stringReadCoreTemplate
@@ -20,15 +20,19 @@ const stringWriteCoreTemplate = `{{ .Writer }}.WriteUInt32(uint32(len({{ .Derefe
 
 const stringWriteTemplate = stringWriteCoreTemplate
 
+const stringSizeTemplate = "{{ .Size }} += 4 + len({{ .Dereference }}{{ .Value }})\n"
+
 type StringSerializer struct {
 	readTemplate  *template.Template
 	writeTemplate *template.Template
+	sizeTemplate  *template.Template
 }
 
 func NewStringSerializer() *StringSerializer {
 	return &StringSerializer{
 		readTemplate:  template.Must(template.New("Read").Parse(stringReadTemplate)),
 		writeTemplate: template.Must(template.New("Write").Parse(stringWriteTemplate)),
+		sizeTemplate:  template.Must(template.New("Size").Parse(stringSizeTemplate)),
 	}
 }
 
@@ -62,6 +66,18 @@ func (ss *StringSerializer) MakeWritingCode(context *Context, ptrValueTarget boo
 	return buf.String()
 }
 
+func (ss *StringSerializer) MakeSizingCode(context *Context, ptrValueTarget bool, target Target, sizeName, valueName string) string {
+	var buf bytes.Buffer
+	ss.sizeTemplate.Execute(&buf,
+		Lookup{
+			"Value":       valueName,
+			"Size":        sizeName,
+			"Dereference": makeDeref(ptrValueTarget),
+		},
+	)
+	return buf.String()
+}
+
 func (*StringSerializer) SizeOf(*Context, Target) uint32 {
 	return 4
 }
@@ -0,0 +1,143 @@
+// Package fixtures contains types that the tests of the generator generate
+// schemata for. The generated code is compiled in a temporary module.
+package fixtures
+
+import "strings"
+
+// Tag grows every time it is written, so that writing it with a stale size
+// or running its hook twice is noticed.
+type Tag struct {
+	Name string
+}
+
+func (tag *Tag) BeforeSchemaWrite(context int) {
+	tag.Name = strings.ToUpper(tag.Name) + "!"
+}
+
+// Section contains tags, but has no hook of its own.
+type Section struct {
+	Tags []Tag
+}
+
+// Document contains tags in every kind of container.
+type Document struct {
+	Title    string
+	Main     *Tag
+	Tags     []Tag
+	ByName   map[string]Tag
+	Sections []Section
+}
+
+func (doc *Document) BeforeSchemaWrite(context int) {
+	doc.Title += "."
+}
+
+// Node nests itself, so that data can be nested arbitrarily deep.
+type Node struct {
+	Value    int32
+	Children []Node
+}
+
+// Item is requested under its Go name, like every type in Catalog.
+type Item struct {
+	Name   string
+	Count  int32
+	Weight float64 `schemaDefault:"1.5"`
+}
+
+// Catalog contains items in every kind of container and has no hooks, so
+// Marshal writes the same data and schemata as the generated code.
+type Catalog struct {
+	Title    string `schemaName:"Name"`
+	Featured *Item
+	Items    []Item
+	Links    []*Item
+	ByName   map[string]Item
+	Groups   map[int32][]Item
+	Labels   []string
+	Hidden   int `schemaIgnore:""`
+}
+
+// Vector2 is serialized in place by an InlineSerializer.
+type Vector2 struct {
+	X, Y float32
+}
+
+// Scalars has a field of every basic type, renamed fields, fields serialized
+// by an InlineSerializer and pointers to collections.
+type Scalars struct {
+	Flag     bool
+	Int      int
+	Int8     int8
+	Int16    int16 `schemaName:"Short"`
+	Int32    int32
+	Int64    int64
+	UInt     uint
+	UInt8    uint8
+	UInt16   uint16
+	UInt32   uint32 `schemaName:"Unsigned"`
+	UInt64   uint64
+	Float32  float32
+	Float64  float64
+	Text     string
+	Position Vector2
+	Path     []Vector2
+	Origin   *Vector2
+	Counts   *[]int32
+	Lookup   *map[string]int16
+}
+
+// Limits has bounded numbers, so that random values outside of the bounds fail
+// validation when they are written.
+type Limits struct {
+	Small   int8    `schemaMin:"-100" schemaMax:"100"`
+	Full    int8    `schemaMin:"-128" schemaMax:"127"`
+	Count   uint64  `schemaMin:"10"`
+	Offset  int     `schemaMax:"-1"`
+	Ratio   float32 `schemaMin:"0" schemaMax:"1"`
+	Weight  float64 `schemaMin:"0.5"`
+	Profile Profile
+}
+
+// Profile sets a field that is not serialized with its defaults, so a value
+// that was read differs from the value that was written.
+type Profile struct {
+	Name     string
+	Revision int `schemaIgnore:""`
+}
+
+func (profile *Profile) SetSchemaDefaults() {
+	profile.Revision = 1
+}
+
+// Ranged has a constrained field and a deprecated one, which callers no longer
+// fill. Its default satisfies its constraint when it is read from new data.
+type Ranged struct {
+	Level  int32 `schemaMin:"1" schemaMax:"10"`
+	Legacy int32 `schemaDeprecated:"" schemaMin:"1" schemaDefault:"1"`
+}
+
+// Ranges contains constrained objects and a constrained float, which may be NaN.
+type Ranges struct {
+	Ranges []Ranged
+	Ratio  float64 `schemaMin:"0" schemaMax:"1"`
+}
+
+// Account initializes its nested settings in SetSchemaDefaults. Older versions
+// of it had no settings.
+type Account struct {
+	Gold     int32
+	Settings AccountSettings
+}
+
+func (account *Account) SetSchemaDefaults() {
+	account.Settings.Slots = 32
+}
+
+type AccountSettings struct {
+	Slots, Pages int32
+}
+
+func (settings *AccountSettings) SetSchemaDefaults() {
+	settings.Slots, settings.Pages = 16, 2
+}
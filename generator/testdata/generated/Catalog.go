@@ -0,0 +1,1058 @@
+package generated
+
+import (
+	"github.com/chasingcarrots/goschema"
+	"io"
+
+	"github.com/chasingcarrots/goschema/generator/testdata/fixtures"
+)
+
+const CatalogSchemaID goschema.SchemaID = 3
+const CatalogSchemaFingerprint uint64 = 0xddcbb3bd6adb899f
+const CatalogSchemaName = "Catalog"
+
+type CatalogSchema struct {
+	NameOffset     int
+	FeaturedOffset int
+	ItemsOffset    int
+	LinksOffset    int
+	ByNameOffset   int
+	GroupsOffset   int
+	LabelsOffset   int
+
+	descriptor []goschema.SchemaEntry
+}
+
+func NewCatalogSchema() *CatalogSchema {
+	schema := CatalogSchema{}
+	schema.init()
+	return &schema
+}
+
+func (schema *CatalogSchema) ID() goschema.SchemaID {
+	return CatalogSchemaID
+}
+
+func (schema *CatalogSchema) Fingerprint() uint64 {
+	return CatalogSchemaFingerprint
+}
+
+func (schema *CatalogSchema) Name() string {
+	return CatalogSchemaName
+}
+
+func (schema *CatalogSchema) GoType() string {
+	return "github.com/chasingcarrots/goschema/generator/testdata/fixtures.Catalog"
+}
+
+func (schema *CatalogSchema) Fill(entries []goschema.SchemaEntry) {
+	schema.NameOffset = -1
+	schema.FeaturedOffset = -1
+	schema.ItemsOffset = -1
+	schema.LinksOffset = -1
+	schema.ByNameOffset = -1
+	schema.GroupsOffset = -1
+	schema.LabelsOffset = -1
+
+	for i := range entries {
+		switch entries[i].Name {
+		case "Name":
+			// the current name of a field takes precedence over its aliases
+			if schema.descriptor[0].CanRead(&entries[i]) {
+				schema.NameOffset = int(entries[i].Offset)
+			}
+		case "Featured":
+			// the current name of a field takes precedence over its aliases
+			if schema.descriptor[1].CanRead(&entries[i]) {
+				schema.FeaturedOffset = int(entries[i].Offset)
+			}
+		case "Items":
+			// the current name of a field takes precedence over its aliases
+			if schema.descriptor[2].CanRead(&entries[i]) {
+				schema.ItemsOffset = int(entries[i].Offset)
+			}
+		case "Links":
+			// the current name of a field takes precedence over its aliases
+			if schema.descriptor[3].CanRead(&entries[i]) {
+				schema.LinksOffset = int(entries[i].Offset)
+			}
+		case "ByName":
+			// the current name of a field takes precedence over its aliases
+			if schema.descriptor[4].CanRead(&entries[i]) {
+				schema.ByNameOffset = int(entries[i].Offset)
+			}
+		case "Groups":
+			// the current name of a field takes precedence over its aliases
+			if schema.descriptor[5].CanRead(&entries[i]) {
+				schema.GroupsOffset = int(entries[i].Offset)
+			}
+		case "Labels":
+			// the current name of a field takes precedence over its aliases
+			if schema.descriptor[6].CanRead(&entries[i]) {
+				schema.LabelsOffset = int(entries[i].Offset)
+			}
+		}
+	}
+}
+
+func (schema *CatalogSchema) init() {
+	if schema.descriptor == nil {
+		schema.descriptor = make([]goschema.SchemaEntry, 0, 7)
+		schema.descriptor = append(schema.descriptor,
+			goschema.SchemaEntry{
+				Name:       "Name",
+				Type:       goschema.TypeCode(16),
+				Offset:     0,
+				Descriptor: &goschema.TypeDescriptor{Code: goschema.TypeCode(16)},
+			},
+		)
+		schema.NameOffset = 0
+		schema.descriptor = append(schema.descriptor,
+			goschema.SchemaEntry{
+				Name:       "Featured",
+				Type:       goschema.TypeCode(17),
+				Offset:     4,
+				Descriptor: &goschema.TypeDescriptor{Code: goschema.TypeCode(17), Elem: &goschema.TypeDescriptor{Code: goschema.TypeCode(0), Schema: "Item"}},
+			},
+		)
+		schema.FeaturedOffset = 4
+		schema.descriptor = append(schema.descriptor,
+			goschema.SchemaEntry{
+				Name:       "Items",
+				Type:       goschema.TypeCode(2),
+				Offset:     8,
+				Descriptor: &goschema.TypeDescriptor{Code: goschema.TypeCode(2), Elem: &goschema.TypeDescriptor{Code: goschema.TypeCode(0), Schema: "Item"}},
+			},
+		)
+		schema.ItemsOffset = 8
+		schema.descriptor = append(schema.descriptor,
+			goschema.SchemaEntry{
+				Name:       "Links",
+				Type:       goschema.TypeCode(2),
+				Offset:     12,
+				Descriptor: &goschema.TypeDescriptor{Code: goschema.TypeCode(2), Elem: &goschema.TypeDescriptor{Code: goschema.TypeCode(17), Elem: &goschema.TypeDescriptor{Code: goschema.TypeCode(0), Schema: "Item"}}},
+			},
+		)
+		schema.LinksOffset = 12
+		schema.descriptor = append(schema.descriptor,
+			goschema.SchemaEntry{
+				Name:       "ByName",
+				Type:       goschema.TypeCode(1),
+				Offset:     16,
+				Descriptor: &goschema.TypeDescriptor{Code: goschema.TypeCode(1), Key: &goschema.TypeDescriptor{Code: goschema.TypeCode(16)}, Elem: &goschema.TypeDescriptor{Code: goschema.TypeCode(0), Schema: "Item"}},
+			},
+		)
+		schema.ByNameOffset = 16
+		schema.descriptor = append(schema.descriptor,
+			goschema.SchemaEntry{
+				Name:       "Groups",
+				Type:       goschema.TypeCode(1),
+				Offset:     20,
+				Descriptor: &goschema.TypeDescriptor{Code: goschema.TypeCode(1), Key: &goschema.TypeDescriptor{Code: goschema.TypeCode(10)}, Elem: &goschema.TypeDescriptor{Code: goschema.TypeCode(2), Elem: &goschema.TypeDescriptor{Code: goschema.TypeCode(0), Schema: "Item"}}},
+			},
+		)
+		schema.GroupsOffset = 20
+		schema.descriptor = append(schema.descriptor,
+			goschema.SchemaEntry{
+				Name:       "Labels",
+				Type:       goschema.TypeCode(2),
+				Offset:     24,
+				Descriptor: &goschema.TypeDescriptor{Code: goschema.TypeCode(2), Elem: &goschema.TypeDescriptor{Code: goschema.TypeCode(16)}},
+			},
+		)
+		schema.LabelsOffset = 24
+	}
+}
+
+// DescribeDeprecated returns the entries of the fields that are read, but no
+// longer written.
+func (schema *CatalogSchema) DescribeDeprecated() []goschema.SchemaEntry {
+	return nil
+}
+
+func (schema *CatalogSchema) Describe() []goschema.SchemaEntry {
+	return schema.descriptor
+}
+
+func ReadCatalogSchema(reader *goschema.SchemaReader) *CatalogSchema {
+	schemaIdx := int(reader.ReadUInt32())
+	existingSchema, schemaEntries := reader.FindSchema(int(schemaIdx))
+	schema, ok := existingSchema.(*CatalogSchema)
+	if existingSchema == nil || !ok {
+		schema = NewCatalogSchema()
+		reader.VerifySchemaName(schemaIdx, CatalogSchemaName)
+		// the offsets of a new schema already match data with the same fingerprint
+		if fingerprint, ok := reader.SchemaFingerprint(schemaIdx); !ok || fingerprint != CatalogSchemaFingerprint {
+			schema.Fill(schemaEntries)
+		}
+		reader.RegisterSchema(schemaIdx, schema)
+	}
+	return schema
+}
+
+func WriteCatalogSchema(writer *goschema.SchemaWriter) *CatalogSchema {
+	schemaEntry, ok := writer.FindSchema(goschema.SchemaID(CatalogSchemaID))
+	schemaIdx := schemaEntry.Index()
+	schema, ok := schemaEntry.Schema().(*CatalogSchema)
+	if !ok {
+		schema = NewCatalogSchema()
+		schemaIdx = writer.RegisterSchema(schema)
+	}
+	writer.WriteUInt32(uint32(schemaIdx))
+	return schema
+}
+
+func (schema *CatalogSchema) SingleRead(reader *goschema.SchemaReader, value *fixtures.Catalog, context int) error {
+	originalBase := reader.Base()
+	err := schema.NakedRead(reader, value, context)
+	reader.View(reader.Local(originalBase))
+	return err
+}
+
+func (schema *CatalogSchema) NakedRead(reader *goschema.SchemaReader, value *fixtures.Catalog, context int) error {
+	nextOffset, err := reader.BeginObject("Catalog")
+	if err != nil {
+		return err
+	}
+	defer reader.EndObject()
+	if err := schema.ReadNameInto(reader, &value.Title, context); err != nil {
+		return err
+	}
+	if err := schema.ReadFeaturedInto(reader, &value.Featured, context); err != nil {
+		return goschema.PrefixFieldPath(err, "Featured")
+	}
+	if err := schema.ReadItemsInto(reader, &value.Items, context); err != nil {
+		return goschema.PrefixFieldPath(err, "Items")
+	}
+	if err := schema.ReadLinksInto(reader, &value.Links, context); err != nil {
+		return goschema.PrefixFieldPath(err, "Links")
+	}
+	if err := schema.ReadByNameInto(reader, &value.ByName, context); err != nil {
+		return goschema.PrefixFieldPath(err, "ByName")
+	}
+	if err := schema.ReadGroupsInto(reader, &value.Groups, context); err != nil {
+		return goschema.PrefixFieldPath(err, "Groups")
+	}
+	if err := schema.ReadLabelsInto(reader, &value.Labels, context); err != nil {
+		return err
+	}
+	reader.Seek(reader.Local(nextOffset), io.SeekStart)
+	return reader.Err()
+}
+
+// CatalogView gives access to single fields of an object without
+// reading the whole object.
+type CatalogView struct {
+	schema *CatalogSchema
+	reader *goschema.SchemaReader
+	base   int64 // global offset of the object's data
+}
+
+// NakedView creates a view of the object at the current position of the reader
+// and moves the reader past the object, like NakedRead would. The fields of the
+// object are only read when they are requested from the view. If the object
+// carries a checksum, NakedView verifies it and thus reads the whole object.
+func (schema *CatalogSchema) NakedView(reader *goschema.SchemaReader) (CatalogView, error) {
+	originalBase := reader.Base()
+	nextOffset, err := reader.BeginObject("Catalog")
+	if err != nil {
+		reader.View(reader.Local(originalBase))
+		return CatalogView{}, err
+	}
+	reader.EndObject()
+	view := CatalogView{
+		schema: schema,
+		reader: reader,
+		base:   reader.Base(),
+	}
+	reader.Seek(reader.Local(nextOffset), io.SeekStart)
+	reader.View(reader.Local(originalBase))
+	return view, nil
+}
+
+func (schema *CatalogSchema) SingleWrite(writer *goschema.SchemaWriter, value *fixtures.Catalog, context int) {
+	originalBase := writer.Base()
+	schema.NakedWrite(writer, value, context)
+	writer.View(writer.Local(originalBase))
+}
+
+func (schema *CatalogSchema) NakedWrite(writer *goschema.SchemaWriter, value *fixtures.Catalog, context int) {
+	schema.nakedWrite(writer, value, context)
+}
+
+// SingleWritePrepared is like SingleWrite, but does not run the
+// BeforeSchemaWrite hooks. It writes a value that RunBeforeWriteHooks has
+// prepared, e.g. after checking its EncodedSize.
+func (schema *CatalogSchema) SingleWritePrepared(writer *goschema.SchemaWriter, value *fixtures.Catalog, context int) {
+	originalBase := writer.Base()
+	schema.nakedWrite(writer, value, context)
+	writer.View(writer.Local(originalBase))
+}
+
+// RunBeforeWriteHooks runs the BeforeSchemaWrite hooks of the value and of all
+// objects that it contains, like SingleWrite does before writing anything.
+func (schema *CatalogSchema) RunBeforeWriteHooks(value *fixtures.Catalog, context int) {
+}
+
+// nakedWrite writes an object whose hooks have run already. It is called for
+// nested objects.
+func (schema *CatalogSchema) nakedWrite(writer *goschema.SchemaWriter, value *fixtures.Catalog, context int) {
+	if writer.Err() != nil {
+		return
+	}
+	if writer.IsForward() {
+		schema.forwardNakedWrite(writer, value, context)
+		return
+	}
+	startOffset := writer.BeginObject()
+	writer.Seek(28, io.SeekCurrent)
+	schema.WriteName(writer, value.Title, context)
+	schema.WriteFeatured(writer, value.Featured, context)
+	if writer.FailedIn("Featured") {
+		return
+	}
+	schema.WriteItems(writer, value.Items, context)
+	if writer.FailedIn("Items") {
+		return
+	}
+	schema.WriteLinks(writer, value.Links, context)
+	if writer.FailedIn("Links") {
+		return
+	}
+	schema.WriteByName(writer, value.ByName, context)
+	if writer.FailedIn("ByName") {
+		return
+	}
+	schema.WriteGroups(writer, value.Groups, context)
+	if writer.FailedIn("Groups") {
+		return
+	}
+	schema.WriteLabels(writer, value.Labels, context)
+	writer.EndObject(startOffset)
+}
+
+// forwardNakedWrite produces the same data as NakedWrite without seeking by
+// computing all references up front.
+func (schema *CatalogSchema) forwardNakedWrite(writer *goschema.SchemaWriter, value *fixtures.Catalog, context int) {
+	overhead := writer.ObjectOverhead()
+	startOffset := writer.BeginSizedObject(sizeCatalog(value, overhead) - overhead)
+	reference := 28
+	writer.WriteUInt32(uint32(reference))
+	reference += 4 + len(value.Title)
+
+	writer.WriteUInt32(uint32(reference))
+	reference += 1 + 4 + 1
+	if value.Featured != nil {
+		reference += sizeItem(value.Featured, overhead)
+	}
+
+	writer.WriteUInt32(uint32(reference))
+	reference += 1 + 4 + 4
+	for v7I := range value.Items {
+		reference += sizeItem(&value.Items[v7I], overhead)
+	}
+
+	writer.WriteUInt32(uint32(reference))
+	reference += 1 + 4
+	for v13I := range value.Links {
+		reference += 1 + 4 + 1
+		if value.Links[v13I] != nil {
+			reference += sizeItem(value.Links[v13I], overhead)
+		}
+	}
+
+	writer.WriteUInt32(uint32(reference))
+	reference += 1 + 1 + 4 + 4
+	for v22Key, v22Value := range value.ByName {
+		reference += 4 + len(v22Key)
+		reference += sizeItem(&v22Value, overhead)
+	}
+
+	writer.WriteUInt32(uint32(reference))
+	reference += 1 + 1 + 4
+	reference += len(value.Groups) * 4
+	for _, v31Value := range value.Groups {
+		reference += 1 + 4 + 4
+		for v32I := range v31Value {
+			reference += sizeItem(&v31Value[v32I], overhead)
+		}
+	}
+
+	writer.WriteUInt32(uint32(reference))
+	reference += 1 + 4
+	for v38I := range value.Labels {
+		reference += 4 + len(value.Labels[v38I])
+	}
+
+	schema.forwardWriteName(writer, value.Title, context)
+	schema.forwardWriteFeatured(writer, value.Featured, context)
+	if writer.FailedIn("Featured") {
+		return
+	}
+	schema.forwardWriteItems(writer, value.Items, context)
+	if writer.FailedIn("Items") {
+		return
+	}
+	schema.forwardWriteLinks(writer, value.Links, context)
+	if writer.FailedIn("Links") {
+		return
+	}
+	schema.forwardWriteByName(writer, value.ByName, context)
+	if writer.FailedIn("ByName") {
+		return
+	}
+	schema.forwardWriteGroups(writer, value.Groups, context)
+	if writer.FailedIn("Groups") {
+		return
+	}
+	schema.forwardWriteLabels(writer, value.Labels, context)
+	writer.EndObject(startOffset)
+}
+
+// EncodedSize returns the number of bytes that SingleWritePrepared writes for
+// the given value, assuming that checksums are disabled. It does not include
+// the index of the schema written by WriteCatalogSchema, and it does
+// not run any hooks.
+func (schema *CatalogSchema) EncodedSize(value *fixtures.Catalog) int {
+	return sizeCatalog(value, 4)
+}
+
+// sizeCatalog returns the number of bytes written by NakedWrite for
+// the given value if each object takes up overhead bytes besides its data.
+func sizeCatalog(value *fixtures.Catalog, overhead int) int {
+	size := overhead + 28
+	size += 4 + len(value.Title)
+
+	size += 1 + 4 + 1
+	if value.Featured != nil {
+		size += sizeItem(value.Featured, overhead)
+	}
+
+	size += 1 + 4 + 4
+	for v6I := range value.Items {
+		size += sizeItem(&value.Items[v6I], overhead)
+	}
+
+	size += 1 + 4
+	for v12I := range value.Links {
+		size += 1 + 4 + 1
+		if value.Links[v12I] != nil {
+			size += sizeItem(value.Links[v12I], overhead)
+		}
+	}
+
+	size += 1 + 1 + 4 + 4
+	for v21Key, v21Value := range value.ByName {
+		size += 4 + len(v21Key)
+		size += sizeItem(&v21Value, overhead)
+	}
+
+	size += 1 + 1 + 4
+	size += len(value.Groups) * 4
+	for _, v29Value := range value.Groups {
+		size += 1 + 4 + 4
+		for v30I := range v29Value {
+			size += sizeItem(&v29Value[v30I], overhead)
+		}
+	}
+
+	size += 1 + 4
+	for v37I := range value.Labels {
+		size += 4 + len(value.Labels[v37I])
+	}
+
+	return size
+}
+
+func (schema *CatalogSchema) WriteName(writer *goschema.SchemaWriter, value string, context int) {
+	offset := writer.Offset()
+	writer.Seek(int64(schema.NameOffset), io.SeekStart)
+	writer.WriteUInt32(uint32(offset))
+	writer.Seek(offset, io.SeekStart)
+	writer.WriteUInt32(uint32(len(value)))
+	writer.WriteString(value)
+}
+
+func (schema *CatalogSchema) forwardWriteName(writer *goschema.SchemaWriter, value string, context int) {
+	writer.WriteUInt32(uint32(len(value)))
+	writer.WriteString(value)
+
+}
+
+func (schema *CatalogSchema) ReadNameInto(reader *goschema.SchemaReader, value *string, context int) error {
+	if schema.NameOffset == -1 {
+		var tmp string
+		*value = tmp
+		return nil
+	}
+	offset := reader.Offset()
+	reader.Seek(int64(schema.NameOffset), io.SeekStart)
+	fieldOffset := reader.ReadUInt32()
+	reader.Seek(int64(fieldOffset), io.SeekStart)
+	v1Length := reader.ReadStringLength()
+	*value = string(reader.ReadString(v1Length))
+
+	reader.Seek(offset, io.SeekStart)
+	return reader.Err()
+}
+
+func (view CatalogView) GetName(context int) (string, error) {
+	var value string
+	reader := view.reader
+	originalBase := reader.Base()
+	offset := reader.GlobalOffset()
+	reader.View(reader.Local(view.base))
+	err := view.schema.ReadNameInto(reader, &value, context)
+	reader.View(reader.Local(originalBase))
+	reader.Seek(reader.Local(offset), io.SeekStart)
+	return value, err
+}
+
+func (schema *CatalogSchema) GetName(reader *goschema.SchemaReader, context int) (string, error) {
+	offset := reader.Offset()
+	view, err := schema.NakedView(reader)
+	reader.Seek(offset, io.SeekStart)
+	if err != nil {
+		var value string
+		return value, err
+	}
+	return view.GetName(context)
+}
+
+func (schema *CatalogSchema) WriteFeatured(writer *goschema.SchemaWriter, value *fixtures.Item, context int) {
+	offset := writer.Offset()
+	writer.Seek(int64(schema.FeaturedOffset), io.SeekStart)
+	writer.WriteUInt32(uint32(offset))
+	writer.Seek(offset, io.SeekStart)
+	writer.WriteUInt8(uint8(goschema.TypeCode(0)))
+	v2ViewBase := writer.Base()
+	v2Schema := WriteItemSchema(writer)
+	if value != nil {
+		writer.WriteBool(true)
+		v2Schema.nakedWrite(writer, value, context)
+	} else {
+		writer.WriteBool(false)
+	}
+	writer.View(writer.Local(v2ViewBase))
+}
+
+func (schema *CatalogSchema) forwardWriteFeatured(writer *goschema.SchemaWriter, value *fixtures.Item, context int) {
+	writer.WriteUInt8(uint8(goschema.TypeCode(0)))
+	v2ViewBase := writer.Base()
+	v2Schema := WriteItemSchema(writer)
+	if value != nil {
+		writer.WriteBool(true)
+		v2Schema.nakedWrite(writer, value, context)
+	} else {
+		writer.WriteBool(false)
+	}
+	writer.View(writer.Local(v2ViewBase))
+
+}
+
+func (schema *CatalogSchema) ReadFeaturedInto(reader *goschema.SchemaReader, value **fixtures.Item, context int) error {
+	if schema.FeaturedOffset == -1 {
+		var tmp *fixtures.Item
+		*value = tmp
+		return nil
+	}
+	offset := reader.Offset()
+	reader.Seek(int64(schema.FeaturedOffset), io.SeekStart)
+	fieldOffset := reader.ReadUInt32()
+	reader.Seek(int64(fieldOffset), io.SeekStart)
+	_ = reader.ReadUInt8() // ignore typecode
+	v3Schema := ReadItemSchema(reader)
+	v3ViewBase := reader.Base()
+	v3NonNil := reader.ReadBool()
+	if v3NonNil {
+		var v3 fixtures.Item
+		if err := v3Schema.NakedRead(reader, &v3, context); err != nil {
+			return err
+		}
+		*value = &v3
+	} else {
+		*value = nil
+	}
+	reader.View(reader.Local(v3ViewBase))
+
+	reader.Seek(offset, io.SeekStart)
+	return reader.Err()
+}
+
+func (view CatalogView) GetFeatured(context int) (*fixtures.Item, error) {
+	var value *fixtures.Item
+	reader := view.reader
+	originalBase := reader.Base()
+	offset := reader.GlobalOffset()
+	reader.View(reader.Local(view.base))
+	err := view.schema.ReadFeaturedInto(reader, &value, context)
+	reader.View(reader.Local(originalBase))
+	reader.Seek(reader.Local(offset), io.SeekStart)
+	return value, err
+}
+
+func (schema *CatalogSchema) GetFeatured(reader *goschema.SchemaReader, context int) (*fixtures.Item, error) {
+	offset := reader.Offset()
+	view, err := schema.NakedView(reader)
+	reader.Seek(offset, io.SeekStart)
+	if err != nil {
+		var value *fixtures.Item
+		return value, err
+	}
+	return view.GetFeatured(context)
+}
+
+func (schema *CatalogSchema) WriteItems(writer *goschema.SchemaWriter, value []fixtures.Item, context int) {
+	offset := writer.Offset()
+	writer.Seek(int64(schema.ItemsOffset), io.SeekStart)
+	writer.WriteUInt32(uint32(offset))
+	writer.Seek(offset, io.SeekStart)
+	writer.WriteUInt8(uint8(goschema.TypeCode(0)))
+	v4ViewBase := writer.Base()
+	v4Schema := WriteItemSchema(writer)
+	v4Length := len(value)
+	writer.WriteUInt32(uint32(v4Length))
+	for v4I := 0; v4I < v4Length; v4I++ {
+		v4Schema.nakedWrite(writer, &value[v4I], context)
+	}
+	writer.View(writer.Local(v4ViewBase))
+}
+
+func (schema *CatalogSchema) forwardWriteItems(writer *goschema.SchemaWriter, value []fixtures.Item, context int) {
+	writer.WriteUInt8(uint8(goschema.TypeCode(0)))
+	v4ViewBase := writer.Base()
+	v4Schema := WriteItemSchema(writer)
+	v4Length := len(value)
+	writer.WriteUInt32(uint32(v4Length))
+	for v4I := 0; v4I < v4Length; v4I++ {
+		v4Schema.nakedWrite(writer, &value[v4I], context)
+	}
+	writer.View(writer.Local(v4ViewBase))
+
+}
+
+func (schema *CatalogSchema) ReadItemsInto(reader *goschema.SchemaReader, value *[]fixtures.Item, context int) error {
+	if schema.ItemsOffset == -1 {
+		var tmp []fixtures.Item
+		*value = tmp
+		return nil
+	}
+	offset := reader.Offset()
+	reader.Seek(int64(schema.ItemsOffset), io.SeekStart)
+	fieldOffset := reader.ReadUInt32()
+	reader.Seek(int64(fieldOffset), io.SeekStart)
+	_ = reader.ReadUInt8() // ignore typecode
+	v5Schema := ReadItemSchema(reader)
+	v5ViewBase := reader.Base()
+	v5Entries := reader.ReadCollectionLength(32)
+	v5Slice := make([]fixtures.Item, v5Entries, v5Entries)
+	for v5I := 0; v5I < v5Entries; v5I++ {
+		if err := v5Schema.NakedRead(reader, &v5Slice[v5I], context); err != nil {
+			return err
+		}
+	}
+	*value = v5Slice
+	reader.View(reader.Local(v5ViewBase))
+
+	reader.Seek(offset, io.SeekStart)
+	return reader.Err()
+}
+
+func (view CatalogView) GetItems(context int) ([]fixtures.Item, error) {
+	var value []fixtures.Item
+	reader := view.reader
+	originalBase := reader.Base()
+	offset := reader.GlobalOffset()
+	reader.View(reader.Local(view.base))
+	err := view.schema.ReadItemsInto(reader, &value, context)
+	reader.View(reader.Local(originalBase))
+	reader.Seek(reader.Local(offset), io.SeekStart)
+	return value, err
+}
+
+func (schema *CatalogSchema) GetItems(reader *goschema.SchemaReader, context int) ([]fixtures.Item, error) {
+	offset := reader.Offset()
+	view, err := schema.NakedView(reader)
+	reader.Seek(offset, io.SeekStart)
+	if err != nil {
+		var value []fixtures.Item
+		return value, err
+	}
+	return view.GetItems(context)
+}
+
+func (schema *CatalogSchema) WriteLinks(writer *goschema.SchemaWriter, value []*fixtures.Item, context int) {
+	offset := writer.Offset()
+	writer.Seek(int64(schema.LinksOffset), io.SeekStart)
+	writer.WriteUInt32(uint32(offset))
+	writer.Seek(offset, io.SeekStart)
+	writer.WriteUInt8(uint8(goschema.TypeCode(17)))
+	v8Length := len(value)
+	writer.WriteUInt32(uint32(v8Length))
+	for v8I := 0; v8I < v8Length; v8I++ {
+		writer.WriteUInt8(uint8(goschema.TypeCode(0)))
+		v9ViewBase := writer.Base()
+		v9Schema := WriteItemSchema(writer)
+		if value[v8I] != nil {
+			writer.WriteBool(true)
+			v9Schema.nakedWrite(writer, value[v8I], context)
+		} else {
+			writer.WriteBool(false)
+		}
+		writer.View(writer.Local(v9ViewBase))
+
+	}
+}
+
+func (schema *CatalogSchema) forwardWriteLinks(writer *goschema.SchemaWriter, value []*fixtures.Item, context int) {
+	writer.WriteUInt8(uint8(goschema.TypeCode(17)))
+	v8Length := len(value)
+	writer.WriteUInt32(uint32(v8Length))
+	for v8I := 0; v8I < v8Length; v8I++ {
+		writer.WriteUInt8(uint8(goschema.TypeCode(0)))
+		v9ViewBase := writer.Base()
+		v9Schema := WriteItemSchema(writer)
+		if value[v8I] != nil {
+			writer.WriteBool(true)
+			v9Schema.nakedWrite(writer, value[v8I], context)
+		} else {
+			writer.WriteBool(false)
+		}
+		writer.View(writer.Local(v9ViewBase))
+
+	}
+
+}
+
+func (schema *CatalogSchema) ReadLinksInto(reader *goschema.SchemaReader, value *[]*fixtures.Item, context int) error {
+	if schema.LinksOffset == -1 {
+		var tmp []*fixtures.Item
+		*value = tmp
+		return nil
+	}
+	offset := reader.Offset()
+	reader.Seek(int64(schema.LinksOffset), io.SeekStart)
+	fieldOffset := reader.ReadUInt32()
+	reader.Seek(int64(fieldOffset), io.SeekStart)
+	_ = reader.ReadUInt8() // ignore typecode
+	v10Entries := reader.ReadCollectionLength(8)
+	v10Slice := make([]*fixtures.Item, v10Entries, v10Entries)
+	for v10I := 0; v10I < v10Entries; v10I++ {
+		_ = reader.ReadUInt8() // ignore typecode
+		v11Schema := ReadItemSchema(reader)
+		v11ViewBase := reader.Base()
+		v11NonNil := reader.ReadBool()
+		if v11NonNil {
+			var v11 fixtures.Item
+			if err := v11Schema.NakedRead(reader, &v11, context); err != nil {
+				return err
+			}
+			v10Slice[v10I] = &v11
+		} else {
+			v10Slice[v10I] = nil
+		}
+		reader.View(reader.Local(v11ViewBase))
+
+	}
+	*value = v10Slice
+
+	reader.Seek(offset, io.SeekStart)
+	return reader.Err()
+}
+
+func (view CatalogView) GetLinks(context int) ([]*fixtures.Item, error) {
+	var value []*fixtures.Item
+	reader := view.reader
+	originalBase := reader.Base()
+	offset := reader.GlobalOffset()
+	reader.View(reader.Local(view.base))
+	err := view.schema.ReadLinksInto(reader, &value, context)
+	reader.View(reader.Local(originalBase))
+	reader.Seek(reader.Local(offset), io.SeekStart)
+	return value, err
+}
+
+func (schema *CatalogSchema) GetLinks(reader *goschema.SchemaReader, context int) ([]*fixtures.Item, error) {
+	offset := reader.Offset()
+	view, err := schema.NakedView(reader)
+	reader.Seek(offset, io.SeekStart)
+	if err != nil {
+		var value []*fixtures.Item
+		return value, err
+	}
+	return view.GetLinks(context)
+}
+
+func (schema *CatalogSchema) WriteByName(writer *goschema.SchemaWriter, value map[string]fixtures.Item, context int) {
+	offset := writer.Offset()
+	writer.Seek(int64(schema.ByNameOffset), io.SeekStart)
+	writer.WriteUInt32(uint32(offset))
+	writer.Seek(offset, io.SeekStart)
+	writer.WriteUInt8(uint8(goschema.TypeCode(16)))
+	writer.WriteUInt8(uint8(goschema.TypeCode(0)))
+	v17Schema := WriteItemSchema(writer)
+	v14ViewBase := writer.Base()
+	writer.WriteUInt32(uint32(len(value)))
+	for v14Key, v14Value := range value {
+		writer.WriteUInt32(uint32(len(v14Key)))
+		writer.WriteString(v14Key)
+
+		v17Schema.nakedWrite(writer, &v14Value, context)
+
+	}
+	writer.View(writer.Local(v14ViewBase))
+}
+
+func (schema *CatalogSchema) forwardWriteByName(writer *goschema.SchemaWriter, value map[string]fixtures.Item, context int) {
+	writer.WriteUInt8(uint8(goschema.TypeCode(16)))
+	writer.WriteUInt8(uint8(goschema.TypeCode(0)))
+	v17Schema := WriteItemSchema(writer)
+	v14ViewBase := writer.Base()
+	writer.WriteUInt32(uint32(len(value)))
+	for v14Key, v14Value := range value {
+		writer.WriteUInt32(uint32(len(v14Key)))
+		writer.WriteString(v14Key)
+
+		v17Schema.nakedWrite(writer, &v14Value, context)
+
+	}
+	writer.View(writer.Local(v14ViewBase))
+
+}
+
+func (schema *CatalogSchema) ReadByNameInto(reader *goschema.SchemaReader, value *map[string]fixtures.Item, context int) error {
+	if schema.ByNameOffset == -1 {
+		var tmp map[string]fixtures.Item
+		*value = tmp
+		return nil
+	}
+	offset := reader.Offset()
+	reader.Seek(int64(schema.ByNameOffset), io.SeekStart)
+	fieldOffset := reader.ReadUInt32()
+	reader.Seek(int64(fieldOffset), io.SeekStart)
+	_ = reader.ReadUInt8() // ignore typecode
+	_ = reader.ReadUInt8() // ignore typecode
+	v20Schema := ReadItemSchema(reader)
+	v18ViewBase := reader.Base()
+	v18Entries := reader.ReadCollectionLength(48)
+	var v18Key string
+	var v18Value fixtures.Item
+	v18Map := make(map[string]fixtures.Item)
+	for v18I := 0; v18I < v18Entries; v18I++ {
+		v19Length := reader.ReadStringLength()
+		v18Key = string(reader.ReadString(v19Length))
+
+		if err := v20Schema.NakedRead(reader, &v18Value, context); err != nil {
+			return err
+		}
+
+		v18Map[v18Key] = v18Value
+	}
+	*value = v18Map
+	reader.View(reader.Local(v18ViewBase))
+
+	reader.Seek(offset, io.SeekStart)
+	return reader.Err()
+}
+
+func (view CatalogView) GetByName(context int) (map[string]fixtures.Item, error) {
+	var value map[string]fixtures.Item
+	reader := view.reader
+	originalBase := reader.Base()
+	offset := reader.GlobalOffset()
+	reader.View(reader.Local(view.base))
+	err := view.schema.ReadByNameInto(reader, &value, context)
+	reader.View(reader.Local(originalBase))
+	reader.Seek(reader.Local(offset), io.SeekStart)
+	return value, err
+}
+
+func (schema *CatalogSchema) GetByName(reader *goschema.SchemaReader, context int) (map[string]fixtures.Item, error) {
+	offset := reader.Offset()
+	view, err := schema.NakedView(reader)
+	reader.Seek(offset, io.SeekStart)
+	if err != nil {
+		var value map[string]fixtures.Item
+		return value, err
+	}
+	return view.GetByName(context)
+}
+
+func (schema *CatalogSchema) WriteGroups(writer *goschema.SchemaWriter, value map[int32][]fixtures.Item, context int) {
+	offset := writer.Offset()
+	writer.Seek(int64(schema.GroupsOffset), io.SeekStart)
+	writer.WriteUInt32(uint32(offset))
+	writer.Seek(offset, io.SeekStart)
+	writer.WriteUInt8(uint8(goschema.TypeCode(10)))
+	writer.WriteUInt8(uint8(goschema.TypeCode(2)))
+	writer.WriteUInt32(uint32(len(value)))
+	for v23Key, v23Value := range value {
+		writer.WriteInt32(int32(v23Key))
+		writer.WriteUInt8(uint8(goschema.TypeCode(0)))
+		v26ViewBase := writer.Base()
+		v26Schema := WriteItemSchema(writer)
+		v26Length := len(v23Value)
+		writer.WriteUInt32(uint32(v26Length))
+		for v26I := 0; v26I < v26Length; v26I++ {
+			v26Schema.nakedWrite(writer, &v23Value[v26I], context)
+		}
+		writer.View(writer.Local(v26ViewBase))
+
+	}
+}
+
+func (schema *CatalogSchema) forwardWriteGroups(writer *goschema.SchemaWriter, value map[int32][]fixtures.Item, context int) {
+	writer.WriteUInt8(uint8(goschema.TypeCode(10)))
+	writer.WriteUInt8(uint8(goschema.TypeCode(2)))
+	writer.WriteUInt32(uint32(len(value)))
+	for v23Key, v23Value := range value {
+		writer.WriteInt32(int32(v23Key))
+		writer.WriteUInt8(uint8(goschema.TypeCode(0)))
+		v26ViewBase := writer.Base()
+		v26Schema := WriteItemSchema(writer)
+		v26Length := len(v23Value)
+		writer.WriteUInt32(uint32(v26Length))
+		for v26I := 0; v26I < v26Length; v26I++ {
+			v26Schema.nakedWrite(writer, &v23Value[v26I], context)
+		}
+		writer.View(writer.Local(v26ViewBase))
+
+	}
+
+}
+
+func (schema *CatalogSchema) ReadGroupsInto(reader *goschema.SchemaReader, value *map[int32][]fixtures.Item, context int) error {
+	if schema.GroupsOffset == -1 {
+		var tmp map[int32][]fixtures.Item
+		*value = tmp
+		return nil
+	}
+	offset := reader.Offset()
+	reader.Seek(int64(schema.GroupsOffset), io.SeekStart)
+	fieldOffset := reader.ReadUInt32()
+	reader.Seek(int64(fieldOffset), io.SeekStart)
+	_ = reader.ReadUInt8() // ignore typecode
+	_ = reader.ReadUInt8() // ignore typecode
+	v27Entries := reader.ReadCollectionLength(28)
+	var v27Key int32
+	var v27Value []fixtures.Item
+	v27Map := make(map[int32][]fixtures.Item)
+	for v27I := 0; v27I < v27Entries; v27I++ {
+		v27Key = int32(reader.ReadInt32())
+		_ = reader.ReadUInt8() // ignore typecode
+		v28Schema := ReadItemSchema(reader)
+		v28ViewBase := reader.Base()
+		v28Entries := reader.ReadCollectionLength(32)
+		v28Slice := make([]fixtures.Item, v28Entries, v28Entries)
+		for v28I := 0; v28I < v28Entries; v28I++ {
+			if err := v28Schema.NakedRead(reader, &v28Slice[v28I], context); err != nil {
+				return err
+			}
+		}
+		v27Value = v28Slice
+		reader.View(reader.Local(v28ViewBase))
+
+		v27Map[v27Key] = v27Value
+	}
+	*value = v27Map
+
+	reader.Seek(offset, io.SeekStart)
+	return reader.Err()
+}
+
+func (view CatalogView) GetGroups(context int) (map[int32][]fixtures.Item, error) {
+	var value map[int32][]fixtures.Item
+	reader := view.reader
+	originalBase := reader.Base()
+	offset := reader.GlobalOffset()
+	reader.View(reader.Local(view.base))
+	err := view.schema.ReadGroupsInto(reader, &value, context)
+	reader.View(reader.Local(originalBase))
+	reader.Seek(reader.Local(offset), io.SeekStart)
+	return value, err
+}
+
+func (schema *CatalogSchema) GetGroups(reader *goschema.SchemaReader, context int) (map[int32][]fixtures.Item, error) {
+	offset := reader.Offset()
+	view, err := schema.NakedView(reader)
+	reader.Seek(offset, io.SeekStart)
+	if err != nil {
+		var value map[int32][]fixtures.Item
+		return value, err
+	}
+	return view.GetGroups(context)
+}
+
+func (schema *CatalogSchema) WriteLabels(writer *goschema.SchemaWriter, value []string, context int) {
+	offset := writer.Offset()
+	writer.Seek(int64(schema.LabelsOffset), io.SeekStart)
+	writer.WriteUInt32(uint32(offset))
+	writer.Seek(offset, io.SeekStart)
+	writer.WriteUInt8(uint8(goschema.TypeCode(16)))
+	v33Length := len(value)
+	writer.WriteUInt32(uint32(v33Length))
+	for v33I := 0; v33I < v33Length; v33I++ {
+		writer.WriteUInt32(uint32(len(value[v33I])))
+		writer.WriteString(value[v33I])
+
+	}
+}
+
+func (schema *CatalogSchema) forwardWriteLabels(writer *goschema.SchemaWriter, value []string, context int) {
+	writer.WriteUInt8(uint8(goschema.TypeCode(16)))
+	v33Length := len(value)
+	writer.WriteUInt32(uint32(v33Length))
+	for v33I := 0; v33I < v33Length; v33I++ {
+		writer.WriteUInt32(uint32(len(value[v33I])))
+		writer.WriteString(value[v33I])
+
+	}
+
+}
+
+func (schema *CatalogSchema) ReadLabelsInto(reader *goschema.SchemaReader, value *[]string, context int) error {
+	if schema.LabelsOffset == -1 {
+		var tmp []string
+		*value = tmp
+		return nil
+	}
+	offset := reader.Offset()
+	reader.Seek(int64(schema.LabelsOffset), io.SeekStart)
+	fieldOffset := reader.ReadUInt32()
+	reader.Seek(int64(fieldOffset), io.SeekStart)
+	_ = reader.ReadUInt8() // ignore typecode
+	v35Entries := reader.ReadCollectionLength(16)
+	v35Slice := make([]string, v35Entries, v35Entries)
+	for v35I := 0; v35I < v35Entries; v35I++ {
+		v36Length := reader.ReadStringLength()
+		v35Slice[v35I] = string(reader.ReadString(v36Length))
+
+	}
+	*value = v35Slice
+
+	reader.Seek(offset, io.SeekStart)
+	return reader.Err()
+}
+
+func (view CatalogView) GetLabels(context int) ([]string, error) {
+	var value []string
+	reader := view.reader
+	originalBase := reader.Base()
+	offset := reader.GlobalOffset()
+	reader.View(reader.Local(view.base))
+	err := view.schema.ReadLabelsInto(reader, &value, context)
+	reader.View(reader.Local(originalBase))
+	reader.Seek(reader.Local(offset), io.SeekStart)
+	return value, err
+}
+
+func (schema *CatalogSchema) GetLabels(reader *goschema.SchemaReader, context int) ([]string, error) {
+	offset := reader.Offset()
+	view, err := schema.NakedView(reader)
+	reader.Seek(offset, io.SeekStart)
+	if err != nil {
+		var value []string
+		return value, err
+	}
+	return view.GetLabels(context)
+}
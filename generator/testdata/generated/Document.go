@@ -0,0 +1,801 @@
+package generated
+
+import (
+	"github.com/chasingcarrots/goschema"
+	"io"
+
+	"github.com/chasingcarrots/goschema/generator/testdata/fixtures"
+)
+
+const DocumentSchemaID goschema.SchemaID = 0
+const DocumentSchemaFingerprint uint64 = 0xd5d07044cfe65740
+const DocumentSchemaName = "Document"
+
+type DocumentSchema struct {
+	TitleOffset    int
+	MainOffset     int
+	TagsOffset     int
+	ByNameOffset   int
+	SectionsOffset int
+
+	descriptor []goschema.SchemaEntry
+}
+
+func NewDocumentSchema() *DocumentSchema {
+	schema := DocumentSchema{}
+	schema.init()
+	return &schema
+}
+
+func (schema *DocumentSchema) ID() goschema.SchemaID {
+	return DocumentSchemaID
+}
+
+func (schema *DocumentSchema) Fingerprint() uint64 {
+	return DocumentSchemaFingerprint
+}
+
+func (schema *DocumentSchema) Name() string {
+	return DocumentSchemaName
+}
+
+func (schema *DocumentSchema) GoType() string {
+	return "github.com/chasingcarrots/goschema/generator/testdata/fixtures.Document"
+}
+
+func (schema *DocumentSchema) Fill(entries []goschema.SchemaEntry) {
+	schema.TitleOffset = -1
+	schema.MainOffset = -1
+	schema.TagsOffset = -1
+	schema.ByNameOffset = -1
+	schema.SectionsOffset = -1
+
+	for i := range entries {
+		switch entries[i].Name {
+		case "Title":
+			// the current name of a field takes precedence over its aliases
+			if schema.descriptor[0].CanRead(&entries[i]) {
+				schema.TitleOffset = int(entries[i].Offset)
+			}
+		case "Main":
+			// the current name of a field takes precedence over its aliases
+			if schema.descriptor[1].CanRead(&entries[i]) {
+				schema.MainOffset = int(entries[i].Offset)
+			}
+		case "Tags":
+			// the current name of a field takes precedence over its aliases
+			if schema.descriptor[2].CanRead(&entries[i]) {
+				schema.TagsOffset = int(entries[i].Offset)
+			}
+		case "ByName":
+			// the current name of a field takes precedence over its aliases
+			if schema.descriptor[3].CanRead(&entries[i]) {
+				schema.ByNameOffset = int(entries[i].Offset)
+			}
+		case "Sections":
+			// the current name of a field takes precedence over its aliases
+			if schema.descriptor[4].CanRead(&entries[i]) {
+				schema.SectionsOffset = int(entries[i].Offset)
+			}
+		}
+	}
+}
+
+func (schema *DocumentSchema) init() {
+	if schema.descriptor == nil {
+		schema.descriptor = make([]goschema.SchemaEntry, 0, 5)
+		schema.descriptor = append(schema.descriptor,
+			goschema.SchemaEntry{
+				Name:       "Title",
+				Type:       goschema.TypeCode(16),
+				Offset:     0,
+				Descriptor: &goschema.TypeDescriptor{Code: goschema.TypeCode(16)},
+			},
+		)
+		schema.TitleOffset = 0
+		schema.descriptor = append(schema.descriptor,
+			goschema.SchemaEntry{
+				Name:       "Main",
+				Type:       goschema.TypeCode(17),
+				Offset:     4,
+				Descriptor: &goschema.TypeDescriptor{Code: goschema.TypeCode(17), Elem: &goschema.TypeDescriptor{Code: goschema.TypeCode(0), Schema: "TagAutoGen"}},
+			},
+		)
+		schema.MainOffset = 4
+		schema.descriptor = append(schema.descriptor,
+			goschema.SchemaEntry{
+				Name:       "Tags",
+				Type:       goschema.TypeCode(2),
+				Offset:     8,
+				Descriptor: &goschema.TypeDescriptor{Code: goschema.TypeCode(2), Elem: &goschema.TypeDescriptor{Code: goschema.TypeCode(0), Schema: "TagAutoGen"}},
+			},
+		)
+		schema.TagsOffset = 8
+		schema.descriptor = append(schema.descriptor,
+			goschema.SchemaEntry{
+				Name:       "ByName",
+				Type:       goschema.TypeCode(1),
+				Offset:     12,
+				Descriptor: &goschema.TypeDescriptor{Code: goschema.TypeCode(1), Key: &goschema.TypeDescriptor{Code: goschema.TypeCode(16)}, Elem: &goschema.TypeDescriptor{Code: goschema.TypeCode(0), Schema: "TagAutoGen"}},
+			},
+		)
+		schema.ByNameOffset = 12
+		schema.descriptor = append(schema.descriptor,
+			goschema.SchemaEntry{
+				Name:       "Sections",
+				Type:       goschema.TypeCode(2),
+				Offset:     16,
+				Descriptor: &goschema.TypeDescriptor{Code: goschema.TypeCode(2), Elem: &goschema.TypeDescriptor{Code: goschema.TypeCode(0), Schema: "SectionAutoGen"}},
+			},
+		)
+		schema.SectionsOffset = 16
+	}
+}
+
+// DescribeDeprecated returns the entries of the fields that are read, but no
+// longer written.
+func (schema *DocumentSchema) DescribeDeprecated() []goschema.SchemaEntry {
+	return nil
+}
+
+func (schema *DocumentSchema) Describe() []goschema.SchemaEntry {
+	return schema.descriptor
+}
+
+func ReadDocumentSchema(reader *goschema.SchemaReader) *DocumentSchema {
+	schemaIdx := int(reader.ReadUInt32())
+	existingSchema, schemaEntries := reader.FindSchema(int(schemaIdx))
+	schema, ok := existingSchema.(*DocumentSchema)
+	if existingSchema == nil || !ok {
+		schema = NewDocumentSchema()
+		reader.VerifySchemaName(schemaIdx, DocumentSchemaName)
+		// the offsets of a new schema already match data with the same fingerprint
+		if fingerprint, ok := reader.SchemaFingerprint(schemaIdx); !ok || fingerprint != DocumentSchemaFingerprint {
+			schema.Fill(schemaEntries)
+		}
+		reader.RegisterSchema(schemaIdx, schema)
+	}
+	return schema
+}
+
+func WriteDocumentSchema(writer *goschema.SchemaWriter) *DocumentSchema {
+	schemaEntry, ok := writer.FindSchema(goschema.SchemaID(DocumentSchemaID))
+	schemaIdx := schemaEntry.Index()
+	schema, ok := schemaEntry.Schema().(*DocumentSchema)
+	if !ok {
+		schema = NewDocumentSchema()
+		schemaIdx = writer.RegisterSchema(schema)
+	}
+	writer.WriteUInt32(uint32(schemaIdx))
+	return schema
+}
+
+func (schema *DocumentSchema) SingleRead(reader *goschema.SchemaReader, value *fixtures.Document, context int) error {
+	originalBase := reader.Base()
+	err := schema.NakedRead(reader, value, context)
+	reader.View(reader.Local(originalBase))
+	return err
+}
+
+func (schema *DocumentSchema) NakedRead(reader *goschema.SchemaReader, value *fixtures.Document, context int) error {
+	nextOffset, err := reader.BeginObject("Document")
+	if err != nil {
+		return err
+	}
+	defer reader.EndObject()
+	if err := schema.ReadTitleInto(reader, &value.Title, context); err != nil {
+		return err
+	}
+	if err := schema.ReadMainInto(reader, &value.Main, context); err != nil {
+		return goschema.PrefixFieldPath(err, "Main")
+	}
+	if err := schema.ReadTagsInto(reader, &value.Tags, context); err != nil {
+		return goschema.PrefixFieldPath(err, "Tags")
+	}
+	if err := schema.ReadByNameInto(reader, &value.ByName, context); err != nil {
+		return goschema.PrefixFieldPath(err, "ByName")
+	}
+	if err := schema.ReadSectionsInto(reader, &value.Sections, context); err != nil {
+		return goschema.PrefixFieldPath(err, "Sections")
+	}
+	reader.Seek(reader.Local(nextOffset), io.SeekStart)
+	return reader.Err()
+}
+
+// DocumentView gives access to single fields of an object without
+// reading the whole object.
+type DocumentView struct {
+	schema *DocumentSchema
+	reader *goschema.SchemaReader
+	base   int64 // global offset of the object's data
+}
+
+// NakedView creates a view of the object at the current position of the reader
+// and moves the reader past the object, like NakedRead would. The fields of the
+// object are only read when they are requested from the view. If the object
+// carries a checksum, NakedView verifies it and thus reads the whole object.
+func (schema *DocumentSchema) NakedView(reader *goschema.SchemaReader) (DocumentView, error) {
+	originalBase := reader.Base()
+	nextOffset, err := reader.BeginObject("Document")
+	if err != nil {
+		reader.View(reader.Local(originalBase))
+		return DocumentView{}, err
+	}
+	reader.EndObject()
+	view := DocumentView{
+		schema: schema,
+		reader: reader,
+		base:   reader.Base(),
+	}
+	reader.Seek(reader.Local(nextOffset), io.SeekStart)
+	reader.View(reader.Local(originalBase))
+	return view, nil
+}
+
+func (schema *DocumentSchema) SingleWrite(writer *goschema.SchemaWriter, value *fixtures.Document, context int) {
+	originalBase := writer.Base()
+	schema.NakedWrite(writer, value, context)
+	writer.View(writer.Local(originalBase))
+}
+
+func (schema *DocumentSchema) NakedWrite(writer *goschema.SchemaWriter, value *fixtures.Document, context int) {
+	if writer.Err() == nil {
+		// the hooks of all nested objects run first, since forward writers
+		// compute sizes up front
+		beforeWriteDocument(value, context)
+	}
+	schema.nakedWrite(writer, value, context)
+}
+
+// SingleWritePrepared is like SingleWrite, but does not run the
+// BeforeSchemaWrite hooks. It writes a value that RunBeforeWriteHooks has
+// prepared, e.g. after checking its EncodedSize.
+func (schema *DocumentSchema) SingleWritePrepared(writer *goschema.SchemaWriter, value *fixtures.Document, context int) {
+	originalBase := writer.Base()
+	schema.nakedWrite(writer, value, context)
+	writer.View(writer.Local(originalBase))
+}
+
+// RunBeforeWriteHooks runs the BeforeSchemaWrite hooks of the value and of all
+// objects that it contains, like SingleWrite does before writing anything.
+func (schema *DocumentSchema) RunBeforeWriteHooks(value *fixtures.Document, context int) {
+	beforeWriteDocument(value, context)
+}
+
+// nakedWrite writes an object whose hooks have run already. It is called for
+// nested objects.
+func (schema *DocumentSchema) nakedWrite(writer *goschema.SchemaWriter, value *fixtures.Document, context int) {
+	if writer.Err() != nil {
+		return
+	}
+	if writer.IsForward() {
+		schema.forwardNakedWrite(writer, value, context)
+		return
+	}
+	startOffset := writer.BeginObject()
+	writer.Seek(20, io.SeekCurrent)
+	schema.WriteTitle(writer, value.Title, context)
+	schema.WriteMain(writer, value.Main, context)
+	if writer.FailedIn("Main") {
+		return
+	}
+	schema.WriteTags(writer, value.Tags, context)
+	if writer.FailedIn("Tags") {
+		return
+	}
+	schema.WriteByName(writer, value.ByName, context)
+	if writer.FailedIn("ByName") {
+		return
+	}
+	schema.WriteSections(writer, value.Sections, context)
+	if writer.FailedIn("Sections") {
+		return
+	}
+	writer.EndObject(startOffset)
+}
+
+// forwardNakedWrite produces the same data as NakedWrite without seeking by
+// computing all references up front.
+func (schema *DocumentSchema) forwardNakedWrite(writer *goschema.SchemaWriter, value *fixtures.Document, context int) {
+	overhead := writer.ObjectOverhead()
+	startOffset := writer.BeginSizedObject(sizeDocument(value, overhead) - overhead)
+	reference := 20
+	writer.WriteUInt32(uint32(reference))
+	reference += 4 + len(value.Title)
+
+	writer.WriteUInt32(uint32(reference))
+	reference += 1 + 4 + 1
+	if value.Main != nil {
+		reference += sizeTagAutoGen(value.Main, overhead)
+	}
+
+	writer.WriteUInt32(uint32(reference))
+	reference += 1 + 4 + 4
+	for v7I := range value.Tags {
+		reference += sizeTagAutoGen(&value.Tags[v7I], overhead)
+	}
+
+	writer.WriteUInt32(uint32(reference))
+	reference += 1 + 1 + 4 + 4
+	for v17Key, v17Value := range value.ByName {
+		reference += 4 + len(v17Key)
+		reference += sizeTagAutoGen(&v17Value, overhead)
+	}
+
+	writer.WriteUInt32(uint32(reference))
+	reference += 1 + 4 + 4
+	for v22I := range value.Sections {
+		reference += sizeSectionAutoGen(&value.Sections[v22I], overhead)
+	}
+
+	schema.forwardWriteTitle(writer, value.Title, context)
+	schema.forwardWriteMain(writer, value.Main, context)
+	if writer.FailedIn("Main") {
+		return
+	}
+	schema.forwardWriteTags(writer, value.Tags, context)
+	if writer.FailedIn("Tags") {
+		return
+	}
+	schema.forwardWriteByName(writer, value.ByName, context)
+	if writer.FailedIn("ByName") {
+		return
+	}
+	schema.forwardWriteSections(writer, value.Sections, context)
+	if writer.FailedIn("Sections") {
+		return
+	}
+	writer.EndObject(startOffset)
+}
+
+// EncodedSize returns the number of bytes that SingleWritePrepared writes for
+// the given value, assuming that checksums are disabled. It does not include
+// the index of the schema written by WriteDocumentSchema, and it does
+// not run any hooks.
+func (schema *DocumentSchema) EncodedSize(value *fixtures.Document) int {
+	return sizeDocument(value, 4)
+}
+
+// beforeWriteDocument runs the BeforeSchemaWrite hooks of the value and
+// of all objects that it contains.
+func beforeWriteDocument(value *fixtures.Document, context int) {
+	value.BeforeSchemaWrite(context)
+	if value.Main != nil {
+		beforeWriteTagAutoGen(value.Main, context)
+	}
+
+	for v8I := range value.Tags {
+		beforeWriteTagAutoGen(&value.Tags[v8I], context)
+	}
+
+	for v18Key, v18Value := range value.ByName {
+		beforeWriteTagAutoGen(&v18Value, context)
+		value.ByName[v18Key] = v18Value
+	}
+
+	for v23I := range value.Sections {
+		beforeWriteSectionAutoGen(&value.Sections[v23I], context)
+	}
+
+}
+
+// sizeDocument returns the number of bytes written by NakedWrite for
+// the given value if each object takes up overhead bytes besides its data.
+func sizeDocument(value *fixtures.Document, overhead int) int {
+	size := overhead + 20
+	size += 4 + len(value.Title)
+
+	size += 1 + 4 + 1
+	if value.Main != nil {
+		size += sizeTagAutoGen(value.Main, overhead)
+	}
+
+	size += 1 + 4 + 4
+	for v6I := range value.Tags {
+		size += sizeTagAutoGen(&value.Tags[v6I], overhead)
+	}
+
+	size += 1 + 1 + 4 + 4
+	for v16Key, v16Value := range value.ByName {
+		size += 4 + len(v16Key)
+		size += sizeTagAutoGen(&v16Value, overhead)
+	}
+
+	size += 1 + 4 + 4
+	for v21I := range value.Sections {
+		size += sizeSectionAutoGen(&value.Sections[v21I], overhead)
+	}
+
+	return size
+}
+
+func (schema *DocumentSchema) WriteTitle(writer *goschema.SchemaWriter, value string, context int) {
+	offset := writer.Offset()
+	writer.Seek(int64(schema.TitleOffset), io.SeekStart)
+	writer.WriteUInt32(uint32(offset))
+	writer.Seek(offset, io.SeekStart)
+	writer.WriteUInt32(uint32(len(value)))
+	writer.WriteString(value)
+}
+
+func (schema *DocumentSchema) forwardWriteTitle(writer *goschema.SchemaWriter, value string, context int) {
+	writer.WriteUInt32(uint32(len(value)))
+	writer.WriteString(value)
+
+}
+
+func (schema *DocumentSchema) ReadTitleInto(reader *goschema.SchemaReader, value *string, context int) error {
+	if schema.TitleOffset == -1 {
+		var tmp string
+		*value = tmp
+		return nil
+	}
+	offset := reader.Offset()
+	reader.Seek(int64(schema.TitleOffset), io.SeekStart)
+	fieldOffset := reader.ReadUInt32()
+	reader.Seek(int64(fieldOffset), io.SeekStart)
+	v1Length := reader.ReadStringLength()
+	*value = string(reader.ReadString(v1Length))
+
+	reader.Seek(offset, io.SeekStart)
+	return reader.Err()
+}
+
+func (view DocumentView) GetTitle(context int) (string, error) {
+	var value string
+	reader := view.reader
+	originalBase := reader.Base()
+	offset := reader.GlobalOffset()
+	reader.View(reader.Local(view.base))
+	err := view.schema.ReadTitleInto(reader, &value, context)
+	reader.View(reader.Local(originalBase))
+	reader.Seek(reader.Local(offset), io.SeekStart)
+	return value, err
+}
+
+func (schema *DocumentSchema) GetTitle(reader *goschema.SchemaReader, context int) (string, error) {
+	offset := reader.Offset()
+	view, err := schema.NakedView(reader)
+	reader.Seek(offset, io.SeekStart)
+	if err != nil {
+		var value string
+		return value, err
+	}
+	return view.GetTitle(context)
+}
+
+func (schema *DocumentSchema) WriteMain(writer *goschema.SchemaWriter, value *fixtures.Tag, context int) {
+	offset := writer.Offset()
+	writer.Seek(int64(schema.MainOffset), io.SeekStart)
+	writer.WriteUInt32(uint32(offset))
+	writer.Seek(offset, io.SeekStart)
+	writer.WriteUInt8(uint8(goschema.TypeCode(0)))
+	v2ViewBase := writer.Base()
+	v2Schema := WriteTagAutoGenSchema(writer)
+	if value != nil {
+		writer.WriteBool(true)
+		v2Schema.nakedWrite(writer, value, context)
+	} else {
+		writer.WriteBool(false)
+	}
+	writer.View(writer.Local(v2ViewBase))
+}
+
+func (schema *DocumentSchema) forwardWriteMain(writer *goschema.SchemaWriter, value *fixtures.Tag, context int) {
+	writer.WriteUInt8(uint8(goschema.TypeCode(0)))
+	v2ViewBase := writer.Base()
+	v2Schema := WriteTagAutoGenSchema(writer)
+	if value != nil {
+		writer.WriteBool(true)
+		v2Schema.nakedWrite(writer, value, context)
+	} else {
+		writer.WriteBool(false)
+	}
+	writer.View(writer.Local(v2ViewBase))
+
+}
+
+func (schema *DocumentSchema) ReadMainInto(reader *goschema.SchemaReader, value **fixtures.Tag, context int) error {
+	if schema.MainOffset == -1 {
+		var tmp *fixtures.Tag
+		*value = tmp
+		return nil
+	}
+	offset := reader.Offset()
+	reader.Seek(int64(schema.MainOffset), io.SeekStart)
+	fieldOffset := reader.ReadUInt32()
+	reader.Seek(int64(fieldOffset), io.SeekStart)
+	_ = reader.ReadUInt8() // ignore typecode
+	v3Schema := ReadTagAutoGenSchema(reader)
+	v3ViewBase := reader.Base()
+	v3NonNil := reader.ReadBool()
+	if v3NonNil {
+		var v3 fixtures.Tag
+		if err := v3Schema.NakedRead(reader, &v3, context); err != nil {
+			return err
+		}
+		*value = &v3
+	} else {
+		*value = nil
+	}
+	reader.View(reader.Local(v3ViewBase))
+
+	reader.Seek(offset, io.SeekStart)
+	return reader.Err()
+}
+
+func (view DocumentView) GetMain(context int) (*fixtures.Tag, error) {
+	var value *fixtures.Tag
+	reader := view.reader
+	originalBase := reader.Base()
+	offset := reader.GlobalOffset()
+	reader.View(reader.Local(view.base))
+	err := view.schema.ReadMainInto(reader, &value, context)
+	reader.View(reader.Local(originalBase))
+	reader.Seek(reader.Local(offset), io.SeekStart)
+	return value, err
+}
+
+func (schema *DocumentSchema) GetMain(reader *goschema.SchemaReader, context int) (*fixtures.Tag, error) {
+	offset := reader.Offset()
+	view, err := schema.NakedView(reader)
+	reader.Seek(offset, io.SeekStart)
+	if err != nil {
+		var value *fixtures.Tag
+		return value, err
+	}
+	return view.GetMain(context)
+}
+
+func (schema *DocumentSchema) WriteTags(writer *goschema.SchemaWriter, value []fixtures.Tag, context int) {
+	offset := writer.Offset()
+	writer.Seek(int64(schema.TagsOffset), io.SeekStart)
+	writer.WriteUInt32(uint32(offset))
+	writer.Seek(offset, io.SeekStart)
+	writer.WriteUInt8(uint8(goschema.TypeCode(0)))
+	v4ViewBase := writer.Base()
+	v4Schema := WriteTagAutoGenSchema(writer)
+	v4Length := len(value)
+	writer.WriteUInt32(uint32(v4Length))
+	for v4I := 0; v4I < v4Length; v4I++ {
+		v4Schema.nakedWrite(writer, &value[v4I], context)
+	}
+	writer.View(writer.Local(v4ViewBase))
+}
+
+func (schema *DocumentSchema) forwardWriteTags(writer *goschema.SchemaWriter, value []fixtures.Tag, context int) {
+	writer.WriteUInt8(uint8(goschema.TypeCode(0)))
+	v4ViewBase := writer.Base()
+	v4Schema := WriteTagAutoGenSchema(writer)
+	v4Length := len(value)
+	writer.WriteUInt32(uint32(v4Length))
+	for v4I := 0; v4I < v4Length; v4I++ {
+		v4Schema.nakedWrite(writer, &value[v4I], context)
+	}
+	writer.View(writer.Local(v4ViewBase))
+
+}
+
+func (schema *DocumentSchema) ReadTagsInto(reader *goschema.SchemaReader, value *[]fixtures.Tag, context int) error {
+	if schema.TagsOffset == -1 {
+		var tmp []fixtures.Tag
+		*value = tmp
+		return nil
+	}
+	offset := reader.Offset()
+	reader.Seek(int64(schema.TagsOffset), io.SeekStart)
+	fieldOffset := reader.ReadUInt32()
+	reader.Seek(int64(fieldOffset), io.SeekStart)
+	_ = reader.ReadUInt8() // ignore typecode
+	v5Schema := ReadTagAutoGenSchema(reader)
+	v5ViewBase := reader.Base()
+	v5Entries := reader.ReadCollectionLength(16)
+	v5Slice := make([]fixtures.Tag, v5Entries, v5Entries)
+	for v5I := 0; v5I < v5Entries; v5I++ {
+		if err := v5Schema.NakedRead(reader, &v5Slice[v5I], context); err != nil {
+			return err
+		}
+	}
+	*value = v5Slice
+	reader.View(reader.Local(v5ViewBase))
+
+	reader.Seek(offset, io.SeekStart)
+	return reader.Err()
+}
+
+func (view DocumentView) GetTags(context int) ([]fixtures.Tag, error) {
+	var value []fixtures.Tag
+	reader := view.reader
+	originalBase := reader.Base()
+	offset := reader.GlobalOffset()
+	reader.View(reader.Local(view.base))
+	err := view.schema.ReadTagsInto(reader, &value, context)
+	reader.View(reader.Local(originalBase))
+	reader.Seek(reader.Local(offset), io.SeekStart)
+	return value, err
+}
+
+func (schema *DocumentSchema) GetTags(reader *goschema.SchemaReader, context int) ([]fixtures.Tag, error) {
+	offset := reader.Offset()
+	view, err := schema.NakedView(reader)
+	reader.Seek(offset, io.SeekStart)
+	if err != nil {
+		var value []fixtures.Tag
+		return value, err
+	}
+	return view.GetTags(context)
+}
+
+func (schema *DocumentSchema) WriteByName(writer *goschema.SchemaWriter, value map[string]fixtures.Tag, context int) {
+	offset := writer.Offset()
+	writer.Seek(int64(schema.ByNameOffset), io.SeekStart)
+	writer.WriteUInt32(uint32(offset))
+	writer.Seek(offset, io.SeekStart)
+	writer.WriteUInt8(uint8(goschema.TypeCode(16)))
+	writer.WriteUInt8(uint8(goschema.TypeCode(0)))
+	v12Schema := WriteTagAutoGenSchema(writer)
+	v9ViewBase := writer.Base()
+	writer.WriteUInt32(uint32(len(value)))
+	for v9Key, v9Value := range value {
+		writer.WriteUInt32(uint32(len(v9Key)))
+		writer.WriteString(v9Key)
+
+		v12Schema.nakedWrite(writer, &v9Value, context)
+
+	}
+	writer.View(writer.Local(v9ViewBase))
+}
+
+func (schema *DocumentSchema) forwardWriteByName(writer *goschema.SchemaWriter, value map[string]fixtures.Tag, context int) {
+	writer.WriteUInt8(uint8(goschema.TypeCode(16)))
+	writer.WriteUInt8(uint8(goschema.TypeCode(0)))
+	v12Schema := WriteTagAutoGenSchema(writer)
+	v9ViewBase := writer.Base()
+	writer.WriteUInt32(uint32(len(value)))
+	for v9Key, v9Value := range value {
+		writer.WriteUInt32(uint32(len(v9Key)))
+		writer.WriteString(v9Key)
+
+		v12Schema.nakedWrite(writer, &v9Value, context)
+
+	}
+	writer.View(writer.Local(v9ViewBase))
+
+}
+
+func (schema *DocumentSchema) ReadByNameInto(reader *goschema.SchemaReader, value *map[string]fixtures.Tag, context int) error {
+	if schema.ByNameOffset == -1 {
+		var tmp map[string]fixtures.Tag
+		*value = tmp
+		return nil
+	}
+	offset := reader.Offset()
+	reader.Seek(int64(schema.ByNameOffset), io.SeekStart)
+	fieldOffset := reader.ReadUInt32()
+	reader.Seek(int64(fieldOffset), io.SeekStart)
+	_ = reader.ReadUInt8() // ignore typecode
+	_ = reader.ReadUInt8() // ignore typecode
+	v15Schema := ReadTagAutoGenSchema(reader)
+	v13ViewBase := reader.Base()
+	v13Entries := reader.ReadCollectionLength(32)
+	var v13Key string
+	var v13Value fixtures.Tag
+	v13Map := make(map[string]fixtures.Tag)
+	for v13I := 0; v13I < v13Entries; v13I++ {
+		v14Length := reader.ReadStringLength()
+		v13Key = string(reader.ReadString(v14Length))
+
+		if err := v15Schema.NakedRead(reader, &v13Value, context); err != nil {
+			return err
+		}
+
+		v13Map[v13Key] = v13Value
+	}
+	*value = v13Map
+	reader.View(reader.Local(v13ViewBase))
+
+	reader.Seek(offset, io.SeekStart)
+	return reader.Err()
+}
+
+func (view DocumentView) GetByName(context int) (map[string]fixtures.Tag, error) {
+	var value map[string]fixtures.Tag
+	reader := view.reader
+	originalBase := reader.Base()
+	offset := reader.GlobalOffset()
+	reader.View(reader.Local(view.base))
+	err := view.schema.ReadByNameInto(reader, &value, context)
+	reader.View(reader.Local(originalBase))
+	reader.Seek(reader.Local(offset), io.SeekStart)
+	return value, err
+}
+
+func (schema *DocumentSchema) GetByName(reader *goschema.SchemaReader, context int) (map[string]fixtures.Tag, error) {
+	offset := reader.Offset()
+	view, err := schema.NakedView(reader)
+	reader.Seek(offset, io.SeekStart)
+	if err != nil {
+		var value map[string]fixtures.Tag
+		return value, err
+	}
+	return view.GetByName(context)
+}
+
+func (schema *DocumentSchema) WriteSections(writer *goschema.SchemaWriter, value []fixtures.Section, context int) {
+	offset := writer.Offset()
+	writer.Seek(int64(schema.SectionsOffset), io.SeekStart)
+	writer.WriteUInt32(uint32(offset))
+	writer.Seek(offset, io.SeekStart)
+	writer.WriteUInt8(uint8(goschema.TypeCode(0)))
+	v19ViewBase := writer.Base()
+	v19Schema := WriteSectionAutoGenSchema(writer)
+	v19Length := len(value)
+	writer.WriteUInt32(uint32(v19Length))
+	for v19I := 0; v19I < v19Length; v19I++ {
+		v19Schema.nakedWrite(writer, &value[v19I], context)
+	}
+	writer.View(writer.Local(v19ViewBase))
+}
+
+func (schema *DocumentSchema) forwardWriteSections(writer *goschema.SchemaWriter, value []fixtures.Section, context int) {
+	writer.WriteUInt8(uint8(goschema.TypeCode(0)))
+	v19ViewBase := writer.Base()
+	v19Schema := WriteSectionAutoGenSchema(writer)
+	v19Length := len(value)
+	writer.WriteUInt32(uint32(v19Length))
+	for v19I := 0; v19I < v19Length; v19I++ {
+		v19Schema.nakedWrite(writer, &value[v19I], context)
+	}
+	writer.View(writer.Local(v19ViewBase))
+
+}
+
+func (schema *DocumentSchema) ReadSectionsInto(reader *goschema.SchemaReader, value *[]fixtures.Section, context int) error {
+	if schema.SectionsOffset == -1 {
+		var tmp []fixtures.Section
+		*value = tmp
+		return nil
+	}
+	offset := reader.Offset()
+	reader.Seek(int64(schema.SectionsOffset), io.SeekStart)
+	fieldOffset := reader.ReadUInt32()
+	reader.Seek(int64(fieldOffset), io.SeekStart)
+	_ = reader.ReadUInt8() // ignore typecode
+	v20Schema := ReadSectionAutoGenSchema(reader)
+	v20ViewBase := reader.Base()
+	v20Entries := reader.ReadCollectionLength(24)
+	v20Slice := make([]fixtures.Section, v20Entries, v20Entries)
+	for v20I := 0; v20I < v20Entries; v20I++ {
+		if err := v20Schema.NakedRead(reader, &v20Slice[v20I], context); err != nil {
+			return err
+		}
+	}
+	*value = v20Slice
+	reader.View(reader.Local(v20ViewBase))
+
+	reader.Seek(offset, io.SeekStart)
+	return reader.Err()
+}
+
+func (view DocumentView) GetSections(context int) ([]fixtures.Section, error) {
+	var value []fixtures.Section
+	reader := view.reader
+	originalBase := reader.Base()
+	offset := reader.GlobalOffset()
+	reader.View(reader.Local(view.base))
+	err := view.schema.ReadSectionsInto(reader, &value, context)
+	reader.View(reader.Local(originalBase))
+	reader.Seek(reader.Local(offset), io.SeekStart)
+	return value, err
+}
+
+func (schema *DocumentSchema) GetSections(reader *goschema.SchemaReader, context int) ([]fixtures.Section, error) {
+	offset := reader.Offset()
+	view, err := schema.NakedView(reader)
+	reader.Seek(offset, io.SeekStart)
+	if err != nil {
+		var value []fixtures.Section
+		return value, err
+	}
+	return view.GetSections(context)
+}
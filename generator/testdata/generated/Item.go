@@ -0,0 +1,433 @@
+package generated
+
+import (
+	"github.com/chasingcarrots/goschema"
+	"io"
+
+	"github.com/chasingcarrots/goschema/generator/testdata/fixtures"
+)
+
+const ItemSchemaID goschema.SchemaID = 2
+const ItemSchemaFingerprint uint64 = 0x9da7f2b064b3ef58
+const ItemSchemaName = "Item"
+
+type ItemSchema struct {
+	NameOffset   int
+	CountOffset  int
+	CountType    goschema.TypeCode
+	WeightOffset int
+	WeightType   goschema.TypeCode
+
+	descriptor []goschema.SchemaEntry
+}
+
+func NewItemSchema() *ItemSchema {
+	schema := ItemSchema{}
+	schema.init()
+	return &schema
+}
+
+func (schema *ItemSchema) ID() goschema.SchemaID {
+	return ItemSchemaID
+}
+
+func (schema *ItemSchema) Fingerprint() uint64 {
+	return ItemSchemaFingerprint
+}
+
+func (schema *ItemSchema) Name() string {
+	return ItemSchemaName
+}
+
+func (schema *ItemSchema) GoType() string {
+	return "github.com/chasingcarrots/goschema/generator/testdata/fixtures.Item"
+}
+
+func (schema *ItemSchema) Fill(entries []goschema.SchemaEntry) {
+	schema.NameOffset = -1
+	schema.CountOffset = -1
+	schema.WeightOffset = -1
+
+	for i := range entries {
+		switch entries[i].Name {
+		case "Name":
+			// the current name of a field takes precedence over its aliases
+			if schema.descriptor[0].CanRead(&entries[i]) {
+				schema.NameOffset = int(entries[i].Offset)
+			}
+		case "Count":
+			// the current name of a field takes precedence over its aliases
+			if schema.descriptor[1].CanRead(&entries[i]) {
+				schema.CountOffset = int(entries[i].Offset)
+				schema.CountType = entries[i].Type
+			}
+		case "Weight":
+			// the current name of a field takes precedence over its aliases
+			if schema.descriptor[2].CanRead(&entries[i]) {
+				schema.WeightOffset = int(entries[i].Offset)
+				schema.WeightType = entries[i].Type
+			}
+		}
+	}
+}
+
+func (schema *ItemSchema) init() {
+	if schema.descriptor == nil {
+		schema.descriptor = make([]goschema.SchemaEntry, 0, 3)
+		schema.descriptor = append(schema.descriptor,
+			goschema.SchemaEntry{
+				Name:       "Name",
+				Type:       goschema.TypeCode(16),
+				Offset:     0,
+				Descriptor: &goschema.TypeDescriptor{Code: goschema.TypeCode(16)},
+			},
+		)
+		schema.NameOffset = 0
+		schema.descriptor = append(schema.descriptor,
+			goschema.SchemaEntry{
+				Name:       "Count",
+				Type:       goschema.TypeCode(10),
+				Offset:     4,
+				Descriptor: &goschema.TypeDescriptor{Code: goschema.TypeCode(10)},
+			},
+		)
+		schema.CountOffset = 4
+		schema.CountType = goschema.TypeCode(10)
+		schema.descriptor = append(schema.descriptor,
+			goschema.SchemaEntry{
+				Name:       "Weight",
+				Type:       goschema.TypeCode(14),
+				Offset:     8,
+				Descriptor: &goschema.TypeDescriptor{Code: goschema.TypeCode(14)},
+			},
+		)
+		schema.WeightOffset = 8
+		schema.WeightType = goschema.TypeCode(14)
+	}
+}
+
+// DescribeDeprecated returns the entries of the fields that are read, but no
+// longer written.
+func (schema *ItemSchema) DescribeDeprecated() []goschema.SchemaEntry {
+	return nil
+}
+
+func (schema *ItemSchema) Describe() []goschema.SchemaEntry {
+	return schema.descriptor
+}
+
+func ReadItemSchema(reader *goschema.SchemaReader) *ItemSchema {
+	schemaIdx := int(reader.ReadUInt32())
+	existingSchema, schemaEntries := reader.FindSchema(int(schemaIdx))
+	schema, ok := existingSchema.(*ItemSchema)
+	if existingSchema == nil || !ok {
+		schema = NewItemSchema()
+		reader.VerifySchemaName(schemaIdx, ItemSchemaName)
+		// the offsets of a new schema already match data with the same fingerprint
+		if fingerprint, ok := reader.SchemaFingerprint(schemaIdx); !ok || fingerprint != ItemSchemaFingerprint {
+			schema.Fill(schemaEntries)
+		}
+		reader.RegisterSchema(schemaIdx, schema)
+	}
+	return schema
+}
+
+func WriteItemSchema(writer *goschema.SchemaWriter) *ItemSchema {
+	schemaEntry, ok := writer.FindSchema(goschema.SchemaID(ItemSchemaID))
+	schemaIdx := schemaEntry.Index()
+	schema, ok := schemaEntry.Schema().(*ItemSchema)
+	if !ok {
+		schema = NewItemSchema()
+		schemaIdx = writer.RegisterSchema(schema)
+	}
+	writer.WriteUInt32(uint32(schemaIdx))
+	return schema
+}
+
+func (schema *ItemSchema) SingleRead(reader *goschema.SchemaReader, value *fixtures.Item, context int) error {
+	originalBase := reader.Base()
+	err := schema.NakedRead(reader, value, context)
+	reader.View(reader.Local(originalBase))
+	return err
+}
+
+func (schema *ItemSchema) NakedRead(reader *goschema.SchemaReader, value *fixtures.Item, context int) error {
+	nextOffset, err := reader.BeginObject("Item")
+	if err != nil {
+		return err
+	}
+	defer reader.EndObject()
+	if err := schema.ReadNameInto(reader, &value.Name, context); err != nil {
+		return err
+	}
+	if err := schema.ReadCountInto(reader, &value.Count, context); err != nil {
+		return err
+	}
+	if err := schema.ReadWeightInto(reader, &value.Weight, context); err != nil {
+		return err
+	}
+	reader.Seek(reader.Local(nextOffset), io.SeekStart)
+	return reader.Err()
+}
+
+// ItemView gives access to single fields of an object without
+// reading the whole object.
+type ItemView struct {
+	schema *ItemSchema
+	reader *goschema.SchemaReader
+	base   int64 // global offset of the object's data
+}
+
+// NakedView creates a view of the object at the current position of the reader
+// and moves the reader past the object, like NakedRead would. The fields of the
+// object are only read when they are requested from the view. If the object
+// carries a checksum, NakedView verifies it and thus reads the whole object.
+func (schema *ItemSchema) NakedView(reader *goschema.SchemaReader) (ItemView, error) {
+	originalBase := reader.Base()
+	nextOffset, err := reader.BeginObject("Item")
+	if err != nil {
+		reader.View(reader.Local(originalBase))
+		return ItemView{}, err
+	}
+	reader.EndObject()
+	view := ItemView{
+		schema: schema,
+		reader: reader,
+		base:   reader.Base(),
+	}
+	reader.Seek(reader.Local(nextOffset), io.SeekStart)
+	reader.View(reader.Local(originalBase))
+	return view, nil
+}
+
+func (schema *ItemSchema) SingleWrite(writer *goschema.SchemaWriter, value *fixtures.Item, context int) {
+	originalBase := writer.Base()
+	schema.NakedWrite(writer, value, context)
+	writer.View(writer.Local(originalBase))
+}
+
+func (schema *ItemSchema) NakedWrite(writer *goschema.SchemaWriter, value *fixtures.Item, context int) {
+	schema.nakedWrite(writer, value, context)
+}
+
+// SingleWritePrepared is like SingleWrite, but does not run the
+// BeforeSchemaWrite hooks. It writes a value that RunBeforeWriteHooks has
+// prepared, e.g. after checking its EncodedSize.
+func (schema *ItemSchema) SingleWritePrepared(writer *goschema.SchemaWriter, value *fixtures.Item, context int) {
+	originalBase := writer.Base()
+	schema.nakedWrite(writer, value, context)
+	writer.View(writer.Local(originalBase))
+}
+
+// RunBeforeWriteHooks runs the BeforeSchemaWrite hooks of the value and of all
+// objects that it contains, like SingleWrite does before writing anything.
+func (schema *ItemSchema) RunBeforeWriteHooks(value *fixtures.Item, context int) {
+}
+
+// nakedWrite writes an object whose hooks have run already. It is called for
+// nested objects.
+func (schema *ItemSchema) nakedWrite(writer *goschema.SchemaWriter, value *fixtures.Item, context int) {
+	if writer.Err() != nil {
+		return
+	}
+	if writer.IsForward() {
+		schema.forwardNakedWrite(writer, value, context)
+		return
+	}
+	startOffset := writer.BeginObject()
+	writer.Seek(16, io.SeekCurrent)
+	schema.WriteName(writer, value.Name, context)
+	schema.WriteCount(writer, value.Count, context)
+	schema.WriteWeight(writer, value.Weight, context)
+	writer.EndObject(startOffset)
+}
+
+// forwardNakedWrite produces the same data as NakedWrite without seeking by
+// computing all references up front.
+func (schema *ItemSchema) forwardNakedWrite(writer *goschema.SchemaWriter, value *fixtures.Item, context int) {
+	overhead := writer.ObjectOverhead()
+	startOffset := writer.BeginSizedObject(sizeItem(value, overhead) - overhead)
+	reference := 16
+	writer.WriteUInt32(uint32(reference))
+	reference += 4 + len(value.Name)
+
+	schema.forwardWriteCount(writer, value.Count, context)
+	schema.forwardWriteWeight(writer, value.Weight, context)
+	schema.forwardWriteName(writer, value.Name, context)
+	writer.EndObject(startOffset)
+}
+
+// EncodedSize returns the number of bytes that SingleWritePrepared writes for
+// the given value, assuming that checksums are disabled. It does not include
+// the index of the schema written by WriteItemSchema, and it does
+// not run any hooks.
+func (schema *ItemSchema) EncodedSize(value *fixtures.Item) int {
+	return sizeItem(value, 4)
+}
+
+// sizeItem returns the number of bytes written by NakedWrite for
+// the given value if each object takes up overhead bytes besides its data.
+func sizeItem(value *fixtures.Item, overhead int) int {
+	size := overhead + 16
+	size += 4 + len(value.Name)
+
+	return size
+}
+
+func (schema *ItemSchema) WriteName(writer *goschema.SchemaWriter, value string, context int) {
+	offset := writer.Offset()
+	writer.Seek(int64(schema.NameOffset), io.SeekStart)
+	writer.WriteUInt32(uint32(offset))
+	writer.Seek(offset, io.SeekStart)
+	writer.WriteUInt32(uint32(len(value)))
+	writer.WriteString(value)
+}
+
+func (schema *ItemSchema) forwardWriteName(writer *goschema.SchemaWriter, value string, context int) {
+	writer.WriteUInt32(uint32(len(value)))
+	writer.WriteString(value)
+
+}
+
+func (schema *ItemSchema) ReadNameInto(reader *goschema.SchemaReader, value *string, context int) error {
+	if schema.NameOffset == -1 {
+		var tmp string
+		*value = tmp
+		return nil
+	}
+	offset := reader.Offset()
+	reader.Seek(int64(schema.NameOffset), io.SeekStart)
+	fieldOffset := reader.ReadUInt32()
+	reader.Seek(int64(fieldOffset), io.SeekStart)
+	v1Length := reader.ReadStringLength()
+	*value = string(reader.ReadString(v1Length))
+
+	reader.Seek(offset, io.SeekStart)
+	return reader.Err()
+}
+
+func (view ItemView) GetName(context int) (string, error) {
+	var value string
+	reader := view.reader
+	originalBase := reader.Base()
+	offset := reader.GlobalOffset()
+	reader.View(reader.Local(view.base))
+	err := view.schema.ReadNameInto(reader, &value, context)
+	reader.View(reader.Local(originalBase))
+	reader.Seek(reader.Local(offset), io.SeekStart)
+	return value, err
+}
+
+func (schema *ItemSchema) GetName(reader *goschema.SchemaReader, context int) (string, error) {
+	offset := reader.Offset()
+	view, err := schema.NakedView(reader)
+	reader.Seek(offset, io.SeekStart)
+	if err != nil {
+		var value string
+		return value, err
+	}
+	return view.GetName(context)
+}
+
+func (schema *ItemSchema) WriteCount(writer *goschema.SchemaWriter, value int32, context int) {
+	offset := writer.Offset()
+	writer.Seek(int64(schema.CountOffset), io.SeekStart)
+	writer.WriteInt32(int32(value))
+	writer.Seek(offset, io.SeekStart)
+}
+
+func (schema *ItemSchema) forwardWriteCount(writer *goschema.SchemaWriter, value int32, context int) {
+	writer.WriteInt32(int32(value))
+}
+
+func (schema *ItemSchema) ReadCountInto(reader *goschema.SchemaReader, value *int32, context int) error {
+	if schema.CountOffset == -1 {
+		var tmp int32
+		*value = tmp
+		return nil
+	}
+	offset := reader.Offset()
+	reader.Seek(int64(schema.CountOffset), io.SeekStart)
+	if schema.CountType != goschema.TypeCode(10) {
+		*value = int32(reader.ReadWidenedInt(schema.CountType))
+		reader.Seek(offset, io.SeekStart)
+		return reader.Err()
+	}
+	*value = int32(reader.ReadInt32())
+	reader.Seek(offset, io.SeekStart)
+	return reader.Err()
+}
+
+func (view ItemView) GetCount(context int) (int32, error) {
+	var value int32
+	reader := view.reader
+	originalBase := reader.Base()
+	offset := reader.GlobalOffset()
+	reader.View(reader.Local(view.base))
+	err := view.schema.ReadCountInto(reader, &value, context)
+	reader.View(reader.Local(originalBase))
+	reader.Seek(reader.Local(offset), io.SeekStart)
+	return value, err
+}
+
+func (schema *ItemSchema) GetCount(reader *goschema.SchemaReader, context int) (int32, error) {
+	offset := reader.Offset()
+	view, err := schema.NakedView(reader)
+	reader.Seek(offset, io.SeekStart)
+	if err != nil {
+		var value int32
+		return value, err
+	}
+	return view.GetCount(context)
+}
+
+func (schema *ItemSchema) WriteWeight(writer *goschema.SchemaWriter, value float64, context int) {
+	offset := writer.Offset()
+	writer.Seek(int64(schema.WeightOffset), io.SeekStart)
+	writer.WriteFloat64(float64(value))
+	writer.Seek(offset, io.SeekStart)
+}
+
+func (schema *ItemSchema) forwardWriteWeight(writer *goschema.SchemaWriter, value float64, context int) {
+	writer.WriteFloat64(float64(value))
+}
+
+func (schema *ItemSchema) ReadWeightInto(reader *goschema.SchemaReader, value *float64, context int) error {
+	if schema.WeightOffset == -1 {
+		*value = float64(1.5)
+		return nil
+	}
+	offset := reader.Offset()
+	reader.Seek(int64(schema.WeightOffset), io.SeekStart)
+	if schema.WeightType != goschema.TypeCode(14) {
+		*value = float64(reader.ReadWidenedFloat(schema.WeightType))
+		reader.Seek(offset, io.SeekStart)
+		return reader.Err()
+	}
+	*value = float64(reader.ReadFloat64())
+	reader.Seek(offset, io.SeekStart)
+	return reader.Err()
+}
+
+func (view ItemView) GetWeight(context int) (float64, error) {
+	var value float64
+	reader := view.reader
+	originalBase := reader.Base()
+	offset := reader.GlobalOffset()
+	reader.View(reader.Local(view.base))
+	err := view.schema.ReadWeightInto(reader, &value, context)
+	reader.View(reader.Local(originalBase))
+	reader.Seek(reader.Local(offset), io.SeekStart)
+	return value, err
+}
+
+func (schema *ItemSchema) GetWeight(reader *goschema.SchemaReader, context int) (float64, error) {
+	offset := reader.Offset()
+	view, err := schema.NakedView(reader)
+	reader.Seek(offset, io.SeekStart)
+	if err != nil {
+		var value float64
+		return value, err
+	}
+	return view.GetWeight(context)
+}
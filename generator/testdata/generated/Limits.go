@@ -0,0 +1,782 @@
+package generated
+
+import (
+	"github.com/chasingcarrots/goschema"
+	"io"
+
+	"github.com/chasingcarrots/goschema/generator/testdata/fixtures"
+)
+
+const LimitsSchemaID goschema.SchemaID = 6
+const LimitsSchemaFingerprint uint64 = 0xad7dce84cf25553a
+const LimitsSchemaName = "Limits"
+
+type LimitsSchema struct {
+	SmallOffset   int
+	FullOffset    int
+	CountOffset   int
+	CountType     goschema.TypeCode
+	OffsetOffset  int
+	OffsetType    goschema.TypeCode
+	RatioOffset   int
+	RatioType     goschema.TypeCode
+	WeightOffset  int
+	WeightType    goschema.TypeCode
+	ProfileOffset int
+
+	descriptor []goschema.SchemaEntry
+}
+
+func NewLimitsSchema() *LimitsSchema {
+	schema := LimitsSchema{}
+	schema.init()
+	return &schema
+}
+
+func (schema *LimitsSchema) ID() goschema.SchemaID {
+	return LimitsSchemaID
+}
+
+func (schema *LimitsSchema) Fingerprint() uint64 {
+	return LimitsSchemaFingerprint
+}
+
+func (schema *LimitsSchema) Name() string {
+	return LimitsSchemaName
+}
+
+func (schema *LimitsSchema) GoType() string {
+	return "github.com/chasingcarrots/goschema/generator/testdata/fixtures.Limits"
+}
+
+func (schema *LimitsSchema) Fill(entries []goschema.SchemaEntry) {
+	schema.SmallOffset = -1
+	schema.FullOffset = -1
+	schema.CountOffset = -1
+	schema.OffsetOffset = -1
+	schema.RatioOffset = -1
+	schema.WeightOffset = -1
+	schema.ProfileOffset = -1
+
+	for i := range entries {
+		switch entries[i].Name {
+		case "Small":
+			// the current name of a field takes precedence over its aliases
+			if schema.descriptor[0].CanRead(&entries[i]) {
+				schema.SmallOffset = int(entries[i].Offset)
+			}
+		case "Full":
+			// the current name of a field takes precedence over its aliases
+			if schema.descriptor[1].CanRead(&entries[i]) {
+				schema.FullOffset = int(entries[i].Offset)
+			}
+		case "Count":
+			// the current name of a field takes precedence over its aliases
+			if schema.descriptor[2].CanRead(&entries[i]) {
+				schema.CountOffset = int(entries[i].Offset)
+				schema.CountType = entries[i].Type
+			}
+		case "Offset":
+			// the current name of a field takes precedence over its aliases
+			if schema.descriptor[3].CanRead(&entries[i]) {
+				schema.OffsetOffset = int(entries[i].Offset)
+				schema.OffsetType = entries[i].Type
+			}
+		case "Ratio":
+			// the current name of a field takes precedence over its aliases
+			if schema.descriptor[4].CanRead(&entries[i]) {
+				schema.RatioOffset = int(entries[i].Offset)
+				schema.RatioType = entries[i].Type
+			}
+		case "Weight":
+			// the current name of a field takes precedence over its aliases
+			if schema.descriptor[5].CanRead(&entries[i]) {
+				schema.WeightOffset = int(entries[i].Offset)
+				schema.WeightType = entries[i].Type
+			}
+		case "Profile":
+			// the current name of a field takes precedence over its aliases
+			if schema.descriptor[6].CanRead(&entries[i]) {
+				schema.ProfileOffset = int(entries[i].Offset)
+			}
+		}
+	}
+}
+
+func (schema *LimitsSchema) init() {
+	if schema.descriptor == nil {
+		schema.descriptor = make([]goschema.SchemaEntry, 0, 7)
+		schema.descriptor = append(schema.descriptor,
+			goschema.SchemaEntry{
+				Name:       "Small",
+				Type:       goschema.TypeCode(8),
+				Offset:     0,
+				Descriptor: &goschema.TypeDescriptor{Code: goschema.TypeCode(8)},
+			},
+		)
+		schema.SmallOffset = 0
+		schema.descriptor = append(schema.descriptor,
+			goschema.SchemaEntry{
+				Name:       "Full",
+				Type:       goschema.TypeCode(8),
+				Offset:     1,
+				Descriptor: &goschema.TypeDescriptor{Code: goschema.TypeCode(8)},
+			},
+		)
+		schema.FullOffset = 1
+		schema.descriptor = append(schema.descriptor,
+			goschema.SchemaEntry{
+				Name:       "Count",
+				Type:       goschema.TypeCode(6),
+				Offset:     2,
+				Descriptor: &goschema.TypeDescriptor{Code: goschema.TypeCode(6)},
+			},
+		)
+		schema.CountOffset = 2
+		schema.CountType = goschema.TypeCode(6)
+		schema.descriptor = append(schema.descriptor,
+			goschema.SchemaEntry{
+				Name:       "Offset",
+				Type:       goschema.TypeCode(12),
+				Offset:     10,
+				Descriptor: &goschema.TypeDescriptor{Code: goschema.TypeCode(12)},
+			},
+		)
+		schema.OffsetOffset = 10
+		schema.OffsetType = goschema.TypeCode(12)
+		schema.descriptor = append(schema.descriptor,
+			goschema.SchemaEntry{
+				Name:       "Ratio",
+				Type:       goschema.TypeCode(13),
+				Offset:     18,
+				Descriptor: &goschema.TypeDescriptor{Code: goschema.TypeCode(13)},
+			},
+		)
+		schema.RatioOffset = 18
+		schema.RatioType = goschema.TypeCode(13)
+		schema.descriptor = append(schema.descriptor,
+			goschema.SchemaEntry{
+				Name:       "Weight",
+				Type:       goschema.TypeCode(14),
+				Offset:     22,
+				Descriptor: &goschema.TypeDescriptor{Code: goschema.TypeCode(14)},
+			},
+		)
+		schema.WeightOffset = 22
+		schema.WeightType = goschema.TypeCode(14)
+		schema.descriptor = append(schema.descriptor,
+			goschema.SchemaEntry{
+				Name:       "Profile",
+				Type:       goschema.TypeCode(0),
+				Offset:     30,
+				Descriptor: &goschema.TypeDescriptor{Code: goschema.TypeCode(0), Schema: "ProfileAutoGen"},
+			},
+		)
+		schema.ProfileOffset = 30
+	}
+}
+
+// DescribeDeprecated returns the entries of the fields that are read, but no
+// longer written.
+func (schema *LimitsSchema) DescribeDeprecated() []goschema.SchemaEntry {
+	return nil
+}
+
+func (schema *LimitsSchema) Describe() []goschema.SchemaEntry {
+	return schema.descriptor
+}
+
+func ReadLimitsSchema(reader *goschema.SchemaReader) *LimitsSchema {
+	schemaIdx := int(reader.ReadUInt32())
+	existingSchema, schemaEntries := reader.FindSchema(int(schemaIdx))
+	schema, ok := existingSchema.(*LimitsSchema)
+	if existingSchema == nil || !ok {
+		schema = NewLimitsSchema()
+		reader.VerifySchemaName(schemaIdx, LimitsSchemaName)
+		// the offsets of a new schema already match data with the same fingerprint
+		if fingerprint, ok := reader.SchemaFingerprint(schemaIdx); !ok || fingerprint != LimitsSchemaFingerprint {
+			schema.Fill(schemaEntries)
+		}
+		reader.RegisterSchema(schemaIdx, schema)
+	}
+	return schema
+}
+
+func WriteLimitsSchema(writer *goschema.SchemaWriter) *LimitsSchema {
+	schemaEntry, ok := writer.FindSchema(goschema.SchemaID(LimitsSchemaID))
+	schemaIdx := schemaEntry.Index()
+	schema, ok := schemaEntry.Schema().(*LimitsSchema)
+	if !ok {
+		schema = NewLimitsSchema()
+		schemaIdx = writer.RegisterSchema(schema)
+	}
+	writer.WriteUInt32(uint32(schemaIdx))
+	return schema
+}
+
+func (schema *LimitsSchema) SingleRead(reader *goschema.SchemaReader, value *fixtures.Limits, context int) error {
+	originalBase := reader.Base()
+	err := schema.NakedRead(reader, value, context)
+	reader.View(reader.Local(originalBase))
+	return err
+}
+
+func (schema *LimitsSchema) NakedRead(reader *goschema.SchemaReader, value *fixtures.Limits, context int) error {
+	nextOffset, err := reader.BeginObject("Limits")
+	if err != nil {
+		return err
+	}
+	defer reader.EndObject()
+	if err := schema.ReadSmallInto(reader, &value.Small, context); err != nil {
+		return err
+	}
+	if err := schema.ReadFullInto(reader, &value.Full, context); err != nil {
+		return err
+	}
+	if err := schema.ReadCountInto(reader, &value.Count, context); err != nil {
+		return err
+	}
+	if err := schema.ReadOffsetInto(reader, &value.Offset, context); err != nil {
+		return err
+	}
+	if err := schema.ReadRatioInto(reader, &value.Ratio, context); err != nil {
+		return err
+	}
+	if err := schema.ReadWeightInto(reader, &value.Weight, context); err != nil {
+		return err
+	}
+	if err := schema.ReadProfileInto(reader, &value.Profile, context); err != nil {
+		return goschema.PrefixFieldPath(err, "Profile")
+	}
+	reader.Seek(reader.Local(nextOffset), io.SeekStart)
+	if err := reader.Err(); err != nil {
+		return err
+	}
+	if err := schema.validate(value, false); err != nil {
+		return err
+	}
+	return nil
+}
+
+// validate checks the constraints declared by the tags of the fields. Deprecated
+// fields are not checked when writing, since they are not written.
+func (schema *LimitsSchema) validate(value *fixtures.Limits, writing bool) error {
+	if value.Small < -100 {
+		return &goschema.ValidationError{Schema: LimitsSchemaName, Field: "Small", Constraint: "schemaMin:\"-100\""}
+	}
+	if value.Small > 100 {
+		return &goschema.ValidationError{Schema: LimitsSchemaName, Field: "Small", Constraint: "schemaMax:\"100\""}
+	}
+	if value.Full < -128 {
+		return &goschema.ValidationError{Schema: LimitsSchemaName, Field: "Full", Constraint: "schemaMin:\"-128\""}
+	}
+	if value.Full > 127 {
+		return &goschema.ValidationError{Schema: LimitsSchemaName, Field: "Full", Constraint: "schemaMax:\"127\""}
+	}
+	if value.Count < 10 {
+		return &goschema.ValidationError{Schema: LimitsSchemaName, Field: "Count", Constraint: "schemaMin:\"10\""}
+	}
+	if value.Offset > -1 {
+		return &goschema.ValidationError{Schema: LimitsSchemaName, Field: "Offset", Constraint: "schemaMax:\"-1\""}
+	}
+	if value.Ratio < 0 || value.Ratio != value.Ratio {
+		return &goschema.ValidationError{Schema: LimitsSchemaName, Field: "Ratio", Constraint: "schemaMin:\"0\""}
+	}
+	if value.Ratio > 1 || value.Ratio != value.Ratio {
+		return &goschema.ValidationError{Schema: LimitsSchemaName, Field: "Ratio", Constraint: "schemaMax:\"1\""}
+	}
+	if value.Weight < 0.5 || value.Weight != value.Weight {
+		return &goschema.ValidationError{Schema: LimitsSchemaName, Field: "Weight", Constraint: "schemaMin:\"0.5\""}
+	}
+	return nil
+}
+
+// LimitsView gives access to single fields of an object without
+// reading the whole object.
+type LimitsView struct {
+	schema *LimitsSchema
+	reader *goschema.SchemaReader
+	base   int64 // global offset of the object's data
+}
+
+// NakedView creates a view of the object at the current position of the reader
+// and moves the reader past the object, like NakedRead would. The fields of the
+// object are only read when they are requested from the view. If the object
+// carries a checksum, NakedView verifies it and thus reads the whole object.
+func (schema *LimitsSchema) NakedView(reader *goschema.SchemaReader) (LimitsView, error) {
+	originalBase := reader.Base()
+	nextOffset, err := reader.BeginObject("Limits")
+	if err != nil {
+		reader.View(reader.Local(originalBase))
+		return LimitsView{}, err
+	}
+	reader.EndObject()
+	view := LimitsView{
+		schema: schema,
+		reader: reader,
+		base:   reader.Base(),
+	}
+	reader.Seek(reader.Local(nextOffset), io.SeekStart)
+	reader.View(reader.Local(originalBase))
+	return view, nil
+}
+
+func (schema *LimitsSchema) SingleWrite(writer *goschema.SchemaWriter, value *fixtures.Limits, context int) {
+	originalBase := writer.Base()
+	schema.NakedWrite(writer, value, context)
+	writer.View(writer.Local(originalBase))
+}
+
+func (schema *LimitsSchema) NakedWrite(writer *goschema.SchemaWriter, value *fixtures.Limits, context int) {
+	schema.nakedWrite(writer, value, context)
+}
+
+// SingleWritePrepared is like SingleWrite, but does not run the
+// BeforeSchemaWrite hooks. It writes a value that RunBeforeWriteHooks has
+// prepared, e.g. after checking its EncodedSize.
+func (schema *LimitsSchema) SingleWritePrepared(writer *goschema.SchemaWriter, value *fixtures.Limits, context int) {
+	originalBase := writer.Base()
+	schema.nakedWrite(writer, value, context)
+	writer.View(writer.Local(originalBase))
+}
+
+// RunBeforeWriteHooks runs the BeforeSchemaWrite hooks of the value and of all
+// objects that it contains, like SingleWrite does before writing anything.
+func (schema *LimitsSchema) RunBeforeWriteHooks(value *fixtures.Limits, context int) {
+}
+
+// nakedWrite writes an object whose hooks have run already. It is called for
+// nested objects.
+func (schema *LimitsSchema) nakedWrite(writer *goschema.SchemaWriter, value *fixtures.Limits, context int) {
+	if writer.Err() != nil {
+		return
+	}
+	if err := schema.validate(value, true); err != nil {
+		writer.Fail(err)
+		return
+	}
+	if writer.IsForward() {
+		schema.forwardNakedWrite(writer, value, context)
+		return
+	}
+	startOffset := writer.BeginObject()
+	writer.Seek(34, io.SeekCurrent)
+	schema.WriteSmall(writer, value.Small, context)
+	schema.WriteFull(writer, value.Full, context)
+	schema.WriteCount(writer, value.Count, context)
+	schema.WriteOffset(writer, value.Offset, context)
+	schema.WriteRatio(writer, value.Ratio, context)
+	schema.WriteWeight(writer, value.Weight, context)
+	schema.WriteProfile(writer, &value.Profile, context)
+	if writer.FailedIn("Profile") {
+		return
+	}
+	writer.EndObject(startOffset)
+}
+
+// forwardNakedWrite produces the same data as NakedWrite without seeking by
+// computing all references up front.
+func (schema *LimitsSchema) forwardNakedWrite(writer *goschema.SchemaWriter, value *fixtures.Limits, context int) {
+	overhead := writer.ObjectOverhead()
+	startOffset := writer.BeginSizedObject(sizeLimits(value, overhead) - overhead)
+	reference := 34
+	schema.forwardWriteSmall(writer, value.Small, context)
+	schema.forwardWriteFull(writer, value.Full, context)
+	schema.forwardWriteCount(writer, value.Count, context)
+	schema.forwardWriteOffset(writer, value.Offset, context)
+	schema.forwardWriteRatio(writer, value.Ratio, context)
+	schema.forwardWriteWeight(writer, value.Weight, context)
+	writer.WriteUInt32(uint32(reference))
+	reference += 4
+	reference += sizeProfileAutoGen(&value.Profile, overhead)
+
+	schema.forwardWriteProfile(writer, &value.Profile, context)
+	if writer.FailedIn("Profile") {
+		return
+	}
+	writer.EndObject(startOffset)
+}
+
+// EncodedSize returns the number of bytes that SingleWritePrepared writes for
+// the given value, assuming that checksums are disabled. It does not include
+// the index of the schema written by WriteLimitsSchema, and it does
+// not run any hooks.
+func (schema *LimitsSchema) EncodedSize(value *fixtures.Limits) int {
+	return sizeLimits(value, 4)
+}
+
+// sizeLimits returns the number of bytes written by NakedWrite for
+// the given value if each object takes up overhead bytes besides its data.
+func sizeLimits(value *fixtures.Limits, overhead int) int {
+	size := overhead + 34
+	size += 4
+	size += sizeProfileAutoGen(&value.Profile, overhead)
+
+	return size
+}
+
+func (schema *LimitsSchema) WriteSmall(writer *goschema.SchemaWriter, value int8, context int) {
+	offset := writer.Offset()
+	writer.Seek(int64(schema.SmallOffset), io.SeekStart)
+	writer.WriteInt8(int8(value))
+	writer.Seek(offset, io.SeekStart)
+}
+
+func (schema *LimitsSchema) forwardWriteSmall(writer *goschema.SchemaWriter, value int8, context int) {
+	writer.WriteInt8(int8(value))
+}
+
+func (schema *LimitsSchema) ReadSmallInto(reader *goschema.SchemaReader, value *int8, context int) error {
+	if schema.SmallOffset == -1 {
+		var tmp int8
+		*value = tmp
+		return nil
+	}
+	offset := reader.Offset()
+	reader.Seek(int64(schema.SmallOffset), io.SeekStart)
+	*value = int8(reader.ReadInt8())
+	reader.Seek(offset, io.SeekStart)
+	return reader.Err()
+}
+
+func (view LimitsView) GetSmall(context int) (int8, error) {
+	var value int8
+	reader := view.reader
+	originalBase := reader.Base()
+	offset := reader.GlobalOffset()
+	reader.View(reader.Local(view.base))
+	err := view.schema.ReadSmallInto(reader, &value, context)
+	reader.View(reader.Local(originalBase))
+	reader.Seek(reader.Local(offset), io.SeekStart)
+	return value, err
+}
+
+func (schema *LimitsSchema) GetSmall(reader *goschema.SchemaReader, context int) (int8, error) {
+	offset := reader.Offset()
+	view, err := schema.NakedView(reader)
+	reader.Seek(offset, io.SeekStart)
+	if err != nil {
+		var value int8
+		return value, err
+	}
+	return view.GetSmall(context)
+}
+
+func (schema *LimitsSchema) WriteFull(writer *goschema.SchemaWriter, value int8, context int) {
+	offset := writer.Offset()
+	writer.Seek(int64(schema.FullOffset), io.SeekStart)
+	writer.WriteInt8(int8(value))
+	writer.Seek(offset, io.SeekStart)
+}
+
+func (schema *LimitsSchema) forwardWriteFull(writer *goschema.SchemaWriter, value int8, context int) {
+	writer.WriteInt8(int8(value))
+}
+
+func (schema *LimitsSchema) ReadFullInto(reader *goschema.SchemaReader, value *int8, context int) error {
+	if schema.FullOffset == -1 {
+		var tmp int8
+		*value = tmp
+		return nil
+	}
+	offset := reader.Offset()
+	reader.Seek(int64(schema.FullOffset), io.SeekStart)
+	*value = int8(reader.ReadInt8())
+	reader.Seek(offset, io.SeekStart)
+	return reader.Err()
+}
+
+func (view LimitsView) GetFull(context int) (int8, error) {
+	var value int8
+	reader := view.reader
+	originalBase := reader.Base()
+	offset := reader.GlobalOffset()
+	reader.View(reader.Local(view.base))
+	err := view.schema.ReadFullInto(reader, &value, context)
+	reader.View(reader.Local(originalBase))
+	reader.Seek(reader.Local(offset), io.SeekStart)
+	return value, err
+}
+
+func (schema *LimitsSchema) GetFull(reader *goschema.SchemaReader, context int) (int8, error) {
+	offset := reader.Offset()
+	view, err := schema.NakedView(reader)
+	reader.Seek(offset, io.SeekStart)
+	if err != nil {
+		var value int8
+		return value, err
+	}
+	return view.GetFull(context)
+}
+
+func (schema *LimitsSchema) WriteCount(writer *goschema.SchemaWriter, value uint64, context int) {
+	offset := writer.Offset()
+	writer.Seek(int64(schema.CountOffset), io.SeekStart)
+	writer.WriteUInt64(uint64(value))
+	writer.Seek(offset, io.SeekStart)
+}
+
+func (schema *LimitsSchema) forwardWriteCount(writer *goschema.SchemaWriter, value uint64, context int) {
+	writer.WriteUInt64(uint64(value))
+}
+
+func (schema *LimitsSchema) ReadCountInto(reader *goschema.SchemaReader, value *uint64, context int) error {
+	if schema.CountOffset == -1 {
+		var tmp uint64
+		*value = tmp
+		return nil
+	}
+	offset := reader.Offset()
+	reader.Seek(int64(schema.CountOffset), io.SeekStart)
+	if schema.CountType != goschema.TypeCode(6) {
+		*value = uint64(reader.ReadWidenedUInt(schema.CountType))
+		reader.Seek(offset, io.SeekStart)
+		return reader.Err()
+	}
+	*value = uint64(reader.ReadUInt64())
+	reader.Seek(offset, io.SeekStart)
+	return reader.Err()
+}
+
+func (view LimitsView) GetCount(context int) (uint64, error) {
+	var value uint64
+	reader := view.reader
+	originalBase := reader.Base()
+	offset := reader.GlobalOffset()
+	reader.View(reader.Local(view.base))
+	err := view.schema.ReadCountInto(reader, &value, context)
+	reader.View(reader.Local(originalBase))
+	reader.Seek(reader.Local(offset), io.SeekStart)
+	return value, err
+}
+
+func (schema *LimitsSchema) GetCount(reader *goschema.SchemaReader, context int) (uint64, error) {
+	offset := reader.Offset()
+	view, err := schema.NakedView(reader)
+	reader.Seek(offset, io.SeekStart)
+	if err != nil {
+		var value uint64
+		return value, err
+	}
+	return view.GetCount(context)
+}
+
+func (schema *LimitsSchema) WriteOffset(writer *goschema.SchemaWriter, value int, context int) {
+	offset := writer.Offset()
+	writer.Seek(int64(schema.OffsetOffset), io.SeekStart)
+	writer.WriteInt(int(value))
+	writer.Seek(offset, io.SeekStart)
+}
+
+func (schema *LimitsSchema) forwardWriteOffset(writer *goschema.SchemaWriter, value int, context int) {
+	writer.WriteInt(int(value))
+}
+
+func (schema *LimitsSchema) ReadOffsetInto(reader *goschema.SchemaReader, value *int, context int) error {
+	if schema.OffsetOffset == -1 {
+		var tmp int
+		*value = tmp
+		return nil
+	}
+	offset := reader.Offset()
+	reader.Seek(int64(schema.OffsetOffset), io.SeekStart)
+	if schema.OffsetType != goschema.TypeCode(12) {
+		*value = int(reader.ReadWidenedInt(schema.OffsetType))
+		reader.Seek(offset, io.SeekStart)
+		return reader.Err()
+	}
+	*value = int(reader.ReadInt())
+	reader.Seek(offset, io.SeekStart)
+	return reader.Err()
+}
+
+func (view LimitsView) GetOffset(context int) (int, error) {
+	var value int
+	reader := view.reader
+	originalBase := reader.Base()
+	offset := reader.GlobalOffset()
+	reader.View(reader.Local(view.base))
+	err := view.schema.ReadOffsetInto(reader, &value, context)
+	reader.View(reader.Local(originalBase))
+	reader.Seek(reader.Local(offset), io.SeekStart)
+	return value, err
+}
+
+func (schema *LimitsSchema) GetOffset(reader *goschema.SchemaReader, context int) (int, error) {
+	offset := reader.Offset()
+	view, err := schema.NakedView(reader)
+	reader.Seek(offset, io.SeekStart)
+	if err != nil {
+		var value int
+		return value, err
+	}
+	return view.GetOffset(context)
+}
+
+func (schema *LimitsSchema) WriteRatio(writer *goschema.SchemaWriter, value float32, context int) {
+	offset := writer.Offset()
+	writer.Seek(int64(schema.RatioOffset), io.SeekStart)
+	writer.WriteFloat32(float32(value))
+	writer.Seek(offset, io.SeekStart)
+}
+
+func (schema *LimitsSchema) forwardWriteRatio(writer *goschema.SchemaWriter, value float32, context int) {
+	writer.WriteFloat32(float32(value))
+}
+
+func (schema *LimitsSchema) ReadRatioInto(reader *goschema.SchemaReader, value *float32, context int) error {
+	if schema.RatioOffset == -1 {
+		var tmp float32
+		*value = tmp
+		return nil
+	}
+	offset := reader.Offset()
+	reader.Seek(int64(schema.RatioOffset), io.SeekStart)
+	if schema.RatioType != goschema.TypeCode(13) {
+		*value = float32(reader.ReadWidenedFloat(schema.RatioType))
+		reader.Seek(offset, io.SeekStart)
+		return reader.Err()
+	}
+	*value = float32(reader.ReadFloat32())
+	reader.Seek(offset, io.SeekStart)
+	return reader.Err()
+}
+
+func (view LimitsView) GetRatio(context int) (float32, error) {
+	var value float32
+	reader := view.reader
+	originalBase := reader.Base()
+	offset := reader.GlobalOffset()
+	reader.View(reader.Local(view.base))
+	err := view.schema.ReadRatioInto(reader, &value, context)
+	reader.View(reader.Local(originalBase))
+	reader.Seek(reader.Local(offset), io.SeekStart)
+	return value, err
+}
+
+func (schema *LimitsSchema) GetRatio(reader *goschema.SchemaReader, context int) (float32, error) {
+	offset := reader.Offset()
+	view, err := schema.NakedView(reader)
+	reader.Seek(offset, io.SeekStart)
+	if err != nil {
+		var value float32
+		return value, err
+	}
+	return view.GetRatio(context)
+}
+
+func (schema *LimitsSchema) WriteWeight(writer *goschema.SchemaWriter, value float64, context int) {
+	offset := writer.Offset()
+	writer.Seek(int64(schema.WeightOffset), io.SeekStart)
+	writer.WriteFloat64(float64(value))
+	writer.Seek(offset, io.SeekStart)
+}
+
+func (schema *LimitsSchema) forwardWriteWeight(writer *goschema.SchemaWriter, value float64, context int) {
+	writer.WriteFloat64(float64(value))
+}
+
+func (schema *LimitsSchema) ReadWeightInto(reader *goschema.SchemaReader, value *float64, context int) error {
+	if schema.WeightOffset == -1 {
+		var tmp float64
+		*value = tmp
+		return nil
+	}
+	offset := reader.Offset()
+	reader.Seek(int64(schema.WeightOffset), io.SeekStart)
+	if schema.WeightType != goschema.TypeCode(14) {
+		*value = float64(reader.ReadWidenedFloat(schema.WeightType))
+		reader.Seek(offset, io.SeekStart)
+		return reader.Err()
+	}
+	*value = float64(reader.ReadFloat64())
+	reader.Seek(offset, io.SeekStart)
+	return reader.Err()
+}
+
+func (view LimitsView) GetWeight(context int) (float64, error) {
+	var value float64
+	reader := view.reader
+	originalBase := reader.Base()
+	offset := reader.GlobalOffset()
+	reader.View(reader.Local(view.base))
+	err := view.schema.ReadWeightInto(reader, &value, context)
+	reader.View(reader.Local(originalBase))
+	reader.Seek(reader.Local(offset), io.SeekStart)
+	return value, err
+}
+
+func (schema *LimitsSchema) GetWeight(reader *goschema.SchemaReader, context int) (float64, error) {
+	offset := reader.Offset()
+	view, err := schema.NakedView(reader)
+	reader.Seek(offset, io.SeekStart)
+	if err != nil {
+		var value float64
+		return value, err
+	}
+	return view.GetWeight(context)
+}
+
+func (schema *LimitsSchema) WriteProfile(writer *goschema.SchemaWriter, value *fixtures.Profile, context int) {
+	offset := writer.Offset()
+	writer.Seek(int64(schema.ProfileOffset), io.SeekStart)
+	writer.WriteUInt32(uint32(offset))
+	writer.Seek(offset, io.SeekStart)
+	v0Schema := WriteProfileAutoGenSchema(writer)
+	v0ViewBase := writer.Base()
+	v0Schema.nakedWrite(writer, value, context)
+	writer.View(writer.Local(v0ViewBase))
+}
+
+func (schema *LimitsSchema) forwardWriteProfile(writer *goschema.SchemaWriter, value *fixtures.Profile, context int) {
+	v0Schema := WriteProfileAutoGenSchema(writer)
+	v0ViewBase := writer.Base()
+	v0Schema.nakedWrite(writer, value, context)
+	writer.View(writer.Local(v0ViewBase))
+
+}
+
+func (schema *LimitsSchema) ReadProfileInto(reader *goschema.SchemaReader, value *fixtures.Profile, context int) error {
+	if schema.ProfileOffset == -1 {
+		var tmp fixtures.Profile
+		tmp.SetSchemaDefaults()
+		*value = tmp
+		return nil
+	}
+	offset := reader.Offset()
+	reader.Seek(int64(schema.ProfileOffset), io.SeekStart)
+	fieldOffset := reader.ReadUInt32()
+	reader.Seek(int64(fieldOffset), io.SeekStart)
+	v1Schema := ReadProfileAutoGenSchema(reader)
+	v1ViewBase := reader.Base()
+	if err := v1Schema.NakedRead(reader, value, context); err != nil {
+		return err
+	}
+	reader.View(reader.Local(v1ViewBase))
+
+	reader.Seek(offset, io.SeekStart)
+	return reader.Err()
+}
+
+func (view LimitsView) GetProfile(context int) (fixtures.Profile, error) {
+	var value fixtures.Profile
+	reader := view.reader
+	originalBase := reader.Base()
+	offset := reader.GlobalOffset()
+	reader.View(reader.Local(view.base))
+	err := view.schema.ReadProfileInto(reader, &value, context)
+	reader.View(reader.Local(originalBase))
+	reader.Seek(reader.Local(offset), io.SeekStart)
+	return value, err
+}
+
+func (schema *LimitsSchema) GetProfile(reader *goschema.SchemaReader, context int) (fixtures.Profile, error) {
+	offset := reader.Offset()
+	view, err := schema.NakedView(reader)
+	reader.Seek(offset, io.SeekStart)
+	if err != nil {
+		var value fixtures.Profile
+		return value, err
+	}
+	return view.GetProfile(context)
+}
@@ -0,0 +1,394 @@
+package generated
+
+import (
+	"github.com/chasingcarrots/goschema"
+	"io"
+
+	"github.com/chasingcarrots/goschema/generator/testdata/fixtures"
+)
+
+const NodeSchemaID goschema.SchemaID = 1
+const NodeSchemaFingerprint uint64 = 0x61f7a9bec873a27d
+const NodeSchemaName = "Node"
+
+type NodeSchema struct {
+	ValueOffset    int
+	ValueType      goschema.TypeCode
+	ChildrenOffset int
+
+	descriptor []goschema.SchemaEntry
+}
+
+func NewNodeSchema() *NodeSchema {
+	schema := NodeSchema{}
+	schema.init()
+	return &schema
+}
+
+func (schema *NodeSchema) ID() goschema.SchemaID {
+	return NodeSchemaID
+}
+
+func (schema *NodeSchema) Fingerprint() uint64 {
+	return NodeSchemaFingerprint
+}
+
+func (schema *NodeSchema) Name() string {
+	return NodeSchemaName
+}
+
+func (schema *NodeSchema) GoType() string {
+	return "github.com/chasingcarrots/goschema/generator/testdata/fixtures.Node"
+}
+
+func (schema *NodeSchema) Fill(entries []goschema.SchemaEntry) {
+	schema.ValueOffset = -1
+	schema.ChildrenOffset = -1
+
+	for i := range entries {
+		switch entries[i].Name {
+		case "Value":
+			// the current name of a field takes precedence over its aliases
+			if schema.descriptor[0].CanRead(&entries[i]) {
+				schema.ValueOffset = int(entries[i].Offset)
+				schema.ValueType = entries[i].Type
+			}
+		case "Children":
+			// the current name of a field takes precedence over its aliases
+			if schema.descriptor[1].CanRead(&entries[i]) {
+				schema.ChildrenOffset = int(entries[i].Offset)
+			}
+		}
+	}
+}
+
+func (schema *NodeSchema) init() {
+	if schema.descriptor == nil {
+		schema.descriptor = make([]goschema.SchemaEntry, 0, 2)
+		schema.descriptor = append(schema.descriptor,
+			goschema.SchemaEntry{
+				Name:       "Value",
+				Type:       goschema.TypeCode(10),
+				Offset:     0,
+				Descriptor: &goschema.TypeDescriptor{Code: goschema.TypeCode(10)},
+			},
+		)
+		schema.ValueOffset = 0
+		schema.ValueType = goschema.TypeCode(10)
+		schema.descriptor = append(schema.descriptor,
+			goschema.SchemaEntry{
+				Name:       "Children",
+				Type:       goschema.TypeCode(2),
+				Offset:     4,
+				Descriptor: &goschema.TypeDescriptor{Code: goschema.TypeCode(2), Elem: &goschema.TypeDescriptor{Code: goschema.TypeCode(0), Schema: "Node"}},
+			},
+		)
+		schema.ChildrenOffset = 4
+	}
+}
+
+// DescribeDeprecated returns the entries of the fields that are read, but no
+// longer written.
+func (schema *NodeSchema) DescribeDeprecated() []goschema.SchemaEntry {
+	return nil
+}
+
+func (schema *NodeSchema) Describe() []goschema.SchemaEntry {
+	return schema.descriptor
+}
+
+func ReadNodeSchema(reader *goschema.SchemaReader) *NodeSchema {
+	schemaIdx := int(reader.ReadUInt32())
+	existingSchema, schemaEntries := reader.FindSchema(int(schemaIdx))
+	schema, ok := existingSchema.(*NodeSchema)
+	if existingSchema == nil || !ok {
+		schema = NewNodeSchema()
+		reader.VerifySchemaName(schemaIdx, NodeSchemaName)
+		// the offsets of a new schema already match data with the same fingerprint
+		if fingerprint, ok := reader.SchemaFingerprint(schemaIdx); !ok || fingerprint != NodeSchemaFingerprint {
+			schema.Fill(schemaEntries)
+		}
+		reader.RegisterSchema(schemaIdx, schema)
+	}
+	return schema
+}
+
+func WriteNodeSchema(writer *goschema.SchemaWriter) *NodeSchema {
+	schemaEntry, ok := writer.FindSchema(goschema.SchemaID(NodeSchemaID))
+	schemaIdx := schemaEntry.Index()
+	schema, ok := schemaEntry.Schema().(*NodeSchema)
+	if !ok {
+		schema = NewNodeSchema()
+		schemaIdx = writer.RegisterSchema(schema)
+	}
+	writer.WriteUInt32(uint32(schemaIdx))
+	return schema
+}
+
+func (schema *NodeSchema) SingleRead(reader *goschema.SchemaReader, value *fixtures.Node, context int) error {
+	originalBase := reader.Base()
+	err := schema.NakedRead(reader, value, context)
+	reader.View(reader.Local(originalBase))
+	return err
+}
+
+func (schema *NodeSchema) NakedRead(reader *goschema.SchemaReader, value *fixtures.Node, context int) error {
+	nextOffset, err := reader.BeginObject("Node")
+	if err != nil {
+		return err
+	}
+	defer reader.EndObject()
+	if err := schema.ReadValueInto(reader, &value.Value, context); err != nil {
+		return err
+	}
+	if err := schema.ReadChildrenInto(reader, &value.Children, context); err != nil {
+		return goschema.PrefixFieldPath(err, "Children")
+	}
+	reader.Seek(reader.Local(nextOffset), io.SeekStart)
+	return reader.Err()
+}
+
+// NodeView gives access to single fields of an object without
+// reading the whole object.
+type NodeView struct {
+	schema *NodeSchema
+	reader *goschema.SchemaReader
+	base   int64 // global offset of the object's data
+}
+
+// NakedView creates a view of the object at the current position of the reader
+// and moves the reader past the object, like NakedRead would. The fields of the
+// object are only read when they are requested from the view. If the object
+// carries a checksum, NakedView verifies it and thus reads the whole object.
+func (schema *NodeSchema) NakedView(reader *goschema.SchemaReader) (NodeView, error) {
+	originalBase := reader.Base()
+	nextOffset, err := reader.BeginObject("Node")
+	if err != nil {
+		reader.View(reader.Local(originalBase))
+		return NodeView{}, err
+	}
+	reader.EndObject()
+	view := NodeView{
+		schema: schema,
+		reader: reader,
+		base:   reader.Base(),
+	}
+	reader.Seek(reader.Local(nextOffset), io.SeekStart)
+	reader.View(reader.Local(originalBase))
+	return view, nil
+}
+
+func (schema *NodeSchema) SingleWrite(writer *goschema.SchemaWriter, value *fixtures.Node, context int) {
+	originalBase := writer.Base()
+	schema.NakedWrite(writer, value, context)
+	writer.View(writer.Local(originalBase))
+}
+
+func (schema *NodeSchema) NakedWrite(writer *goschema.SchemaWriter, value *fixtures.Node, context int) {
+	schema.nakedWrite(writer, value, context)
+}
+
+// SingleWritePrepared is like SingleWrite, but does not run the
+// BeforeSchemaWrite hooks. It writes a value that RunBeforeWriteHooks has
+// prepared, e.g. after checking its EncodedSize.
+func (schema *NodeSchema) SingleWritePrepared(writer *goschema.SchemaWriter, value *fixtures.Node, context int) {
+	originalBase := writer.Base()
+	schema.nakedWrite(writer, value, context)
+	writer.View(writer.Local(originalBase))
+}
+
+// RunBeforeWriteHooks runs the BeforeSchemaWrite hooks of the value and of all
+// objects that it contains, like SingleWrite does before writing anything.
+func (schema *NodeSchema) RunBeforeWriteHooks(value *fixtures.Node, context int) {
+}
+
+// nakedWrite writes an object whose hooks have run already. It is called for
+// nested objects.
+func (schema *NodeSchema) nakedWrite(writer *goschema.SchemaWriter, value *fixtures.Node, context int) {
+	if writer.Err() != nil {
+		return
+	}
+	if writer.IsForward() {
+		schema.forwardNakedWrite(writer, value, context)
+		return
+	}
+	startOffset := writer.BeginObject()
+	writer.Seek(8, io.SeekCurrent)
+	schema.WriteValue(writer, value.Value, context)
+	schema.WriteChildren(writer, value.Children, context)
+	if writer.FailedIn("Children") {
+		return
+	}
+	writer.EndObject(startOffset)
+}
+
+// forwardNakedWrite produces the same data as NakedWrite without seeking by
+// computing all references up front.
+func (schema *NodeSchema) forwardNakedWrite(writer *goschema.SchemaWriter, value *fixtures.Node, context int) {
+	overhead := writer.ObjectOverhead()
+	startOffset := writer.BeginSizedObject(sizeNode(value, overhead) - overhead)
+	reference := 8
+	schema.forwardWriteValue(writer, value.Value, context)
+	writer.WriteUInt32(uint32(reference))
+	reference += 1 + 4 + 4
+	for v3I := range value.Children {
+		reference += sizeNode(&value.Children[v3I], overhead)
+	}
+
+	schema.forwardWriteChildren(writer, value.Children, context)
+	if writer.FailedIn("Children") {
+		return
+	}
+	writer.EndObject(startOffset)
+}
+
+// EncodedSize returns the number of bytes that SingleWritePrepared writes for
+// the given value, assuming that checksums are disabled. It does not include
+// the index of the schema written by WriteNodeSchema, and it does
+// not run any hooks.
+func (schema *NodeSchema) EncodedSize(value *fixtures.Node) int {
+	return sizeNode(value, 4)
+}
+
+// sizeNode returns the number of bytes written by NakedWrite for
+// the given value if each object takes up overhead bytes besides its data.
+func sizeNode(value *fixtures.Node, overhead int) int {
+	size := overhead + 8
+	size += 1 + 4 + 4
+	for v2I := range value.Children {
+		size += sizeNode(&value.Children[v2I], overhead)
+	}
+
+	return size
+}
+
+func (schema *NodeSchema) WriteValue(writer *goschema.SchemaWriter, value int32, context int) {
+	offset := writer.Offset()
+	writer.Seek(int64(schema.ValueOffset), io.SeekStart)
+	writer.WriteInt32(int32(value))
+	writer.Seek(offset, io.SeekStart)
+}
+
+func (schema *NodeSchema) forwardWriteValue(writer *goschema.SchemaWriter, value int32, context int) {
+	writer.WriteInt32(int32(value))
+}
+
+func (schema *NodeSchema) ReadValueInto(reader *goschema.SchemaReader, value *int32, context int) error {
+	if schema.ValueOffset == -1 {
+		var tmp int32
+		*value = tmp
+		return nil
+	}
+	offset := reader.Offset()
+	reader.Seek(int64(schema.ValueOffset), io.SeekStart)
+	if schema.ValueType != goschema.TypeCode(10) {
+		*value = int32(reader.ReadWidenedInt(schema.ValueType))
+		reader.Seek(offset, io.SeekStart)
+		return reader.Err()
+	}
+	*value = int32(reader.ReadInt32())
+	reader.Seek(offset, io.SeekStart)
+	return reader.Err()
+}
+
+func (view NodeView) GetValue(context int) (int32, error) {
+	var value int32
+	reader := view.reader
+	originalBase := reader.Base()
+	offset := reader.GlobalOffset()
+	reader.View(reader.Local(view.base))
+	err := view.schema.ReadValueInto(reader, &value, context)
+	reader.View(reader.Local(originalBase))
+	reader.Seek(reader.Local(offset), io.SeekStart)
+	return value, err
+}
+
+func (schema *NodeSchema) GetValue(reader *goschema.SchemaReader, context int) (int32, error) {
+	offset := reader.Offset()
+	view, err := schema.NakedView(reader)
+	reader.Seek(offset, io.SeekStart)
+	if err != nil {
+		var value int32
+		return value, err
+	}
+	return view.GetValue(context)
+}
+
+func (schema *NodeSchema) WriteChildren(writer *goschema.SchemaWriter, value []fixtures.Node, context int) {
+	offset := writer.Offset()
+	writer.Seek(int64(schema.ChildrenOffset), io.SeekStart)
+	writer.WriteUInt32(uint32(offset))
+	writer.Seek(offset, io.SeekStart)
+	writer.WriteUInt8(uint8(goschema.TypeCode(0)))
+	v0ViewBase := writer.Base()
+	v0Schema := WriteNodeSchema(writer)
+	v0Length := len(value)
+	writer.WriteUInt32(uint32(v0Length))
+	for v0I := 0; v0I < v0Length; v0I++ {
+		v0Schema.nakedWrite(writer, &value[v0I], context)
+	}
+	writer.View(writer.Local(v0ViewBase))
+}
+
+func (schema *NodeSchema) forwardWriteChildren(writer *goschema.SchemaWriter, value []fixtures.Node, context int) {
+	writer.WriteUInt8(uint8(goschema.TypeCode(0)))
+	v0ViewBase := writer.Base()
+	v0Schema := WriteNodeSchema(writer)
+	v0Length := len(value)
+	writer.WriteUInt32(uint32(v0Length))
+	for v0I := 0; v0I < v0Length; v0I++ {
+		v0Schema.nakedWrite(writer, &value[v0I], context)
+	}
+	writer.View(writer.Local(v0ViewBase))
+
+}
+
+func (schema *NodeSchema) ReadChildrenInto(reader *goschema.SchemaReader, value *[]fixtures.Node, context int) error {
+	if schema.ChildrenOffset == -1 {
+		var tmp []fixtures.Node
+		*value = tmp
+		return nil
+	}
+	offset := reader.Offset()
+	reader.Seek(int64(schema.ChildrenOffset), io.SeekStart)
+	fieldOffset := reader.ReadUInt32()
+	reader.Seek(int64(fieldOffset), io.SeekStart)
+	_ = reader.ReadUInt8() // ignore typecode
+	v1Schema := ReadNodeSchema(reader)
+	v1ViewBase := reader.Base()
+	v1Entries := reader.ReadCollectionLength(32)
+	v1Slice := make([]fixtures.Node, v1Entries, v1Entries)
+	for v1I := 0; v1I < v1Entries; v1I++ {
+		if err := v1Schema.NakedRead(reader, &v1Slice[v1I], context); err != nil {
+			return err
+		}
+	}
+	*value = v1Slice
+	reader.View(reader.Local(v1ViewBase))
+
+	reader.Seek(offset, io.SeekStart)
+	return reader.Err()
+}
+
+func (view NodeView) GetChildren(context int) ([]fixtures.Node, error) {
+	var value []fixtures.Node
+	reader := view.reader
+	originalBase := reader.Base()
+	offset := reader.GlobalOffset()
+	reader.View(reader.Local(view.base))
+	err := view.schema.ReadChildrenInto(reader, &value, context)
+	reader.View(reader.Local(originalBase))
+	reader.Seek(reader.Local(offset), io.SeekStart)
+	return value, err
+}
+
+func (schema *NodeSchema) GetChildren(reader *goschema.SchemaReader, context int) ([]fixtures.Node, error) {
+	offset := reader.Offset()
+	view, err := schema.NakedView(reader)
+	reader.Seek(offset, io.SeekStart)
+	if err != nil {
+		var value []fixtures.Node
+		return value, err
+	}
+	return view.GetChildren(context)
+}
@@ -0,0 +1,363 @@
+package generated
+
+import (
+	"github.com/chasingcarrots/goschema"
+	"io"
+
+	"github.com/chasingcarrots/goschema/generator/testdata/fixtures"
+)
+
+const RangedSchemaID goschema.SchemaID = 4
+const RangedSchemaFingerprint uint64 = 0x9724d0299d9935f0
+const RangedSchemaName = "Ranged"
+
+type RangedSchema struct {
+	LevelOffset  int
+	LevelType    goschema.TypeCode
+	LegacyOffset int
+	LegacyType   goschema.TypeCode
+
+	descriptor []goschema.SchemaEntry
+	deprecated []goschema.SchemaEntry // fields that are read, but no longer written
+}
+
+func NewRangedSchema() *RangedSchema {
+	schema := RangedSchema{}
+	schema.init()
+	return &schema
+}
+
+func (schema *RangedSchema) ID() goschema.SchemaID {
+	return RangedSchemaID
+}
+
+func (schema *RangedSchema) Fingerprint() uint64 {
+	return RangedSchemaFingerprint
+}
+
+func (schema *RangedSchema) Name() string {
+	return RangedSchemaName
+}
+
+func (schema *RangedSchema) GoType() string {
+	return "github.com/chasingcarrots/goschema/generator/testdata/fixtures.Ranged"
+}
+
+func (schema *RangedSchema) Fill(entries []goschema.SchemaEntry) {
+	schema.LevelOffset = -1
+	schema.LegacyOffset = -1
+
+	for i := range entries {
+		switch entries[i].Name {
+		case "Level":
+			// the current name of a field takes precedence over its aliases
+			if schema.descriptor[0].CanRead(&entries[i]) {
+				schema.LevelOffset = int(entries[i].Offset)
+				schema.LevelType = entries[i].Type
+			}
+		case "Legacy":
+			// the current name of a field takes precedence over its aliases
+			if schema.deprecated[0].CanRead(&entries[i]) {
+				schema.LegacyOffset = int(entries[i].Offset)
+				schema.LegacyType = entries[i].Type
+			}
+		}
+	}
+}
+
+func (schema *RangedSchema) init() {
+	if schema.descriptor == nil {
+		schema.descriptor = make([]goschema.SchemaEntry, 0, 1)
+		schema.descriptor = append(schema.descriptor,
+			goschema.SchemaEntry{
+				Name:       "Level",
+				Type:       goschema.TypeCode(10),
+				Offset:     0,
+				Descriptor: &goschema.TypeDescriptor{Code: goschema.TypeCode(10)},
+			},
+		)
+		schema.LevelOffset = 0
+		schema.LevelType = goschema.TypeCode(10)
+		schema.deprecated = append(schema.deprecated,
+			goschema.SchemaEntry{
+				Name:       "Legacy",
+				Type:       goschema.TypeCode(10),
+				Descriptor: &goschema.TypeDescriptor{Code: goschema.TypeCode(10)},
+			},
+		)
+		schema.LegacyOffset = -1
+		schema.LegacyType = goschema.TypeCode(10)
+	}
+}
+
+// DescribeDeprecated returns the entries of the fields that are read, but no
+// longer written.
+func (schema *RangedSchema) DescribeDeprecated() []goschema.SchemaEntry {
+	return schema.deprecated
+}
+
+func (schema *RangedSchema) Describe() []goschema.SchemaEntry {
+	return schema.descriptor
+}
+
+func ReadRangedSchema(reader *goschema.SchemaReader) *RangedSchema {
+	schemaIdx := int(reader.ReadUInt32())
+	existingSchema, schemaEntries := reader.FindSchema(int(schemaIdx))
+	schema, ok := existingSchema.(*RangedSchema)
+	if existingSchema == nil || !ok {
+		schema = NewRangedSchema()
+		reader.VerifySchemaName(schemaIdx, RangedSchemaName)
+		// the offsets of a new schema already match data with the same fingerprint
+		if fingerprint, ok := reader.SchemaFingerprint(schemaIdx); !ok || fingerprint != RangedSchemaFingerprint {
+			schema.Fill(schemaEntries)
+		}
+		reader.RegisterSchema(schemaIdx, schema)
+	}
+	return schema
+}
+
+func WriteRangedSchema(writer *goschema.SchemaWriter) *RangedSchema {
+	schemaEntry, ok := writer.FindSchema(goschema.SchemaID(RangedSchemaID))
+	schemaIdx := schemaEntry.Index()
+	schema, ok := schemaEntry.Schema().(*RangedSchema)
+	if !ok {
+		schema = NewRangedSchema()
+		schemaIdx = writer.RegisterSchema(schema)
+	}
+	writer.WriteUInt32(uint32(schemaIdx))
+	return schema
+}
+
+func (schema *RangedSchema) SingleRead(reader *goschema.SchemaReader, value *fixtures.Ranged, context int) error {
+	originalBase := reader.Base()
+	err := schema.NakedRead(reader, value, context)
+	reader.View(reader.Local(originalBase))
+	return err
+}
+
+func (schema *RangedSchema) NakedRead(reader *goschema.SchemaReader, value *fixtures.Ranged, context int) error {
+	nextOffset, err := reader.BeginObject("Ranged")
+	if err != nil {
+		return err
+	}
+	defer reader.EndObject()
+	if err := schema.ReadLevelInto(reader, &value.Level, context); err != nil {
+		return err
+	}
+	if err := schema.ReadLegacyInto(reader, &value.Legacy, context); err != nil {
+		return err
+	}
+	reader.Seek(reader.Local(nextOffset), io.SeekStart)
+	if err := reader.Err(); err != nil {
+		return err
+	}
+	if err := schema.validate(value, false); err != nil {
+		return err
+	}
+	return nil
+}
+
+// validate checks the constraints declared by the tags of the fields. Deprecated
+// fields are not checked when writing, since they are not written.
+func (schema *RangedSchema) validate(value *fixtures.Ranged, writing bool) error {
+	if value.Level < 1 {
+		return &goschema.ValidationError{Schema: RangedSchemaName, Field: "Level", Constraint: "schemaMin:\"1\""}
+	}
+	if value.Level > 10 {
+		return &goschema.ValidationError{Schema: RangedSchemaName, Field: "Level", Constraint: "schemaMax:\"10\""}
+	}
+	if !writing && (value.Legacy < 1) {
+		return &goschema.ValidationError{Schema: RangedSchemaName, Field: "Legacy", Constraint: "schemaMin:\"1\""}
+	}
+	return nil
+}
+
+// RangedView gives access to single fields of an object without
+// reading the whole object.
+type RangedView struct {
+	schema *RangedSchema
+	reader *goschema.SchemaReader
+	base   int64 // global offset of the object's data
+}
+
+// NakedView creates a view of the object at the current position of the reader
+// and moves the reader past the object, like NakedRead would. The fields of the
+// object are only read when they are requested from the view. If the object
+// carries a checksum, NakedView verifies it and thus reads the whole object.
+func (schema *RangedSchema) NakedView(reader *goschema.SchemaReader) (RangedView, error) {
+	originalBase := reader.Base()
+	nextOffset, err := reader.BeginObject("Ranged")
+	if err != nil {
+		reader.View(reader.Local(originalBase))
+		return RangedView{}, err
+	}
+	reader.EndObject()
+	view := RangedView{
+		schema: schema,
+		reader: reader,
+		base:   reader.Base(),
+	}
+	reader.Seek(reader.Local(nextOffset), io.SeekStart)
+	reader.View(reader.Local(originalBase))
+	return view, nil
+}
+
+func (schema *RangedSchema) SingleWrite(writer *goschema.SchemaWriter, value *fixtures.Ranged, context int) {
+	originalBase := writer.Base()
+	schema.NakedWrite(writer, value, context)
+	writer.View(writer.Local(originalBase))
+}
+
+func (schema *RangedSchema) NakedWrite(writer *goschema.SchemaWriter, value *fixtures.Ranged, context int) {
+	schema.nakedWrite(writer, value, context)
+}
+
+// SingleWritePrepared is like SingleWrite, but does not run the
+// BeforeSchemaWrite hooks. It writes a value that RunBeforeWriteHooks has
+// prepared, e.g. after checking its EncodedSize.
+func (schema *RangedSchema) SingleWritePrepared(writer *goschema.SchemaWriter, value *fixtures.Ranged, context int) {
+	originalBase := writer.Base()
+	schema.nakedWrite(writer, value, context)
+	writer.View(writer.Local(originalBase))
+}
+
+// RunBeforeWriteHooks runs the BeforeSchemaWrite hooks of the value and of all
+// objects that it contains, like SingleWrite does before writing anything.
+func (schema *RangedSchema) RunBeforeWriteHooks(value *fixtures.Ranged, context int) {
+}
+
+// nakedWrite writes an object whose hooks have run already. It is called for
+// nested objects.
+func (schema *RangedSchema) nakedWrite(writer *goschema.SchemaWriter, value *fixtures.Ranged, context int) {
+	if writer.Err() != nil {
+		return
+	}
+	if err := schema.validate(value, true); err != nil {
+		writer.Fail(err)
+		return
+	}
+	if writer.IsForward() {
+		schema.forwardNakedWrite(writer, value, context)
+		return
+	}
+	startOffset := writer.BeginObject()
+	writer.Seek(4, io.SeekCurrent)
+	schema.WriteLevel(writer, value.Level, context)
+	writer.EndObject(startOffset)
+}
+
+// forwardNakedWrite produces the same data as NakedWrite without seeking by
+// computing all references up front.
+func (schema *RangedSchema) forwardNakedWrite(writer *goschema.SchemaWriter, value *fixtures.Ranged, context int) {
+	overhead := writer.ObjectOverhead()
+	startOffset := writer.BeginSizedObject(sizeRanged(value, overhead) - overhead)
+	schema.forwardWriteLevel(writer, value.Level, context)
+	writer.EndObject(startOffset)
+}
+
+// EncodedSize returns the number of bytes that SingleWritePrepared writes for
+// the given value, assuming that checksums are disabled. It does not include
+// the index of the schema written by WriteRangedSchema, and it does
+// not run any hooks.
+func (schema *RangedSchema) EncodedSize(value *fixtures.Ranged) int {
+	return sizeRanged(value, 4)
+}
+
+// sizeRanged returns the number of bytes written by NakedWrite for
+// the given value if each object takes up overhead bytes besides its data.
+func sizeRanged(value *fixtures.Ranged, overhead int) int {
+	size := overhead + 4
+	return size
+}
+
+func (schema *RangedSchema) WriteLevel(writer *goschema.SchemaWriter, value int32, context int) {
+	offset := writer.Offset()
+	writer.Seek(int64(schema.LevelOffset), io.SeekStart)
+	writer.WriteInt32(int32(value))
+	writer.Seek(offset, io.SeekStart)
+}
+
+func (schema *RangedSchema) forwardWriteLevel(writer *goschema.SchemaWriter, value int32, context int) {
+	writer.WriteInt32(int32(value))
+}
+
+func (schema *RangedSchema) ReadLevelInto(reader *goschema.SchemaReader, value *int32, context int) error {
+	if schema.LevelOffset == -1 {
+		var tmp int32
+		*value = tmp
+		return nil
+	}
+	offset := reader.Offset()
+	reader.Seek(int64(schema.LevelOffset), io.SeekStart)
+	if schema.LevelType != goschema.TypeCode(10) {
+		*value = int32(reader.ReadWidenedInt(schema.LevelType))
+		reader.Seek(offset, io.SeekStart)
+		return reader.Err()
+	}
+	*value = int32(reader.ReadInt32())
+	reader.Seek(offset, io.SeekStart)
+	return reader.Err()
+}
+
+func (view RangedView) GetLevel(context int) (int32, error) {
+	var value int32
+	reader := view.reader
+	originalBase := reader.Base()
+	offset := reader.GlobalOffset()
+	reader.View(reader.Local(view.base))
+	err := view.schema.ReadLevelInto(reader, &value, context)
+	reader.View(reader.Local(originalBase))
+	reader.Seek(reader.Local(offset), io.SeekStart)
+	return value, err
+}
+
+func (schema *RangedSchema) GetLevel(reader *goschema.SchemaReader, context int) (int32, error) {
+	offset := reader.Offset()
+	view, err := schema.NakedView(reader)
+	reader.Seek(offset, io.SeekStart)
+	if err != nil {
+		var value int32
+		return value, err
+	}
+	return view.GetLevel(context)
+}
+
+func (schema *RangedSchema) ReadLegacyInto(reader *goschema.SchemaReader, value *int32, context int) error {
+	if schema.LegacyOffset == -1 {
+		*value = int32(1)
+		return nil
+	}
+	offset := reader.Offset()
+	reader.Seek(int64(schema.LegacyOffset), io.SeekStart)
+	if schema.LegacyType != goschema.TypeCode(10) {
+		*value = int32(reader.ReadWidenedInt(schema.LegacyType))
+		reader.Seek(offset, io.SeekStart)
+		return reader.Err()
+	}
+	*value = int32(reader.ReadInt32())
+	reader.Seek(offset, io.SeekStart)
+	return reader.Err()
+}
+
+func (view RangedView) GetLegacy(context int) (int32, error) {
+	var value int32
+	reader := view.reader
+	originalBase := reader.Base()
+	offset := reader.GlobalOffset()
+	reader.View(reader.Local(view.base))
+	err := view.schema.ReadLegacyInto(reader, &value, context)
+	reader.View(reader.Local(originalBase))
+	reader.Seek(reader.Local(offset), io.SeekStart)
+	return value, err
+}
+
+func (schema *RangedSchema) GetLegacy(reader *goschema.SchemaReader, context int) (int32, error) {
+	offset := reader.Offset()
+	view, err := schema.NakedView(reader)
+	reader.Seek(offset, io.SeekStart)
+	if err != nil {
+		var value int32
+		return value, err
+	}
+	return view.GetLegacy(context)
+}
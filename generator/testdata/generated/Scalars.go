@@ -0,0 +1,1801 @@
+package generated
+
+import (
+	"github.com/chasingcarrots/goschema"
+	"io"
+
+	"github.com/chasingcarrots/goschema/generator/testdata/fixtures"
+)
+
+const ScalarsSchemaID goschema.SchemaID = 5
+const ScalarsSchemaFingerprint uint64 = 0x4dba12784f633c3b
+const ScalarsSchemaName = "Scalars"
+
+type ScalarsSchema struct {
+	FlagOffset     int
+	IntOffset      int
+	IntType        goschema.TypeCode
+	Int8Offset     int
+	ShortOffset    int
+	ShortType      goschema.TypeCode
+	Int32Offset    int
+	Int32Type      goschema.TypeCode
+	Int64Offset    int
+	Int64Type      goschema.TypeCode
+	UIntOffset     int
+	UIntType       goschema.TypeCode
+	UInt8Offset    int
+	UInt16Offset   int
+	UInt16Type     goschema.TypeCode
+	UnsignedOffset int
+	UnsignedType   goschema.TypeCode
+	UInt64Offset   int
+	UInt64Type     goschema.TypeCode
+	Float32Offset  int
+	Float32Type    goschema.TypeCode
+	Float64Offset  int
+	Float64Type    goschema.TypeCode
+	TextOffset     int
+	PositionOffset int
+	PathOffset     int
+	OriginOffset   int
+	CountsOffset   int
+	LookupOffset   int
+
+	descriptor []goschema.SchemaEntry
+}
+
+func NewScalarsSchema() *ScalarsSchema {
+	schema := ScalarsSchema{}
+	schema.init()
+	return &schema
+}
+
+func (schema *ScalarsSchema) ID() goschema.SchemaID {
+	return ScalarsSchemaID
+}
+
+func (schema *ScalarsSchema) Fingerprint() uint64 {
+	return ScalarsSchemaFingerprint
+}
+
+func (schema *ScalarsSchema) Name() string {
+	return ScalarsSchemaName
+}
+
+func (schema *ScalarsSchema) GoType() string {
+	return "github.com/chasingcarrots/goschema/generator/testdata/fixtures.Scalars"
+}
+
+func (schema *ScalarsSchema) Fill(entries []goschema.SchemaEntry) {
+	schema.FlagOffset = -1
+	schema.IntOffset = -1
+	schema.Int8Offset = -1
+	schema.ShortOffset = -1
+	schema.Int32Offset = -1
+	schema.Int64Offset = -1
+	schema.UIntOffset = -1
+	schema.UInt8Offset = -1
+	schema.UInt16Offset = -1
+	schema.UnsignedOffset = -1
+	schema.UInt64Offset = -1
+	schema.Float32Offset = -1
+	schema.Float64Offset = -1
+	schema.TextOffset = -1
+	schema.PositionOffset = -1
+	schema.PathOffset = -1
+	schema.OriginOffset = -1
+	schema.CountsOffset = -1
+	schema.LookupOffset = -1
+
+	for i := range entries {
+		switch entries[i].Name {
+		case "Flag":
+			// the current name of a field takes precedence over its aliases
+			if schema.descriptor[0].CanRead(&entries[i]) {
+				schema.FlagOffset = int(entries[i].Offset)
+			}
+		case "Int":
+			// the current name of a field takes precedence over its aliases
+			if schema.descriptor[1].CanRead(&entries[i]) {
+				schema.IntOffset = int(entries[i].Offset)
+				schema.IntType = entries[i].Type
+			}
+		case "Int8":
+			// the current name of a field takes precedence over its aliases
+			if schema.descriptor[2].CanRead(&entries[i]) {
+				schema.Int8Offset = int(entries[i].Offset)
+			}
+		case "Short":
+			// the current name of a field takes precedence over its aliases
+			if schema.descriptor[3].CanRead(&entries[i]) {
+				schema.ShortOffset = int(entries[i].Offset)
+				schema.ShortType = entries[i].Type
+			}
+		case "Int32":
+			// the current name of a field takes precedence over its aliases
+			if schema.descriptor[4].CanRead(&entries[i]) {
+				schema.Int32Offset = int(entries[i].Offset)
+				schema.Int32Type = entries[i].Type
+			}
+		case "Int64":
+			// the current name of a field takes precedence over its aliases
+			if schema.descriptor[5].CanRead(&entries[i]) {
+				schema.Int64Offset = int(entries[i].Offset)
+				schema.Int64Type = entries[i].Type
+			}
+		case "UInt":
+			// the current name of a field takes precedence over its aliases
+			if schema.descriptor[6].CanRead(&entries[i]) {
+				schema.UIntOffset = int(entries[i].Offset)
+				schema.UIntType = entries[i].Type
+			}
+		case "UInt8":
+			// the current name of a field takes precedence over its aliases
+			if schema.descriptor[7].CanRead(&entries[i]) {
+				schema.UInt8Offset = int(entries[i].Offset)
+			}
+		case "UInt16":
+			// the current name of a field takes precedence over its aliases
+			if schema.descriptor[8].CanRead(&entries[i]) {
+				schema.UInt16Offset = int(entries[i].Offset)
+				schema.UInt16Type = entries[i].Type
+			}
+		case "Unsigned":
+			// the current name of a field takes precedence over its aliases
+			if schema.descriptor[9].CanRead(&entries[i]) {
+				schema.UnsignedOffset = int(entries[i].Offset)
+				schema.UnsignedType = entries[i].Type
+			}
+		case "UInt64":
+			// the current name of a field takes precedence over its aliases
+			if schema.descriptor[10].CanRead(&entries[i]) {
+				schema.UInt64Offset = int(entries[i].Offset)
+				schema.UInt64Type = entries[i].Type
+			}
+		case "Float32":
+			// the current name of a field takes precedence over its aliases
+			if schema.descriptor[11].CanRead(&entries[i]) {
+				schema.Float32Offset = int(entries[i].Offset)
+				schema.Float32Type = entries[i].Type
+			}
+		case "Float64":
+			// the current name of a field takes precedence over its aliases
+			if schema.descriptor[12].CanRead(&entries[i]) {
+				schema.Float64Offset = int(entries[i].Offset)
+				schema.Float64Type = entries[i].Type
+			}
+		case "Text":
+			// the current name of a field takes precedence over its aliases
+			if schema.descriptor[13].CanRead(&entries[i]) {
+				schema.TextOffset = int(entries[i].Offset)
+			}
+		case "Position":
+			// the current name of a field takes precedence over its aliases
+			if schema.descriptor[14].CanRead(&entries[i]) {
+				schema.PositionOffset = int(entries[i].Offset)
+			}
+		case "Path":
+			// the current name of a field takes precedence over its aliases
+			if schema.descriptor[15].CanRead(&entries[i]) {
+				schema.PathOffset = int(entries[i].Offset)
+			}
+		case "Origin":
+			// the current name of a field takes precedence over its aliases
+			if schema.descriptor[16].CanRead(&entries[i]) {
+				schema.OriginOffset = int(entries[i].Offset)
+			}
+		case "Counts":
+			// the current name of a field takes precedence over its aliases
+			if schema.descriptor[17].CanRead(&entries[i]) {
+				schema.CountsOffset = int(entries[i].Offset)
+			}
+		case "Lookup":
+			// the current name of a field takes precedence over its aliases
+			if schema.descriptor[18].CanRead(&entries[i]) {
+				schema.LookupOffset = int(entries[i].Offset)
+			}
+		}
+	}
+}
+
+func (schema *ScalarsSchema) init() {
+	if schema.descriptor == nil {
+		schema.descriptor = make([]goschema.SchemaEntry, 0, 19)
+		schema.descriptor = append(schema.descriptor,
+			goschema.SchemaEntry{
+				Name:       "Flag",
+				Type:       goschema.TypeCode(15),
+				Offset:     0,
+				Descriptor: &goschema.TypeDescriptor{Code: goschema.TypeCode(15)},
+			},
+		)
+		schema.FlagOffset = 0
+		schema.descriptor = append(schema.descriptor,
+			goschema.SchemaEntry{
+				Name:       "Int",
+				Type:       goschema.TypeCode(12),
+				Offset:     1,
+				Descriptor: &goschema.TypeDescriptor{Code: goschema.TypeCode(12)},
+			},
+		)
+		schema.IntOffset = 1
+		schema.IntType = goschema.TypeCode(12)
+		schema.descriptor = append(schema.descriptor,
+			goschema.SchemaEntry{
+				Name:       "Int8",
+				Type:       goschema.TypeCode(8),
+				Offset:     9,
+				Descriptor: &goschema.TypeDescriptor{Code: goschema.TypeCode(8)},
+			},
+		)
+		schema.Int8Offset = 9
+		schema.descriptor = append(schema.descriptor,
+			goschema.SchemaEntry{
+				Name:       "Short",
+				Type:       goschema.TypeCode(9),
+				Offset:     10,
+				Descriptor: &goschema.TypeDescriptor{Code: goschema.TypeCode(9)},
+			},
+		)
+		schema.ShortOffset = 10
+		schema.ShortType = goschema.TypeCode(9)
+		schema.descriptor = append(schema.descriptor,
+			goschema.SchemaEntry{
+				Name:       "Int32",
+				Type:       goschema.TypeCode(10),
+				Offset:     12,
+				Descriptor: &goschema.TypeDescriptor{Code: goschema.TypeCode(10)},
+			},
+		)
+		schema.Int32Offset = 12
+		schema.Int32Type = goschema.TypeCode(10)
+		schema.descriptor = append(schema.descriptor,
+			goschema.SchemaEntry{
+				Name:       "Int64",
+				Type:       goschema.TypeCode(11),
+				Offset:     16,
+				Descriptor: &goschema.TypeDescriptor{Code: goschema.TypeCode(11)},
+			},
+		)
+		schema.Int64Offset = 16
+		schema.Int64Type = goschema.TypeCode(11)
+		schema.descriptor = append(schema.descriptor,
+			goschema.SchemaEntry{
+				Name:       "UInt",
+				Type:       goschema.TypeCode(7),
+				Offset:     24,
+				Descriptor: &goschema.TypeDescriptor{Code: goschema.TypeCode(7)},
+			},
+		)
+		schema.UIntOffset = 24
+		schema.UIntType = goschema.TypeCode(7)
+		schema.descriptor = append(schema.descriptor,
+			goschema.SchemaEntry{
+				Name:       "UInt8",
+				Type:       goschema.TypeCode(3),
+				Offset:     32,
+				Descriptor: &goschema.TypeDescriptor{Code: goschema.TypeCode(3)},
+			},
+		)
+		schema.UInt8Offset = 32
+		schema.descriptor = append(schema.descriptor,
+			goschema.SchemaEntry{
+				Name:       "UInt16",
+				Type:       goschema.TypeCode(4),
+				Offset:     33,
+				Descriptor: &goschema.TypeDescriptor{Code: goschema.TypeCode(4)},
+			},
+		)
+		schema.UInt16Offset = 33
+		schema.UInt16Type = goschema.TypeCode(4)
+		schema.descriptor = append(schema.descriptor,
+			goschema.SchemaEntry{
+				Name:       "Unsigned",
+				Type:       goschema.TypeCode(5),
+				Offset:     35,
+				Descriptor: &goschema.TypeDescriptor{Code: goschema.TypeCode(5)},
+			},
+		)
+		schema.UnsignedOffset = 35
+		schema.UnsignedType = goschema.TypeCode(5)
+		schema.descriptor = append(schema.descriptor,
+			goschema.SchemaEntry{
+				Name:       "UInt64",
+				Type:       goschema.TypeCode(6),
+				Offset:     39,
+				Descriptor: &goschema.TypeDescriptor{Code: goschema.TypeCode(6)},
+			},
+		)
+		schema.UInt64Offset = 39
+		schema.UInt64Type = goschema.TypeCode(6)
+		schema.descriptor = append(schema.descriptor,
+			goschema.SchemaEntry{
+				Name:       "Float32",
+				Type:       goschema.TypeCode(13),
+				Offset:     47,
+				Descriptor: &goschema.TypeDescriptor{Code: goschema.TypeCode(13)},
+			},
+		)
+		schema.Float32Offset = 47
+		schema.Float32Type = goschema.TypeCode(13)
+		schema.descriptor = append(schema.descriptor,
+			goschema.SchemaEntry{
+				Name:       "Float64",
+				Type:       goschema.TypeCode(14),
+				Offset:     51,
+				Descriptor: &goschema.TypeDescriptor{Code: goschema.TypeCode(14)},
+			},
+		)
+		schema.Float64Offset = 51
+		schema.Float64Type = goschema.TypeCode(14)
+		schema.descriptor = append(schema.descriptor,
+			goschema.SchemaEntry{
+				Name:       "Text",
+				Type:       goschema.TypeCode(16),
+				Offset:     59,
+				Descriptor: &goschema.TypeDescriptor{Code: goschema.TypeCode(16)},
+			},
+		)
+		schema.TextOffset = 59
+		schema.descriptor = append(schema.descriptor,
+			goschema.SchemaEntry{
+				Name:       "Position",
+				Type:       goschema.TypeCode(255),
+				Offset:     63,
+				Descriptor: &goschema.TypeDescriptor{Code: goschema.TypeCode(255)},
+			},
+		)
+		schema.PositionOffset = 63
+		schema.descriptor = append(schema.descriptor,
+			goschema.SchemaEntry{
+				Name:       "Path",
+				Type:       goschema.TypeCode(2),
+				Offset:     71,
+				Descriptor: &goschema.TypeDescriptor{Code: goschema.TypeCode(2), Elem: &goschema.TypeDescriptor{Code: goschema.TypeCode(255)}},
+			},
+		)
+		schema.PathOffset = 71
+		schema.descriptor = append(schema.descriptor,
+			goschema.SchemaEntry{
+				Name:       "Origin",
+				Type:       goschema.TypeCode(17),
+				Offset:     75,
+				Descriptor: &goschema.TypeDescriptor{Code: goschema.TypeCode(17), Elem: &goschema.TypeDescriptor{Code: goschema.TypeCode(255)}},
+			},
+		)
+		schema.OriginOffset = 75
+		schema.descriptor = append(schema.descriptor,
+			goschema.SchemaEntry{
+				Name:       "Counts",
+				Type:       goschema.TypeCode(17),
+				Offset:     79,
+				Descriptor: &goschema.TypeDescriptor{Code: goschema.TypeCode(17), Elem: &goschema.TypeDescriptor{Code: goschema.TypeCode(2), Elem: &goschema.TypeDescriptor{Code: goschema.TypeCode(10)}}},
+			},
+		)
+		schema.CountsOffset = 79
+		schema.descriptor = append(schema.descriptor,
+			goschema.SchemaEntry{
+				Name:       "Lookup",
+				Type:       goschema.TypeCode(17),
+				Offset:     83,
+				Descriptor: &goschema.TypeDescriptor{Code: goschema.TypeCode(17), Elem: &goschema.TypeDescriptor{Code: goschema.TypeCode(1), Key: &goschema.TypeDescriptor{Code: goschema.TypeCode(16)}, Elem: &goschema.TypeDescriptor{Code: goschema.TypeCode(9)}}},
+			},
+		)
+		schema.LookupOffset = 83
+	}
+}
+
+// DescribeDeprecated returns the entries of the fields that are read, but no
+// longer written.
+func (schema *ScalarsSchema) DescribeDeprecated() []goschema.SchemaEntry {
+	return nil
+}
+
+func (schema *ScalarsSchema) Describe() []goschema.SchemaEntry {
+	return schema.descriptor
+}
+
+func ReadScalarsSchema(reader *goschema.SchemaReader) *ScalarsSchema {
+	schemaIdx := int(reader.ReadUInt32())
+	existingSchema, schemaEntries := reader.FindSchema(int(schemaIdx))
+	schema, ok := existingSchema.(*ScalarsSchema)
+	if existingSchema == nil || !ok {
+		schema = NewScalarsSchema()
+		reader.VerifySchemaName(schemaIdx, ScalarsSchemaName)
+		// the offsets of a new schema already match data with the same fingerprint
+		if fingerprint, ok := reader.SchemaFingerprint(schemaIdx); !ok || fingerprint != ScalarsSchemaFingerprint {
+			schema.Fill(schemaEntries)
+		}
+		reader.RegisterSchema(schemaIdx, schema)
+	}
+	return schema
+}
+
+func WriteScalarsSchema(writer *goschema.SchemaWriter) *ScalarsSchema {
+	schemaEntry, ok := writer.FindSchema(goschema.SchemaID(ScalarsSchemaID))
+	schemaIdx := schemaEntry.Index()
+	schema, ok := schemaEntry.Schema().(*ScalarsSchema)
+	if !ok {
+		schema = NewScalarsSchema()
+		schemaIdx = writer.RegisterSchema(schema)
+	}
+	writer.WriteUInt32(uint32(schemaIdx))
+	return schema
+}
+
+func (schema *ScalarsSchema) SingleRead(reader *goschema.SchemaReader, value *fixtures.Scalars, context int) error {
+	originalBase := reader.Base()
+	err := schema.NakedRead(reader, value, context)
+	reader.View(reader.Local(originalBase))
+	return err
+}
+
+func (schema *ScalarsSchema) NakedRead(reader *goschema.SchemaReader, value *fixtures.Scalars, context int) error {
+	nextOffset, err := reader.BeginObject("Scalars")
+	if err != nil {
+		return err
+	}
+	defer reader.EndObject()
+	if err := schema.ReadFlagInto(reader, &value.Flag, context); err != nil {
+		return err
+	}
+	if err := schema.ReadIntInto(reader, &value.Int, context); err != nil {
+		return err
+	}
+	if err := schema.ReadInt8Into(reader, &value.Int8, context); err != nil {
+		return err
+	}
+	if err := schema.ReadShortInto(reader, &value.Int16, context); err != nil {
+		return err
+	}
+	if err := schema.ReadInt32Into(reader, &value.Int32, context); err != nil {
+		return err
+	}
+	if err := schema.ReadInt64Into(reader, &value.Int64, context); err != nil {
+		return err
+	}
+	if err := schema.ReadUIntInto(reader, &value.UInt, context); err != nil {
+		return err
+	}
+	if err := schema.ReadUInt8Into(reader, &value.UInt8, context); err != nil {
+		return err
+	}
+	if err := schema.ReadUInt16Into(reader, &value.UInt16, context); err != nil {
+		return err
+	}
+	if err := schema.ReadUnsignedInto(reader, &value.UInt32, context); err != nil {
+		return err
+	}
+	if err := schema.ReadUInt64Into(reader, &value.UInt64, context); err != nil {
+		return err
+	}
+	if err := schema.ReadFloat32Into(reader, &value.Float32, context); err != nil {
+		return err
+	}
+	if err := schema.ReadFloat64Into(reader, &value.Float64, context); err != nil {
+		return err
+	}
+	if err := schema.ReadTextInto(reader, &value.Text, context); err != nil {
+		return err
+	}
+	if err := schema.ReadPositionInto(reader, &value.Position, context); err != nil {
+		return err
+	}
+	if err := schema.ReadPathInto(reader, &value.Path, context); err != nil {
+		return err
+	}
+	if err := schema.ReadOriginInto(reader, &value.Origin, context); err != nil {
+		return err
+	}
+	if err := schema.ReadCountsInto(reader, &value.Counts, context); err != nil {
+		return err
+	}
+	if err := schema.ReadLookupInto(reader, &value.Lookup, context); err != nil {
+		return err
+	}
+	reader.Seek(reader.Local(nextOffset), io.SeekStart)
+	return reader.Err()
+}
+
+// ScalarsView gives access to single fields of an object without
+// reading the whole object.
+type ScalarsView struct {
+	schema *ScalarsSchema
+	reader *goschema.SchemaReader
+	base   int64 // global offset of the object's data
+}
+
+// NakedView creates a view of the object at the current position of the reader
+// and moves the reader past the object, like NakedRead would. The fields of the
+// object are only read when they are requested from the view. If the object
+// carries a checksum, NakedView verifies it and thus reads the whole object.
+func (schema *ScalarsSchema) NakedView(reader *goschema.SchemaReader) (ScalarsView, error) {
+	originalBase := reader.Base()
+	nextOffset, err := reader.BeginObject("Scalars")
+	if err != nil {
+		reader.View(reader.Local(originalBase))
+		return ScalarsView{}, err
+	}
+	reader.EndObject()
+	view := ScalarsView{
+		schema: schema,
+		reader: reader,
+		base:   reader.Base(),
+	}
+	reader.Seek(reader.Local(nextOffset), io.SeekStart)
+	reader.View(reader.Local(originalBase))
+	return view, nil
+}
+
+func (schema *ScalarsSchema) SingleWrite(writer *goschema.SchemaWriter, value *fixtures.Scalars, context int) {
+	originalBase := writer.Base()
+	schema.NakedWrite(writer, value, context)
+	writer.View(writer.Local(originalBase))
+}
+
+func (schema *ScalarsSchema) NakedWrite(writer *goschema.SchemaWriter, value *fixtures.Scalars, context int) {
+	schema.nakedWrite(writer, value, context)
+}
+
+// SingleWritePrepared is like SingleWrite, but does not run the
+// BeforeSchemaWrite hooks. It writes a value that RunBeforeWriteHooks has
+// prepared, e.g. after checking its EncodedSize.
+func (schema *ScalarsSchema) SingleWritePrepared(writer *goschema.SchemaWriter, value *fixtures.Scalars, context int) {
+	originalBase := writer.Base()
+	schema.nakedWrite(writer, value, context)
+	writer.View(writer.Local(originalBase))
+}
+
+// RunBeforeWriteHooks runs the BeforeSchemaWrite hooks of the value and of all
+// objects that it contains, like SingleWrite does before writing anything.
+func (schema *ScalarsSchema) RunBeforeWriteHooks(value *fixtures.Scalars, context int) {
+}
+
+// nakedWrite writes an object whose hooks have run already. It is called for
+// nested objects.
+func (schema *ScalarsSchema) nakedWrite(writer *goschema.SchemaWriter, value *fixtures.Scalars, context int) {
+	if writer.Err() != nil {
+		return
+	}
+	if writer.IsForward() {
+		schema.forwardNakedWrite(writer, value, context)
+		return
+	}
+	startOffset := writer.BeginObject()
+	writer.Seek(87, io.SeekCurrent)
+	schema.WriteFlag(writer, value.Flag, context)
+	schema.WriteInt(writer, value.Int, context)
+	schema.WriteInt8(writer, value.Int8, context)
+	schema.WriteShort(writer, value.Int16, context)
+	schema.WriteInt32(writer, value.Int32, context)
+	schema.WriteInt64(writer, value.Int64, context)
+	schema.WriteUInt(writer, value.UInt, context)
+	schema.WriteUInt8(writer, value.UInt8, context)
+	schema.WriteUInt16(writer, value.UInt16, context)
+	schema.WriteUnsigned(writer, value.UInt32, context)
+	schema.WriteUInt64(writer, value.UInt64, context)
+	schema.WriteFloat32(writer, value.Float32, context)
+	schema.WriteFloat64(writer, value.Float64, context)
+	schema.WriteText(writer, value.Text, context)
+	schema.WritePosition(writer, value.Position, context)
+	schema.WritePath(writer, value.Path, context)
+	schema.WriteOrigin(writer, value.Origin, context)
+	schema.WriteCounts(writer, value.Counts, context)
+	schema.WriteLookup(writer, value.Lookup, context)
+	writer.EndObject(startOffset)
+}
+
+// forwardNakedWrite produces the same data as NakedWrite without seeking by
+// computing all references up front.
+func (schema *ScalarsSchema) forwardNakedWrite(writer *goschema.SchemaWriter, value *fixtures.Scalars, context int) {
+	overhead := writer.ObjectOverhead()
+	startOffset := writer.BeginSizedObject(sizeScalars(value, overhead) - overhead)
+	reference := 87
+	schema.forwardWriteFlag(writer, value.Flag, context)
+	schema.forwardWriteInt(writer, value.Int, context)
+	schema.forwardWriteInt8(writer, value.Int8, context)
+	schema.forwardWriteShort(writer, value.Int16, context)
+	schema.forwardWriteInt32(writer, value.Int32, context)
+	schema.forwardWriteInt64(writer, value.Int64, context)
+	schema.forwardWriteUInt(writer, value.UInt, context)
+	schema.forwardWriteUInt8(writer, value.UInt8, context)
+	schema.forwardWriteUInt16(writer, value.UInt16, context)
+	schema.forwardWriteUnsigned(writer, value.UInt32, context)
+	schema.forwardWriteUInt64(writer, value.UInt64, context)
+	schema.forwardWriteFloat32(writer, value.Float32, context)
+	schema.forwardWriteFloat64(writer, value.Float64, context)
+	writer.WriteUInt32(uint32(reference))
+	reference += 4 + len(value.Text)
+
+	schema.forwardWritePosition(writer, value.Position, context)
+	writer.WriteUInt32(uint32(reference))
+	reference += 1 + 4
+	reference += len(value.Path) * 8
+
+	writer.WriteUInt32(uint32(reference))
+	reference += 1 + 1
+	if value.Origin != nil {
+		reference += 8
+	}
+
+	writer.WriteUInt32(uint32(reference))
+	reference += 1 + 1
+	if value.Counts != nil {
+		reference += 1 + 4
+		reference += len(*value.Counts) * 4
+	}
+
+	writer.WriteUInt32(uint32(reference))
+	reference += 1 + 1
+	if value.Lookup != nil {
+		reference += 1 + 1 + 4
+		reference += len(*value.Lookup) * 2
+		for v23Key := range *value.Lookup {
+			reference += 4 + len(v23Key)
+		}
+	}
+
+	schema.forwardWriteText(writer, value.Text, context)
+	schema.forwardWritePath(writer, value.Path, context)
+	schema.forwardWriteOrigin(writer, value.Origin, context)
+	schema.forwardWriteCounts(writer, value.Counts, context)
+	schema.forwardWriteLookup(writer, value.Lookup, context)
+	writer.EndObject(startOffset)
+}
+
+// EncodedSize returns the number of bytes that SingleWritePrepared writes for
+// the given value, assuming that checksums are disabled. It does not include
+// the index of the schema written by WriteScalarsSchema, and it does
+// not run any hooks.
+func (schema *ScalarsSchema) EncodedSize(value *fixtures.Scalars) int {
+	return sizeScalars(value, 4)
+}
+
+// sizeScalars returns the number of bytes written by NakedWrite for
+// the given value if each object takes up overhead bytes besides its data.
+func sizeScalars(value *fixtures.Scalars, overhead int) int {
+	size := overhead + 87
+	size += 4 + len(value.Text)
+
+	size += 1 + 4
+	size += len(value.Path) * 8
+
+	size += 1 + 1
+	if value.Origin != nil {
+		size += 8
+	}
+
+	size += 1 + 1
+	if value.Counts != nil {
+		size += 1 + 4
+		size += len(*value.Counts) * 4
+	}
+
+	size += 1 + 1
+	if value.Lookup != nil {
+		size += 1 + 1 + 4
+		size += len(*value.Lookup) * 2
+		for v22Key := range *value.Lookup {
+			size += 4 + len(v22Key)
+		}
+	}
+
+	return size
+}
+
+func (schema *ScalarsSchema) WriteFlag(writer *goschema.SchemaWriter, value bool, context int) {
+	offset := writer.Offset()
+	writer.Seek(int64(schema.FlagOffset), io.SeekStart)
+	writer.WriteBool(bool(value))
+	writer.Seek(offset, io.SeekStart)
+}
+
+func (schema *ScalarsSchema) forwardWriteFlag(writer *goschema.SchemaWriter, value bool, context int) {
+	writer.WriteBool(bool(value))
+}
+
+func (schema *ScalarsSchema) ReadFlagInto(reader *goschema.SchemaReader, value *bool, context int) error {
+	if schema.FlagOffset == -1 {
+		var tmp bool
+		*value = tmp
+		return nil
+	}
+	offset := reader.Offset()
+	reader.Seek(int64(schema.FlagOffset), io.SeekStart)
+	*value = bool(reader.ReadBool())
+	reader.Seek(offset, io.SeekStart)
+	return reader.Err()
+}
+
+func (view ScalarsView) GetFlag(context int) (bool, error) {
+	var value bool
+	reader := view.reader
+	originalBase := reader.Base()
+	offset := reader.GlobalOffset()
+	reader.View(reader.Local(view.base))
+	err := view.schema.ReadFlagInto(reader, &value, context)
+	reader.View(reader.Local(originalBase))
+	reader.Seek(reader.Local(offset), io.SeekStart)
+	return value, err
+}
+
+func (schema *ScalarsSchema) GetFlag(reader *goschema.SchemaReader, context int) (bool, error) {
+	offset := reader.Offset()
+	view, err := schema.NakedView(reader)
+	reader.Seek(offset, io.SeekStart)
+	if err != nil {
+		var value bool
+		return value, err
+	}
+	return view.GetFlag(context)
+}
+
+func (schema *ScalarsSchema) WriteInt(writer *goschema.SchemaWriter, value int, context int) {
+	offset := writer.Offset()
+	writer.Seek(int64(schema.IntOffset), io.SeekStart)
+	writer.WriteInt(int(value))
+	writer.Seek(offset, io.SeekStart)
+}
+
+func (schema *ScalarsSchema) forwardWriteInt(writer *goschema.SchemaWriter, value int, context int) {
+	writer.WriteInt(int(value))
+}
+
+func (schema *ScalarsSchema) ReadIntInto(reader *goschema.SchemaReader, value *int, context int) error {
+	if schema.IntOffset == -1 {
+		var tmp int
+		*value = tmp
+		return nil
+	}
+	offset := reader.Offset()
+	reader.Seek(int64(schema.IntOffset), io.SeekStart)
+	if schema.IntType != goschema.TypeCode(12) {
+		*value = int(reader.ReadWidenedInt(schema.IntType))
+		reader.Seek(offset, io.SeekStart)
+		return reader.Err()
+	}
+	*value = int(reader.ReadInt())
+	reader.Seek(offset, io.SeekStart)
+	return reader.Err()
+}
+
+func (view ScalarsView) GetInt(context int) (int, error) {
+	var value int
+	reader := view.reader
+	originalBase := reader.Base()
+	offset := reader.GlobalOffset()
+	reader.View(reader.Local(view.base))
+	err := view.schema.ReadIntInto(reader, &value, context)
+	reader.View(reader.Local(originalBase))
+	reader.Seek(reader.Local(offset), io.SeekStart)
+	return value, err
+}
+
+func (schema *ScalarsSchema) GetInt(reader *goschema.SchemaReader, context int) (int, error) {
+	offset := reader.Offset()
+	view, err := schema.NakedView(reader)
+	reader.Seek(offset, io.SeekStart)
+	if err != nil {
+		var value int
+		return value, err
+	}
+	return view.GetInt(context)
+}
+
+func (schema *ScalarsSchema) WriteInt8(writer *goschema.SchemaWriter, value int8, context int) {
+	offset := writer.Offset()
+	writer.Seek(int64(schema.Int8Offset), io.SeekStart)
+	writer.WriteInt8(int8(value))
+	writer.Seek(offset, io.SeekStart)
+}
+
+func (schema *ScalarsSchema) forwardWriteInt8(writer *goschema.SchemaWriter, value int8, context int) {
+	writer.WriteInt8(int8(value))
+}
+
+func (schema *ScalarsSchema) ReadInt8Into(reader *goschema.SchemaReader, value *int8, context int) error {
+	if schema.Int8Offset == -1 {
+		var tmp int8
+		*value = tmp
+		return nil
+	}
+	offset := reader.Offset()
+	reader.Seek(int64(schema.Int8Offset), io.SeekStart)
+	*value = int8(reader.ReadInt8())
+	reader.Seek(offset, io.SeekStart)
+	return reader.Err()
+}
+
+func (view ScalarsView) GetInt8(context int) (int8, error) {
+	var value int8
+	reader := view.reader
+	originalBase := reader.Base()
+	offset := reader.GlobalOffset()
+	reader.View(reader.Local(view.base))
+	err := view.schema.ReadInt8Into(reader, &value, context)
+	reader.View(reader.Local(originalBase))
+	reader.Seek(reader.Local(offset), io.SeekStart)
+	return value, err
+}
+
+func (schema *ScalarsSchema) GetInt8(reader *goschema.SchemaReader, context int) (int8, error) {
+	offset := reader.Offset()
+	view, err := schema.NakedView(reader)
+	reader.Seek(offset, io.SeekStart)
+	if err != nil {
+		var value int8
+		return value, err
+	}
+	return view.GetInt8(context)
+}
+
+func (schema *ScalarsSchema) WriteShort(writer *goschema.SchemaWriter, value int16, context int) {
+	offset := writer.Offset()
+	writer.Seek(int64(schema.ShortOffset), io.SeekStart)
+	writer.WriteInt16(int16(value))
+	writer.Seek(offset, io.SeekStart)
+}
+
+func (schema *ScalarsSchema) forwardWriteShort(writer *goschema.SchemaWriter, value int16, context int) {
+	writer.WriteInt16(int16(value))
+}
+
+func (schema *ScalarsSchema) ReadShortInto(reader *goschema.SchemaReader, value *int16, context int) error {
+	if schema.ShortOffset == -1 {
+		var tmp int16
+		*value = tmp
+		return nil
+	}
+	offset := reader.Offset()
+	reader.Seek(int64(schema.ShortOffset), io.SeekStart)
+	if schema.ShortType != goschema.TypeCode(9) {
+		*value = int16(reader.ReadWidenedInt(schema.ShortType))
+		reader.Seek(offset, io.SeekStart)
+		return reader.Err()
+	}
+	*value = int16(reader.ReadInt16())
+	reader.Seek(offset, io.SeekStart)
+	return reader.Err()
+}
+
+func (view ScalarsView) GetShort(context int) (int16, error) {
+	var value int16
+	reader := view.reader
+	originalBase := reader.Base()
+	offset := reader.GlobalOffset()
+	reader.View(reader.Local(view.base))
+	err := view.schema.ReadShortInto(reader, &value, context)
+	reader.View(reader.Local(originalBase))
+	reader.Seek(reader.Local(offset), io.SeekStart)
+	return value, err
+}
+
+func (schema *ScalarsSchema) GetShort(reader *goschema.SchemaReader, context int) (int16, error) {
+	offset := reader.Offset()
+	view, err := schema.NakedView(reader)
+	reader.Seek(offset, io.SeekStart)
+	if err != nil {
+		var value int16
+		return value, err
+	}
+	return view.GetShort(context)
+}
+
+func (schema *ScalarsSchema) WriteInt32(writer *goschema.SchemaWriter, value int32, context int) {
+	offset := writer.Offset()
+	writer.Seek(int64(schema.Int32Offset), io.SeekStart)
+	writer.WriteInt32(int32(value))
+	writer.Seek(offset, io.SeekStart)
+}
+
+func (schema *ScalarsSchema) forwardWriteInt32(writer *goschema.SchemaWriter, value int32, context int) {
+	writer.WriteInt32(int32(value))
+}
+
+func (schema *ScalarsSchema) ReadInt32Into(reader *goschema.SchemaReader, value *int32, context int) error {
+	if schema.Int32Offset == -1 {
+		var tmp int32
+		*value = tmp
+		return nil
+	}
+	offset := reader.Offset()
+	reader.Seek(int64(schema.Int32Offset), io.SeekStart)
+	if schema.Int32Type != goschema.TypeCode(10) {
+		*value = int32(reader.ReadWidenedInt(schema.Int32Type))
+		reader.Seek(offset, io.SeekStart)
+		return reader.Err()
+	}
+	*value = int32(reader.ReadInt32())
+	reader.Seek(offset, io.SeekStart)
+	return reader.Err()
+}
+
+func (view ScalarsView) GetInt32(context int) (int32, error) {
+	var value int32
+	reader := view.reader
+	originalBase := reader.Base()
+	offset := reader.GlobalOffset()
+	reader.View(reader.Local(view.base))
+	err := view.schema.ReadInt32Into(reader, &value, context)
+	reader.View(reader.Local(originalBase))
+	reader.Seek(reader.Local(offset), io.SeekStart)
+	return value, err
+}
+
+func (schema *ScalarsSchema) GetInt32(reader *goschema.SchemaReader, context int) (int32, error) {
+	offset := reader.Offset()
+	view, err := schema.NakedView(reader)
+	reader.Seek(offset, io.SeekStart)
+	if err != nil {
+		var value int32
+		return value, err
+	}
+	return view.GetInt32(context)
+}
+
+func (schema *ScalarsSchema) WriteInt64(writer *goschema.SchemaWriter, value int64, context int) {
+	offset := writer.Offset()
+	writer.Seek(int64(schema.Int64Offset), io.SeekStart)
+	writer.WriteInt64(int64(value))
+	writer.Seek(offset, io.SeekStart)
+}
+
+func (schema *ScalarsSchema) forwardWriteInt64(writer *goschema.SchemaWriter, value int64, context int) {
+	writer.WriteInt64(int64(value))
+}
+
+func (schema *ScalarsSchema) ReadInt64Into(reader *goschema.SchemaReader, value *int64, context int) error {
+	if schema.Int64Offset == -1 {
+		var tmp int64
+		*value = tmp
+		return nil
+	}
+	offset := reader.Offset()
+	reader.Seek(int64(schema.Int64Offset), io.SeekStart)
+	if schema.Int64Type != goschema.TypeCode(11) {
+		*value = int64(reader.ReadWidenedInt(schema.Int64Type))
+		reader.Seek(offset, io.SeekStart)
+		return reader.Err()
+	}
+	*value = int64(reader.ReadInt64())
+	reader.Seek(offset, io.SeekStart)
+	return reader.Err()
+}
+
+func (view ScalarsView) GetInt64(context int) (int64, error) {
+	var value int64
+	reader := view.reader
+	originalBase := reader.Base()
+	offset := reader.GlobalOffset()
+	reader.View(reader.Local(view.base))
+	err := view.schema.ReadInt64Into(reader, &value, context)
+	reader.View(reader.Local(originalBase))
+	reader.Seek(reader.Local(offset), io.SeekStart)
+	return value, err
+}
+
+func (schema *ScalarsSchema) GetInt64(reader *goschema.SchemaReader, context int) (int64, error) {
+	offset := reader.Offset()
+	view, err := schema.NakedView(reader)
+	reader.Seek(offset, io.SeekStart)
+	if err != nil {
+		var value int64
+		return value, err
+	}
+	return view.GetInt64(context)
+}
+
+func (schema *ScalarsSchema) WriteUInt(writer *goschema.SchemaWriter, value uint, context int) {
+	offset := writer.Offset()
+	writer.Seek(int64(schema.UIntOffset), io.SeekStart)
+	writer.WriteUInt(uint(value))
+	writer.Seek(offset, io.SeekStart)
+}
+
+func (schema *ScalarsSchema) forwardWriteUInt(writer *goschema.SchemaWriter, value uint, context int) {
+	writer.WriteUInt(uint(value))
+}
+
+func (schema *ScalarsSchema) ReadUIntInto(reader *goschema.SchemaReader, value *uint, context int) error {
+	if schema.UIntOffset == -1 {
+		var tmp uint
+		*value = tmp
+		return nil
+	}
+	offset := reader.Offset()
+	reader.Seek(int64(schema.UIntOffset), io.SeekStart)
+	if schema.UIntType != goschema.TypeCode(7) {
+		*value = uint(reader.ReadWidenedUInt(schema.UIntType))
+		reader.Seek(offset, io.SeekStart)
+		return reader.Err()
+	}
+	*value = uint(reader.ReadUInt())
+	reader.Seek(offset, io.SeekStart)
+	return reader.Err()
+}
+
+func (view ScalarsView) GetUInt(context int) (uint, error) {
+	var value uint
+	reader := view.reader
+	originalBase := reader.Base()
+	offset := reader.GlobalOffset()
+	reader.View(reader.Local(view.base))
+	err := view.schema.ReadUIntInto(reader, &value, context)
+	reader.View(reader.Local(originalBase))
+	reader.Seek(reader.Local(offset), io.SeekStart)
+	return value, err
+}
+
+func (schema *ScalarsSchema) GetUInt(reader *goschema.SchemaReader, context int) (uint, error) {
+	offset := reader.Offset()
+	view, err := schema.NakedView(reader)
+	reader.Seek(offset, io.SeekStart)
+	if err != nil {
+		var value uint
+		return value, err
+	}
+	return view.GetUInt(context)
+}
+
+func (schema *ScalarsSchema) WriteUInt8(writer *goschema.SchemaWriter, value uint8, context int) {
+	offset := writer.Offset()
+	writer.Seek(int64(schema.UInt8Offset), io.SeekStart)
+	writer.WriteUInt8(uint8(value))
+	writer.Seek(offset, io.SeekStart)
+}
+
+func (schema *ScalarsSchema) forwardWriteUInt8(writer *goschema.SchemaWriter, value uint8, context int) {
+	writer.WriteUInt8(uint8(value))
+}
+
+func (schema *ScalarsSchema) ReadUInt8Into(reader *goschema.SchemaReader, value *uint8, context int) error {
+	if schema.UInt8Offset == -1 {
+		var tmp uint8
+		*value = tmp
+		return nil
+	}
+	offset := reader.Offset()
+	reader.Seek(int64(schema.UInt8Offset), io.SeekStart)
+	*value = uint8(reader.ReadUInt8())
+	reader.Seek(offset, io.SeekStart)
+	return reader.Err()
+}
+
+func (view ScalarsView) GetUInt8(context int) (uint8, error) {
+	var value uint8
+	reader := view.reader
+	originalBase := reader.Base()
+	offset := reader.GlobalOffset()
+	reader.View(reader.Local(view.base))
+	err := view.schema.ReadUInt8Into(reader, &value, context)
+	reader.View(reader.Local(originalBase))
+	reader.Seek(reader.Local(offset), io.SeekStart)
+	return value, err
+}
+
+func (schema *ScalarsSchema) GetUInt8(reader *goschema.SchemaReader, context int) (uint8, error) {
+	offset := reader.Offset()
+	view, err := schema.NakedView(reader)
+	reader.Seek(offset, io.SeekStart)
+	if err != nil {
+		var value uint8
+		return value, err
+	}
+	return view.GetUInt8(context)
+}
+
+func (schema *ScalarsSchema) WriteUInt16(writer *goschema.SchemaWriter, value uint16, context int) {
+	offset := writer.Offset()
+	writer.Seek(int64(schema.UInt16Offset), io.SeekStart)
+	writer.WriteUInt16(uint16(value))
+	writer.Seek(offset, io.SeekStart)
+}
+
+func (schema *ScalarsSchema) forwardWriteUInt16(writer *goschema.SchemaWriter, value uint16, context int) {
+	writer.WriteUInt16(uint16(value))
+}
+
+func (schema *ScalarsSchema) ReadUInt16Into(reader *goschema.SchemaReader, value *uint16, context int) error {
+	if schema.UInt16Offset == -1 {
+		var tmp uint16
+		*value = tmp
+		return nil
+	}
+	offset := reader.Offset()
+	reader.Seek(int64(schema.UInt16Offset), io.SeekStart)
+	if schema.UInt16Type != goschema.TypeCode(4) {
+		*value = uint16(reader.ReadWidenedUInt(schema.UInt16Type))
+		reader.Seek(offset, io.SeekStart)
+		return reader.Err()
+	}
+	*value = uint16(reader.ReadUInt16())
+	reader.Seek(offset, io.SeekStart)
+	return reader.Err()
+}
+
+func (view ScalarsView) GetUInt16(context int) (uint16, error) {
+	var value uint16
+	reader := view.reader
+	originalBase := reader.Base()
+	offset := reader.GlobalOffset()
+	reader.View(reader.Local(view.base))
+	err := view.schema.ReadUInt16Into(reader, &value, context)
+	reader.View(reader.Local(originalBase))
+	reader.Seek(reader.Local(offset), io.SeekStart)
+	return value, err
+}
+
+func (schema *ScalarsSchema) GetUInt16(reader *goschema.SchemaReader, context int) (uint16, error) {
+	offset := reader.Offset()
+	view, err := schema.NakedView(reader)
+	reader.Seek(offset, io.SeekStart)
+	if err != nil {
+		var value uint16
+		return value, err
+	}
+	return view.GetUInt16(context)
+}
+
+func (schema *ScalarsSchema) WriteUnsigned(writer *goschema.SchemaWriter, value uint32, context int) {
+	offset := writer.Offset()
+	writer.Seek(int64(schema.UnsignedOffset), io.SeekStart)
+	writer.WriteUInt32(uint32(value))
+	writer.Seek(offset, io.SeekStart)
+}
+
+func (schema *ScalarsSchema) forwardWriteUnsigned(writer *goschema.SchemaWriter, value uint32, context int) {
+	writer.WriteUInt32(uint32(value))
+}
+
+func (schema *ScalarsSchema) ReadUnsignedInto(reader *goschema.SchemaReader, value *uint32, context int) error {
+	if schema.UnsignedOffset == -1 {
+		var tmp uint32
+		*value = tmp
+		return nil
+	}
+	offset := reader.Offset()
+	reader.Seek(int64(schema.UnsignedOffset), io.SeekStart)
+	if schema.UnsignedType != goschema.TypeCode(5) {
+		*value = uint32(reader.ReadWidenedUInt(schema.UnsignedType))
+		reader.Seek(offset, io.SeekStart)
+		return reader.Err()
+	}
+	*value = uint32(reader.ReadUInt32())
+	reader.Seek(offset, io.SeekStart)
+	return reader.Err()
+}
+
+func (view ScalarsView) GetUnsigned(context int) (uint32, error) {
+	var value uint32
+	reader := view.reader
+	originalBase := reader.Base()
+	offset := reader.GlobalOffset()
+	reader.View(reader.Local(view.base))
+	err := view.schema.ReadUnsignedInto(reader, &value, context)
+	reader.View(reader.Local(originalBase))
+	reader.Seek(reader.Local(offset), io.SeekStart)
+	return value, err
+}
+
+func (schema *ScalarsSchema) GetUnsigned(reader *goschema.SchemaReader, context int) (uint32, error) {
+	offset := reader.Offset()
+	view, err := schema.NakedView(reader)
+	reader.Seek(offset, io.SeekStart)
+	if err != nil {
+		var value uint32
+		return value, err
+	}
+	return view.GetUnsigned(context)
+}
+
+func (schema *ScalarsSchema) WriteUInt64(writer *goschema.SchemaWriter, value uint64, context int) {
+	offset := writer.Offset()
+	writer.Seek(int64(schema.UInt64Offset), io.SeekStart)
+	writer.WriteUInt64(uint64(value))
+	writer.Seek(offset, io.SeekStart)
+}
+
+func (schema *ScalarsSchema) forwardWriteUInt64(writer *goschema.SchemaWriter, value uint64, context int) {
+	writer.WriteUInt64(uint64(value))
+}
+
+func (schema *ScalarsSchema) ReadUInt64Into(reader *goschema.SchemaReader, value *uint64, context int) error {
+	if schema.UInt64Offset == -1 {
+		var tmp uint64
+		*value = tmp
+		return nil
+	}
+	offset := reader.Offset()
+	reader.Seek(int64(schema.UInt64Offset), io.SeekStart)
+	if schema.UInt64Type != goschema.TypeCode(6) {
+		*value = uint64(reader.ReadWidenedUInt(schema.UInt64Type))
+		reader.Seek(offset, io.SeekStart)
+		return reader.Err()
+	}
+	*value = uint64(reader.ReadUInt64())
+	reader.Seek(offset, io.SeekStart)
+	return reader.Err()
+}
+
+func (view ScalarsView) GetUInt64(context int) (uint64, error) {
+	var value uint64
+	reader := view.reader
+	originalBase := reader.Base()
+	offset := reader.GlobalOffset()
+	reader.View(reader.Local(view.base))
+	err := view.schema.ReadUInt64Into(reader, &value, context)
+	reader.View(reader.Local(originalBase))
+	reader.Seek(reader.Local(offset), io.SeekStart)
+	return value, err
+}
+
+func (schema *ScalarsSchema) GetUInt64(reader *goschema.SchemaReader, context int) (uint64, error) {
+	offset := reader.Offset()
+	view, err := schema.NakedView(reader)
+	reader.Seek(offset, io.SeekStart)
+	if err != nil {
+		var value uint64
+		return value, err
+	}
+	return view.GetUInt64(context)
+}
+
+func (schema *ScalarsSchema) WriteFloat32(writer *goschema.SchemaWriter, value float32, context int) {
+	offset := writer.Offset()
+	writer.Seek(int64(schema.Float32Offset), io.SeekStart)
+	writer.WriteFloat32(float32(value))
+	writer.Seek(offset, io.SeekStart)
+}
+
+func (schema *ScalarsSchema) forwardWriteFloat32(writer *goschema.SchemaWriter, value float32, context int) {
+	writer.WriteFloat32(float32(value))
+}
+
+func (schema *ScalarsSchema) ReadFloat32Into(reader *goschema.SchemaReader, value *float32, context int) error {
+	if schema.Float32Offset == -1 {
+		var tmp float32
+		*value = tmp
+		return nil
+	}
+	offset := reader.Offset()
+	reader.Seek(int64(schema.Float32Offset), io.SeekStart)
+	if schema.Float32Type != goschema.TypeCode(13) {
+		*value = float32(reader.ReadWidenedFloat(schema.Float32Type))
+		reader.Seek(offset, io.SeekStart)
+		return reader.Err()
+	}
+	*value = float32(reader.ReadFloat32())
+	reader.Seek(offset, io.SeekStart)
+	return reader.Err()
+}
+
+func (view ScalarsView) GetFloat32(context int) (float32, error) {
+	var value float32
+	reader := view.reader
+	originalBase := reader.Base()
+	offset := reader.GlobalOffset()
+	reader.View(reader.Local(view.base))
+	err := view.schema.ReadFloat32Into(reader, &value, context)
+	reader.View(reader.Local(originalBase))
+	reader.Seek(reader.Local(offset), io.SeekStart)
+	return value, err
+}
+
+func (schema *ScalarsSchema) GetFloat32(reader *goschema.SchemaReader, context int) (float32, error) {
+	offset := reader.Offset()
+	view, err := schema.NakedView(reader)
+	reader.Seek(offset, io.SeekStart)
+	if err != nil {
+		var value float32
+		return value, err
+	}
+	return view.GetFloat32(context)
+}
+
+func (schema *ScalarsSchema) WriteFloat64(writer *goschema.SchemaWriter, value float64, context int) {
+	offset := writer.Offset()
+	writer.Seek(int64(schema.Float64Offset), io.SeekStart)
+	writer.WriteFloat64(float64(value))
+	writer.Seek(offset, io.SeekStart)
+}
+
+func (schema *ScalarsSchema) forwardWriteFloat64(writer *goschema.SchemaWriter, value float64, context int) {
+	writer.WriteFloat64(float64(value))
+}
+
+func (schema *ScalarsSchema) ReadFloat64Into(reader *goschema.SchemaReader, value *float64, context int) error {
+	if schema.Float64Offset == -1 {
+		var tmp float64
+		*value = tmp
+		return nil
+	}
+	offset := reader.Offset()
+	reader.Seek(int64(schema.Float64Offset), io.SeekStart)
+	if schema.Float64Type != goschema.TypeCode(14) {
+		*value = float64(reader.ReadWidenedFloat(schema.Float64Type))
+		reader.Seek(offset, io.SeekStart)
+		return reader.Err()
+	}
+	*value = float64(reader.ReadFloat64())
+	reader.Seek(offset, io.SeekStart)
+	return reader.Err()
+}
+
+func (view ScalarsView) GetFloat64(context int) (float64, error) {
+	var value float64
+	reader := view.reader
+	originalBase := reader.Base()
+	offset := reader.GlobalOffset()
+	reader.View(reader.Local(view.base))
+	err := view.schema.ReadFloat64Into(reader, &value, context)
+	reader.View(reader.Local(originalBase))
+	reader.Seek(reader.Local(offset), io.SeekStart)
+	return value, err
+}
+
+func (schema *ScalarsSchema) GetFloat64(reader *goschema.SchemaReader, context int) (float64, error) {
+	offset := reader.Offset()
+	view, err := schema.NakedView(reader)
+	reader.Seek(offset, io.SeekStart)
+	if err != nil {
+		var value float64
+		return value, err
+	}
+	return view.GetFloat64(context)
+}
+
+func (schema *ScalarsSchema) WriteText(writer *goschema.SchemaWriter, value string, context int) {
+	offset := writer.Offset()
+	writer.Seek(int64(schema.TextOffset), io.SeekStart)
+	writer.WriteUInt32(uint32(offset))
+	writer.Seek(offset, io.SeekStart)
+	writer.WriteUInt32(uint32(len(value)))
+	writer.WriteString(value)
+}
+
+func (schema *ScalarsSchema) forwardWriteText(writer *goschema.SchemaWriter, value string, context int) {
+	writer.WriteUInt32(uint32(len(value)))
+	writer.WriteString(value)
+
+}
+
+func (schema *ScalarsSchema) ReadTextInto(reader *goschema.SchemaReader, value *string, context int) error {
+	if schema.TextOffset == -1 {
+		var tmp string
+		*value = tmp
+		return nil
+	}
+	offset := reader.Offset()
+	reader.Seek(int64(schema.TextOffset), io.SeekStart)
+	fieldOffset := reader.ReadUInt32()
+	reader.Seek(int64(fieldOffset), io.SeekStart)
+	v1Length := reader.ReadStringLength()
+	*value = string(reader.ReadString(v1Length))
+
+	reader.Seek(offset, io.SeekStart)
+	return reader.Err()
+}
+
+func (view ScalarsView) GetText(context int) (string, error) {
+	var value string
+	reader := view.reader
+	originalBase := reader.Base()
+	offset := reader.GlobalOffset()
+	reader.View(reader.Local(view.base))
+	err := view.schema.ReadTextInto(reader, &value, context)
+	reader.View(reader.Local(originalBase))
+	reader.Seek(reader.Local(offset), io.SeekStart)
+	return value, err
+}
+
+func (schema *ScalarsSchema) GetText(reader *goschema.SchemaReader, context int) (string, error) {
+	offset := reader.Offset()
+	view, err := schema.NakedView(reader)
+	reader.Seek(offset, io.SeekStart)
+	if err != nil {
+		var value string
+		return value, err
+	}
+	return view.GetText(context)
+}
+
+func (schema *ScalarsSchema) WritePosition(writer *goschema.SchemaWriter, value fixtures.Vector2, context int) {
+	offset := writer.Offset()
+	writer.Seek(int64(schema.PositionOffset), io.SeekStart)
+	writer.WriteFloat32(float32(value.X))
+	writer.WriteFloat32(float32(value.Y))
+
+	writer.Seek(offset, io.SeekStart)
+}
+
+func (schema *ScalarsSchema) forwardWritePosition(writer *goschema.SchemaWriter, value fixtures.Vector2, context int) {
+	writer.WriteFloat32(float32(value.X))
+	writer.WriteFloat32(float32(value.Y))
+
+}
+
+func (schema *ScalarsSchema) ReadPositionInto(reader *goschema.SchemaReader, value *fixtures.Vector2, context int) error {
+	if schema.PositionOffset == -1 {
+		var tmp fixtures.Vector2
+		*value = tmp
+		return nil
+	}
+	offset := reader.Offset()
+	reader.Seek(int64(schema.PositionOffset), io.SeekStart)
+	value.X = float32(reader.ReadFloat32())
+	value.Y = float32(reader.ReadFloat32())
+
+	reader.Seek(offset, io.SeekStart)
+	return reader.Err()
+}
+
+func (view ScalarsView) GetPosition(context int) (fixtures.Vector2, error) {
+	var value fixtures.Vector2
+	reader := view.reader
+	originalBase := reader.Base()
+	offset := reader.GlobalOffset()
+	reader.View(reader.Local(view.base))
+	err := view.schema.ReadPositionInto(reader, &value, context)
+	reader.View(reader.Local(originalBase))
+	reader.Seek(reader.Local(offset), io.SeekStart)
+	return value, err
+}
+
+func (schema *ScalarsSchema) GetPosition(reader *goschema.SchemaReader, context int) (fixtures.Vector2, error) {
+	offset := reader.Offset()
+	view, err := schema.NakedView(reader)
+	reader.Seek(offset, io.SeekStart)
+	if err != nil {
+		var value fixtures.Vector2
+		return value, err
+	}
+	return view.GetPosition(context)
+}
+
+func (schema *ScalarsSchema) WritePath(writer *goschema.SchemaWriter, value []fixtures.Vector2, context int) {
+	offset := writer.Offset()
+	writer.Seek(int64(schema.PathOffset), io.SeekStart)
+	writer.WriteUInt32(uint32(offset))
+	writer.Seek(offset, io.SeekStart)
+	writer.WriteUInt8(uint8(goschema.TypeCode(255)))
+	v2Length := len(value)
+	writer.WriteUInt32(uint32(v2Length))
+	for v2I := 0; v2I < v2Length; v2I++ {
+		writer.WriteFloat32(float32(value[v2I].X))
+		writer.WriteFloat32(float32(value[v2I].Y))
+
+	}
+}
+
+func (schema *ScalarsSchema) forwardWritePath(writer *goschema.SchemaWriter, value []fixtures.Vector2, context int) {
+	writer.WriteUInt8(uint8(goschema.TypeCode(255)))
+	v2Length := len(value)
+	writer.WriteUInt32(uint32(v2Length))
+	for v2I := 0; v2I < v2Length; v2I++ {
+		writer.WriteFloat32(float32(value[v2I].X))
+		writer.WriteFloat32(float32(value[v2I].Y))
+
+	}
+
+}
+
+func (schema *ScalarsSchema) ReadPathInto(reader *goschema.SchemaReader, value *[]fixtures.Vector2, context int) error {
+	if schema.PathOffset == -1 {
+		var tmp []fixtures.Vector2
+		*value = tmp
+		return nil
+	}
+	offset := reader.Offset()
+	reader.Seek(int64(schema.PathOffset), io.SeekStart)
+	fieldOffset := reader.ReadUInt32()
+	reader.Seek(int64(fieldOffset), io.SeekStart)
+	_ = reader.ReadUInt8() // ignore typecode
+	v3Entries := reader.ReadCollectionLength(8)
+	v3Slice := make([]fixtures.Vector2, v3Entries, v3Entries)
+	for v3I := 0; v3I < v3Entries; v3I++ {
+		v3Slice[v3I].X = float32(reader.ReadFloat32())
+		v3Slice[v3I].Y = float32(reader.ReadFloat32())
+
+	}
+	*value = v3Slice
+
+	reader.Seek(offset, io.SeekStart)
+	return reader.Err()
+}
+
+func (view ScalarsView) GetPath(context int) ([]fixtures.Vector2, error) {
+	var value []fixtures.Vector2
+	reader := view.reader
+	originalBase := reader.Base()
+	offset := reader.GlobalOffset()
+	reader.View(reader.Local(view.base))
+	err := view.schema.ReadPathInto(reader, &value, context)
+	reader.View(reader.Local(originalBase))
+	reader.Seek(reader.Local(offset), io.SeekStart)
+	return value, err
+}
+
+func (schema *ScalarsSchema) GetPath(reader *goschema.SchemaReader, context int) ([]fixtures.Vector2, error) {
+	offset := reader.Offset()
+	view, err := schema.NakedView(reader)
+	reader.Seek(offset, io.SeekStart)
+	if err != nil {
+		var value []fixtures.Vector2
+		return value, err
+	}
+	return view.GetPath(context)
+}
+
+func (schema *ScalarsSchema) WriteOrigin(writer *goschema.SchemaWriter, value *fixtures.Vector2, context int) {
+	offset := writer.Offset()
+	writer.Seek(int64(schema.OriginOffset), io.SeekStart)
+	writer.WriteUInt32(uint32(offset))
+	writer.Seek(offset, io.SeekStart)
+	writer.WriteUInt8(uint8(goschema.TypeCode(255)))
+	if value != nil {
+		writer.WriteBool(true)
+		writer.WriteFloat32(float32(value.X))
+		writer.WriteFloat32(float32(value.Y))
+
+	} else {
+		writer.WriteBool(false)
+	}
+}
+
+func (schema *ScalarsSchema) forwardWriteOrigin(writer *goschema.SchemaWriter, value *fixtures.Vector2, context int) {
+	writer.WriteUInt8(uint8(goschema.TypeCode(255)))
+	if value != nil {
+		writer.WriteBool(true)
+		writer.WriteFloat32(float32(value.X))
+		writer.WriteFloat32(float32(value.Y))
+
+	} else {
+		writer.WriteBool(false)
+	}
+
+}
+
+func (schema *ScalarsSchema) ReadOriginInto(reader *goschema.SchemaReader, value **fixtures.Vector2, context int) error {
+	if schema.OriginOffset == -1 {
+		var tmp *fixtures.Vector2
+		*value = tmp
+		return nil
+	}
+	offset := reader.Offset()
+	reader.Seek(int64(schema.OriginOffset), io.SeekStart)
+	fieldOffset := reader.ReadUInt32()
+	reader.Seek(int64(fieldOffset), io.SeekStart)
+	_ = reader.ReadUInt8() // ignore typecode
+	v7NonNil := reader.ReadBool()
+	if v7NonNil {
+		var v7 fixtures.Vector2
+		v7.X = float32(reader.ReadFloat32())
+		v7.Y = float32(reader.ReadFloat32())
+
+		*value = &v7
+	} else {
+		*value = nil
+	}
+
+	reader.Seek(offset, io.SeekStart)
+	return reader.Err()
+}
+
+func (view ScalarsView) GetOrigin(context int) (*fixtures.Vector2, error) {
+	var value *fixtures.Vector2
+	reader := view.reader
+	originalBase := reader.Base()
+	offset := reader.GlobalOffset()
+	reader.View(reader.Local(view.base))
+	err := view.schema.ReadOriginInto(reader, &value, context)
+	reader.View(reader.Local(originalBase))
+	reader.Seek(reader.Local(offset), io.SeekStart)
+	return value, err
+}
+
+func (schema *ScalarsSchema) GetOrigin(reader *goschema.SchemaReader, context int) (*fixtures.Vector2, error) {
+	offset := reader.Offset()
+	view, err := schema.NakedView(reader)
+	reader.Seek(offset, io.SeekStart)
+	if err != nil {
+		var value *fixtures.Vector2
+		return value, err
+	}
+	return view.GetOrigin(context)
+}
+
+func (schema *ScalarsSchema) WriteCounts(writer *goschema.SchemaWriter, value *[]int32, context int) {
+	offset := writer.Offset()
+	writer.Seek(int64(schema.CountsOffset), io.SeekStart)
+	writer.WriteUInt32(uint32(offset))
+	writer.Seek(offset, io.SeekStart)
+	writer.WriteUInt8(uint8(goschema.TypeCode(2)))
+	if value != nil {
+		writer.WriteBool(true)
+		writer.WriteUInt8(uint8(goschema.TypeCode(10)))
+		v9Length := len(*value)
+		writer.WriteUInt32(uint32(v9Length))
+		for v9I := 0; v9I < v9Length; v9I++ {
+			writer.WriteInt32(int32((*value)[v9I]))
+		}
+
+	} else {
+		writer.WriteBool(false)
+	}
+}
+
+func (schema *ScalarsSchema) forwardWriteCounts(writer *goschema.SchemaWriter, value *[]int32, context int) {
+	writer.WriteUInt8(uint8(goschema.TypeCode(2)))
+	if value != nil {
+		writer.WriteBool(true)
+		writer.WriteUInt8(uint8(goschema.TypeCode(10)))
+		v9Length := len(*value)
+		writer.WriteUInt32(uint32(v9Length))
+		for v9I := 0; v9I < v9Length; v9I++ {
+			writer.WriteInt32(int32((*value)[v9I]))
+		}
+
+	} else {
+		writer.WriteBool(false)
+	}
+
+}
+
+func (schema *ScalarsSchema) ReadCountsInto(reader *goschema.SchemaReader, value **[]int32, context int) error {
+	if schema.CountsOffset == -1 {
+		var tmp *[]int32
+		*value = tmp
+		return nil
+	}
+	offset := reader.Offset()
+	reader.Seek(int64(schema.CountsOffset), io.SeekStart)
+	fieldOffset := reader.ReadUInt32()
+	reader.Seek(int64(fieldOffset), io.SeekStart)
+	_ = reader.ReadUInt8() // ignore typecode
+	v10NonNil := reader.ReadBool()
+	if v10NonNil {
+		var v10 []int32
+		_ = reader.ReadUInt8() // ignore typecode
+		v11Entries := reader.ReadCollectionLength(4)
+		v11Slice := make([]int32, v11Entries, v11Entries)
+		for v11I := 0; v11I < v11Entries; v11I++ {
+			v11Slice[v11I] = int32(reader.ReadInt32())
+		}
+		v10 = v11Slice
+
+		*value = &v10
+	} else {
+		*value = nil
+	}
+
+	reader.Seek(offset, io.SeekStart)
+	return reader.Err()
+}
+
+func (view ScalarsView) GetCounts(context int) (*[]int32, error) {
+	var value *[]int32
+	reader := view.reader
+	originalBase := reader.Base()
+	offset := reader.GlobalOffset()
+	reader.View(reader.Local(view.base))
+	err := view.schema.ReadCountsInto(reader, &value, context)
+	reader.View(reader.Local(originalBase))
+	reader.Seek(reader.Local(offset), io.SeekStart)
+	return value, err
+}
+
+func (schema *ScalarsSchema) GetCounts(reader *goschema.SchemaReader, context int) (*[]int32, error) {
+	offset := reader.Offset()
+	view, err := schema.NakedView(reader)
+	reader.Seek(offset, io.SeekStart)
+	if err != nil {
+		var value *[]int32
+		return value, err
+	}
+	return view.GetCounts(context)
+}
+
+func (schema *ScalarsSchema) WriteLookup(writer *goschema.SchemaWriter, value *map[string]int16, context int) {
+	offset := writer.Offset()
+	writer.Seek(int64(schema.LookupOffset), io.SeekStart)
+	writer.WriteUInt32(uint32(offset))
+	writer.Seek(offset, io.SeekStart)
+	writer.WriteUInt8(uint8(goschema.TypeCode(1)))
+	if value != nil {
+		writer.WriteBool(true)
+		writer.WriteUInt8(uint8(goschema.TypeCode(16)))
+		writer.WriteUInt8(uint8(goschema.TypeCode(9)))
+		writer.WriteUInt32(uint32(len(*value)))
+		for v15Key, v15Value := range *value {
+			writer.WriteUInt32(uint32(len(v15Key)))
+			writer.WriteString(v15Key)
+
+			writer.WriteInt16(int16(v15Value))
+		}
+
+	} else {
+		writer.WriteBool(false)
+	}
+}
+
+func (schema *ScalarsSchema) forwardWriteLookup(writer *goschema.SchemaWriter, value *map[string]int16, context int) {
+	writer.WriteUInt8(uint8(goschema.TypeCode(1)))
+	if value != nil {
+		writer.WriteBool(true)
+		writer.WriteUInt8(uint8(goschema.TypeCode(16)))
+		writer.WriteUInt8(uint8(goschema.TypeCode(9)))
+		writer.WriteUInt32(uint32(len(*value)))
+		for v15Key, v15Value := range *value {
+			writer.WriteUInt32(uint32(len(v15Key)))
+			writer.WriteString(v15Key)
+
+			writer.WriteInt16(int16(v15Value))
+		}
+
+	} else {
+		writer.WriteBool(false)
+	}
+
+}
+
+func (schema *ScalarsSchema) ReadLookupInto(reader *goschema.SchemaReader, value **map[string]int16, context int) error {
+	if schema.LookupOffset == -1 {
+		var tmp *map[string]int16
+		*value = tmp
+		return nil
+	}
+	offset := reader.Offset()
+	reader.Seek(int64(schema.LookupOffset), io.SeekStart)
+	fieldOffset := reader.ReadUInt32()
+	reader.Seek(int64(fieldOffset), io.SeekStart)
+	_ = reader.ReadUInt8() // ignore typecode
+	v19NonNil := reader.ReadBool()
+	if v19NonNil {
+		var v19 map[string]int16
+		_ = reader.ReadUInt8() // ignore typecode
+		_ = reader.ReadUInt8() // ignore typecode
+		v20Entries := reader.ReadCollectionLength(18)
+		var v20Key string
+		var v20Value int16
+		v20Map := make(map[string]int16)
+		for v20I := 0; v20I < v20Entries; v20I++ {
+			v21Length := reader.ReadStringLength()
+			v20Key = string(reader.ReadString(v21Length))
+
+			v20Value = int16(reader.ReadInt16())
+			v20Map[v20Key] = v20Value
+		}
+		v19 = v20Map
+
+		*value = &v19
+	} else {
+		*value = nil
+	}
+
+	reader.Seek(offset, io.SeekStart)
+	return reader.Err()
+}
+
+func (view ScalarsView) GetLookup(context int) (*map[string]int16, error) {
+	var value *map[string]int16
+	reader := view.reader
+	originalBase := reader.Base()
+	offset := reader.GlobalOffset()
+	reader.View(reader.Local(view.base))
+	err := view.schema.ReadLookupInto(reader, &value, context)
+	reader.View(reader.Local(originalBase))
+	reader.Seek(reader.Local(offset), io.SeekStart)
+	return value, err
+}
+
+func (schema *ScalarsSchema) GetLookup(reader *goschema.SchemaReader, context int) (*map[string]int16, error) {
+	offset := reader.Offset()
+	view, err := schema.NakedView(reader)
+	reader.Seek(offset, io.SeekStart)
+	if err != nil {
+		var value *map[string]int16
+		return value, err
+	}
+	return view.GetLookup(context)
+}
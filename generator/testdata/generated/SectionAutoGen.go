@@ -0,0 +1,333 @@
+package generated
+
+import (
+	"github.com/chasingcarrots/goschema"
+	"io"
+
+	"github.com/chasingcarrots/goschema/generator/testdata/fixtures"
+)
+
+const SectionAutoGenSchemaID goschema.SchemaID = 8
+const SectionAutoGenSchemaFingerprint uint64 = 0xfa30e5a2f7008f2e
+const SectionAutoGenSchemaName = "SectionAutoGen"
+
+type SectionAutoGenSchema struct {
+	TagsOffset int
+
+	descriptor []goschema.SchemaEntry
+}
+
+func NewSectionAutoGenSchema() *SectionAutoGenSchema {
+	schema := SectionAutoGenSchema{}
+	schema.init()
+	return &schema
+}
+
+func (schema *SectionAutoGenSchema) ID() goschema.SchemaID {
+	return SectionAutoGenSchemaID
+}
+
+func (schema *SectionAutoGenSchema) Fingerprint() uint64 {
+	return SectionAutoGenSchemaFingerprint
+}
+
+func (schema *SectionAutoGenSchema) Name() string {
+	return SectionAutoGenSchemaName
+}
+
+func (schema *SectionAutoGenSchema) GoType() string {
+	return "github.com/chasingcarrots/goschema/generator/testdata/fixtures.Section"
+}
+
+func (schema *SectionAutoGenSchema) Fill(entries []goschema.SchemaEntry) {
+	schema.TagsOffset = -1
+
+	for i := range entries {
+		switch entries[i].Name {
+		case "Tags":
+			// the current name of a field takes precedence over its aliases
+			if schema.descriptor[0].CanRead(&entries[i]) {
+				schema.TagsOffset = int(entries[i].Offset)
+			}
+		}
+	}
+}
+
+func (schema *SectionAutoGenSchema) init() {
+	if schema.descriptor == nil {
+		schema.descriptor = make([]goschema.SchemaEntry, 0, 1)
+		schema.descriptor = append(schema.descriptor,
+			goschema.SchemaEntry{
+				Name:       "Tags",
+				Type:       goschema.TypeCode(2),
+				Offset:     0,
+				Descriptor: &goschema.TypeDescriptor{Code: goschema.TypeCode(2), Elem: &goschema.TypeDescriptor{Code: goschema.TypeCode(0), Schema: "TagAutoGen"}},
+			},
+		)
+		schema.TagsOffset = 0
+	}
+}
+
+// DescribeDeprecated returns the entries of the fields that are read, but no
+// longer written.
+func (schema *SectionAutoGenSchema) DescribeDeprecated() []goschema.SchemaEntry {
+	return nil
+}
+
+func (schema *SectionAutoGenSchema) Describe() []goschema.SchemaEntry {
+	return schema.descriptor
+}
+
+func ReadSectionAutoGenSchema(reader *goschema.SchemaReader) *SectionAutoGenSchema {
+	schemaIdx := int(reader.ReadUInt32())
+	existingSchema, schemaEntries := reader.FindSchema(int(schemaIdx))
+	schema, ok := existingSchema.(*SectionAutoGenSchema)
+	if existingSchema == nil || !ok {
+		schema = NewSectionAutoGenSchema()
+		reader.VerifySchemaName(schemaIdx, SectionAutoGenSchemaName)
+		// the offsets of a new schema already match data with the same fingerprint
+		if fingerprint, ok := reader.SchemaFingerprint(schemaIdx); !ok || fingerprint != SectionAutoGenSchemaFingerprint {
+			schema.Fill(schemaEntries)
+		}
+		reader.RegisterSchema(schemaIdx, schema)
+	}
+	return schema
+}
+
+func WriteSectionAutoGenSchema(writer *goschema.SchemaWriter) *SectionAutoGenSchema {
+	schemaEntry, ok := writer.FindSchema(goschema.SchemaID(SectionAutoGenSchemaID))
+	schemaIdx := schemaEntry.Index()
+	schema, ok := schemaEntry.Schema().(*SectionAutoGenSchema)
+	if !ok {
+		schema = NewSectionAutoGenSchema()
+		schemaIdx = writer.RegisterSchema(schema)
+	}
+	writer.WriteUInt32(uint32(schemaIdx))
+	return schema
+}
+
+func (schema *SectionAutoGenSchema) SingleRead(reader *goschema.SchemaReader, value *fixtures.Section, context int) error {
+	originalBase := reader.Base()
+	err := schema.NakedRead(reader, value, context)
+	reader.View(reader.Local(originalBase))
+	return err
+}
+
+func (schema *SectionAutoGenSchema) NakedRead(reader *goschema.SchemaReader, value *fixtures.Section, context int) error {
+	nextOffset, err := reader.BeginObject("SectionAutoGen")
+	if err != nil {
+		return err
+	}
+	defer reader.EndObject()
+	if err := schema.ReadTagsInto(reader, &value.Tags, context); err != nil {
+		return goschema.PrefixFieldPath(err, "Tags")
+	}
+	reader.Seek(reader.Local(nextOffset), io.SeekStart)
+	return reader.Err()
+}
+
+// SectionAutoGenView gives access to single fields of an object without
+// reading the whole object.
+type SectionAutoGenView struct {
+	schema *SectionAutoGenSchema
+	reader *goschema.SchemaReader
+	base   int64 // global offset of the object's data
+}
+
+// NakedView creates a view of the object at the current position of the reader
+// and moves the reader past the object, like NakedRead would. The fields of the
+// object are only read when they are requested from the view. If the object
+// carries a checksum, NakedView verifies it and thus reads the whole object.
+func (schema *SectionAutoGenSchema) NakedView(reader *goschema.SchemaReader) (SectionAutoGenView, error) {
+	originalBase := reader.Base()
+	nextOffset, err := reader.BeginObject("SectionAutoGen")
+	if err != nil {
+		reader.View(reader.Local(originalBase))
+		return SectionAutoGenView{}, err
+	}
+	reader.EndObject()
+	view := SectionAutoGenView{
+		schema: schema,
+		reader: reader,
+		base:   reader.Base(),
+	}
+	reader.Seek(reader.Local(nextOffset), io.SeekStart)
+	reader.View(reader.Local(originalBase))
+	return view, nil
+}
+
+func (schema *SectionAutoGenSchema) SingleWrite(writer *goschema.SchemaWriter, value *fixtures.Section, context int) {
+	originalBase := writer.Base()
+	schema.NakedWrite(writer, value, context)
+	writer.View(writer.Local(originalBase))
+}
+
+func (schema *SectionAutoGenSchema) NakedWrite(writer *goschema.SchemaWriter, value *fixtures.Section, context int) {
+	if writer.Err() == nil {
+		// the hooks of all nested objects run first, since forward writers
+		// compute sizes up front
+		beforeWriteSectionAutoGen(value, context)
+	}
+	schema.nakedWrite(writer, value, context)
+}
+
+// SingleWritePrepared is like SingleWrite, but does not run the
+// BeforeSchemaWrite hooks. It writes a value that RunBeforeWriteHooks has
+// prepared, e.g. after checking its EncodedSize.
+func (schema *SectionAutoGenSchema) SingleWritePrepared(writer *goschema.SchemaWriter, value *fixtures.Section, context int) {
+	originalBase := writer.Base()
+	schema.nakedWrite(writer, value, context)
+	writer.View(writer.Local(originalBase))
+}
+
+// RunBeforeWriteHooks runs the BeforeSchemaWrite hooks of the value and of all
+// objects that it contains, like SingleWrite does before writing anything.
+func (schema *SectionAutoGenSchema) RunBeforeWriteHooks(value *fixtures.Section, context int) {
+	beforeWriteSectionAutoGen(value, context)
+}
+
+// nakedWrite writes an object whose hooks have run already. It is called for
+// nested objects.
+func (schema *SectionAutoGenSchema) nakedWrite(writer *goschema.SchemaWriter, value *fixtures.Section, context int) {
+	if writer.Err() != nil {
+		return
+	}
+	if writer.IsForward() {
+		schema.forwardNakedWrite(writer, value, context)
+		return
+	}
+	startOffset := writer.BeginObject()
+	writer.Seek(4, io.SeekCurrent)
+	schema.WriteTags(writer, value.Tags, context)
+	if writer.FailedIn("Tags") {
+		return
+	}
+	writer.EndObject(startOffset)
+}
+
+// forwardNakedWrite produces the same data as NakedWrite without seeking by
+// computing all references up front.
+func (schema *SectionAutoGenSchema) forwardNakedWrite(writer *goschema.SchemaWriter, value *fixtures.Section, context int) {
+	overhead := writer.ObjectOverhead()
+	startOffset := writer.BeginSizedObject(sizeSectionAutoGen(value, overhead) - overhead)
+	reference := 4
+	writer.WriteUInt32(uint32(reference))
+	reference += 1 + 4 + 4
+	for v3I := range value.Tags {
+		reference += sizeTagAutoGen(&value.Tags[v3I], overhead)
+	}
+
+	schema.forwardWriteTags(writer, value.Tags, context)
+	if writer.FailedIn("Tags") {
+		return
+	}
+	writer.EndObject(startOffset)
+}
+
+// EncodedSize returns the number of bytes that SingleWritePrepared writes for
+// the given value, assuming that checksums are disabled. It does not include
+// the index of the schema written by WriteSectionAutoGenSchema, and it does
+// not run any hooks.
+func (schema *SectionAutoGenSchema) EncodedSize(value *fixtures.Section) int {
+	return sizeSectionAutoGen(value, 4)
+}
+
+// beforeWriteSectionAutoGen runs the BeforeSchemaWrite hooks of the value and
+// of all objects that it contains.
+func beforeWriteSectionAutoGen(value *fixtures.Section, context int) {
+	for v4I := range value.Tags {
+		beforeWriteTagAutoGen(&value.Tags[v4I], context)
+	}
+
+}
+
+// sizeSectionAutoGen returns the number of bytes written by NakedWrite for
+// the given value if each object takes up overhead bytes besides its data.
+func sizeSectionAutoGen(value *fixtures.Section, overhead int) int {
+	size := overhead + 4
+	size += 1 + 4 + 4
+	for v2I := range value.Tags {
+		size += sizeTagAutoGen(&value.Tags[v2I], overhead)
+	}
+
+	return size
+}
+
+func (schema *SectionAutoGenSchema) WriteTags(writer *goschema.SchemaWriter, value []fixtures.Tag, context int) {
+	offset := writer.Offset()
+	writer.Seek(int64(schema.TagsOffset), io.SeekStart)
+	writer.WriteUInt32(uint32(offset))
+	writer.Seek(offset, io.SeekStart)
+	writer.WriteUInt8(uint8(goschema.TypeCode(0)))
+	v0ViewBase := writer.Base()
+	v0Schema := WriteTagAutoGenSchema(writer)
+	v0Length := len(value)
+	writer.WriteUInt32(uint32(v0Length))
+	for v0I := 0; v0I < v0Length; v0I++ {
+		v0Schema.nakedWrite(writer, &value[v0I], context)
+	}
+	writer.View(writer.Local(v0ViewBase))
+}
+
+func (schema *SectionAutoGenSchema) forwardWriteTags(writer *goschema.SchemaWriter, value []fixtures.Tag, context int) {
+	writer.WriteUInt8(uint8(goschema.TypeCode(0)))
+	v0ViewBase := writer.Base()
+	v0Schema := WriteTagAutoGenSchema(writer)
+	v0Length := len(value)
+	writer.WriteUInt32(uint32(v0Length))
+	for v0I := 0; v0I < v0Length; v0I++ {
+		v0Schema.nakedWrite(writer, &value[v0I], context)
+	}
+	writer.View(writer.Local(v0ViewBase))
+
+}
+
+func (schema *SectionAutoGenSchema) ReadTagsInto(reader *goschema.SchemaReader, value *[]fixtures.Tag, context int) error {
+	if schema.TagsOffset == -1 {
+		var tmp []fixtures.Tag
+		*value = tmp
+		return nil
+	}
+	offset := reader.Offset()
+	reader.Seek(int64(schema.TagsOffset), io.SeekStart)
+	fieldOffset := reader.ReadUInt32()
+	reader.Seek(int64(fieldOffset), io.SeekStart)
+	_ = reader.ReadUInt8() // ignore typecode
+	v1Schema := ReadTagAutoGenSchema(reader)
+	v1ViewBase := reader.Base()
+	v1Entries := reader.ReadCollectionLength(16)
+	v1Slice := make([]fixtures.Tag, v1Entries, v1Entries)
+	for v1I := 0; v1I < v1Entries; v1I++ {
+		if err := v1Schema.NakedRead(reader, &v1Slice[v1I], context); err != nil {
+			return err
+		}
+	}
+	*value = v1Slice
+	reader.View(reader.Local(v1ViewBase))
+
+	reader.Seek(offset, io.SeekStart)
+	return reader.Err()
+}
+
+func (view SectionAutoGenView) GetTags(context int) ([]fixtures.Tag, error) {
+	var value []fixtures.Tag
+	reader := view.reader
+	originalBase := reader.Base()
+	offset := reader.GlobalOffset()
+	reader.View(reader.Local(view.base))
+	err := view.schema.ReadTagsInto(reader, &value, context)
+	reader.View(reader.Local(originalBase))
+	reader.Seek(reader.Local(offset), io.SeekStart)
+	return value, err
+}
+
+func (schema *SectionAutoGenSchema) GetTags(reader *goschema.SchemaReader, context int) ([]fixtures.Tag, error) {
+	offset := reader.Offset()
+	view, err := schema.NakedView(reader)
+	reader.Seek(offset, io.SeekStart)
+	if err != nil {
+		var value []fixtures.Tag
+		return value, err
+	}
+	return view.GetTags(context)
+}
@@ -0,0 +1,294 @@
+package generated
+
+import (
+	"github.com/chasingcarrots/goschema"
+	"io"
+
+	"github.com/chasingcarrots/goschema/generator/testdata/fixtures"
+)
+
+const TagAutoGenSchemaID goschema.SchemaID = 7
+const TagAutoGenSchemaFingerprint uint64 = 0x4ca0b769b50886dd
+const TagAutoGenSchemaName = "TagAutoGen"
+
+type TagAutoGenSchema struct {
+	NameOffset int
+
+	descriptor []goschema.SchemaEntry
+}
+
+func NewTagAutoGenSchema() *TagAutoGenSchema {
+	schema := TagAutoGenSchema{}
+	schema.init()
+	return &schema
+}
+
+func (schema *TagAutoGenSchema) ID() goschema.SchemaID {
+	return TagAutoGenSchemaID
+}
+
+func (schema *TagAutoGenSchema) Fingerprint() uint64 {
+	return TagAutoGenSchemaFingerprint
+}
+
+func (schema *TagAutoGenSchema) Name() string {
+	return TagAutoGenSchemaName
+}
+
+func (schema *TagAutoGenSchema) GoType() string {
+	return "github.com/chasingcarrots/goschema/generator/testdata/fixtures.Tag"
+}
+
+func (schema *TagAutoGenSchema) Fill(entries []goschema.SchemaEntry) {
+	schema.NameOffset = -1
+
+	for i := range entries {
+		switch entries[i].Name {
+		case "Name":
+			// the current name of a field takes precedence over its aliases
+			if schema.descriptor[0].CanRead(&entries[i]) {
+				schema.NameOffset = int(entries[i].Offset)
+			}
+		}
+	}
+}
+
+func (schema *TagAutoGenSchema) init() {
+	if schema.descriptor == nil {
+		schema.descriptor = make([]goschema.SchemaEntry, 0, 1)
+		schema.descriptor = append(schema.descriptor,
+			goschema.SchemaEntry{
+				Name:       "Name",
+				Type:       goschema.TypeCode(16),
+				Offset:     0,
+				Descriptor: &goschema.TypeDescriptor{Code: goschema.TypeCode(16)},
+			},
+		)
+		schema.NameOffset = 0
+	}
+}
+
+// DescribeDeprecated returns the entries of the fields that are read, but no
+// longer written.
+func (schema *TagAutoGenSchema) DescribeDeprecated() []goschema.SchemaEntry {
+	return nil
+}
+
+func (schema *TagAutoGenSchema) Describe() []goschema.SchemaEntry {
+	return schema.descriptor
+}
+
+func ReadTagAutoGenSchema(reader *goschema.SchemaReader) *TagAutoGenSchema {
+	schemaIdx := int(reader.ReadUInt32())
+	existingSchema, schemaEntries := reader.FindSchema(int(schemaIdx))
+	schema, ok := existingSchema.(*TagAutoGenSchema)
+	if existingSchema == nil || !ok {
+		schema = NewTagAutoGenSchema()
+		reader.VerifySchemaName(schemaIdx, TagAutoGenSchemaName)
+		// the offsets of a new schema already match data with the same fingerprint
+		if fingerprint, ok := reader.SchemaFingerprint(schemaIdx); !ok || fingerprint != TagAutoGenSchemaFingerprint {
+			schema.Fill(schemaEntries)
+		}
+		reader.RegisterSchema(schemaIdx, schema)
+	}
+	return schema
+}
+
+func WriteTagAutoGenSchema(writer *goschema.SchemaWriter) *TagAutoGenSchema {
+	schemaEntry, ok := writer.FindSchema(goschema.SchemaID(TagAutoGenSchemaID))
+	schemaIdx := schemaEntry.Index()
+	schema, ok := schemaEntry.Schema().(*TagAutoGenSchema)
+	if !ok {
+		schema = NewTagAutoGenSchema()
+		schemaIdx = writer.RegisterSchema(schema)
+	}
+	writer.WriteUInt32(uint32(schemaIdx))
+	return schema
+}
+
+func (schema *TagAutoGenSchema) SingleRead(reader *goschema.SchemaReader, value *fixtures.Tag, context int) error {
+	originalBase := reader.Base()
+	err := schema.NakedRead(reader, value, context)
+	reader.View(reader.Local(originalBase))
+	return err
+}
+
+func (schema *TagAutoGenSchema) NakedRead(reader *goschema.SchemaReader, value *fixtures.Tag, context int) error {
+	nextOffset, err := reader.BeginObject("TagAutoGen")
+	if err != nil {
+		return err
+	}
+	defer reader.EndObject()
+	if err := schema.ReadNameInto(reader, &value.Name, context); err != nil {
+		return err
+	}
+	reader.Seek(reader.Local(nextOffset), io.SeekStart)
+	return reader.Err()
+}
+
+// TagAutoGenView gives access to single fields of an object without
+// reading the whole object.
+type TagAutoGenView struct {
+	schema *TagAutoGenSchema
+	reader *goschema.SchemaReader
+	base   int64 // global offset of the object's data
+}
+
+// NakedView creates a view of the object at the current position of the reader
+// and moves the reader past the object, like NakedRead would. The fields of the
+// object are only read when they are requested from the view. If the object
+// carries a checksum, NakedView verifies it and thus reads the whole object.
+func (schema *TagAutoGenSchema) NakedView(reader *goschema.SchemaReader) (TagAutoGenView, error) {
+	originalBase := reader.Base()
+	nextOffset, err := reader.BeginObject("TagAutoGen")
+	if err != nil {
+		reader.View(reader.Local(originalBase))
+		return TagAutoGenView{}, err
+	}
+	reader.EndObject()
+	view := TagAutoGenView{
+		schema: schema,
+		reader: reader,
+		base:   reader.Base(),
+	}
+	reader.Seek(reader.Local(nextOffset), io.SeekStart)
+	reader.View(reader.Local(originalBase))
+	return view, nil
+}
+
+func (schema *TagAutoGenSchema) SingleWrite(writer *goschema.SchemaWriter, value *fixtures.Tag, context int) {
+	originalBase := writer.Base()
+	schema.NakedWrite(writer, value, context)
+	writer.View(writer.Local(originalBase))
+}
+
+func (schema *TagAutoGenSchema) NakedWrite(writer *goschema.SchemaWriter, value *fixtures.Tag, context int) {
+	if writer.Err() == nil {
+		// the hooks of all nested objects run first, since forward writers
+		// compute sizes up front
+		beforeWriteTagAutoGen(value, context)
+	}
+	schema.nakedWrite(writer, value, context)
+}
+
+// SingleWritePrepared is like SingleWrite, but does not run the
+// BeforeSchemaWrite hooks. It writes a value that RunBeforeWriteHooks has
+// prepared, e.g. after checking its EncodedSize.
+func (schema *TagAutoGenSchema) SingleWritePrepared(writer *goschema.SchemaWriter, value *fixtures.Tag, context int) {
+	originalBase := writer.Base()
+	schema.nakedWrite(writer, value, context)
+	writer.View(writer.Local(originalBase))
+}
+
+// RunBeforeWriteHooks runs the BeforeSchemaWrite hooks of the value and of all
+// objects that it contains, like SingleWrite does before writing anything.
+func (schema *TagAutoGenSchema) RunBeforeWriteHooks(value *fixtures.Tag, context int) {
+	beforeWriteTagAutoGen(value, context)
+}
+
+// nakedWrite writes an object whose hooks have run already. It is called for
+// nested objects.
+func (schema *TagAutoGenSchema) nakedWrite(writer *goschema.SchemaWriter, value *fixtures.Tag, context int) {
+	if writer.Err() != nil {
+		return
+	}
+	if writer.IsForward() {
+		schema.forwardNakedWrite(writer, value, context)
+		return
+	}
+	startOffset := writer.BeginObject()
+	writer.Seek(4, io.SeekCurrent)
+	schema.WriteName(writer, value.Name, context)
+	writer.EndObject(startOffset)
+}
+
+// forwardNakedWrite produces the same data as NakedWrite without seeking by
+// computing all references up front.
+func (schema *TagAutoGenSchema) forwardNakedWrite(writer *goschema.SchemaWriter, value *fixtures.Tag, context int) {
+	overhead := writer.ObjectOverhead()
+	startOffset := writer.BeginSizedObject(sizeTagAutoGen(value, overhead) - overhead)
+	reference := 4
+	writer.WriteUInt32(uint32(reference))
+	reference += 4 + len(value.Name)
+
+	schema.forwardWriteName(writer, value.Name, context)
+	writer.EndObject(startOffset)
+}
+
+// EncodedSize returns the number of bytes that SingleWritePrepared writes for
+// the given value, assuming that checksums are disabled. It does not include
+// the index of the schema written by WriteTagAutoGenSchema, and it does
+// not run any hooks.
+func (schema *TagAutoGenSchema) EncodedSize(value *fixtures.Tag) int {
+	return sizeTagAutoGen(value, 4)
+}
+
+// beforeWriteTagAutoGen runs the BeforeSchemaWrite hooks of the value and
+// of all objects that it contains.
+func beforeWriteTagAutoGen(value *fixtures.Tag, context int) {
+	value.BeforeSchemaWrite(context)
+}
+
+// sizeTagAutoGen returns the number of bytes written by NakedWrite for
+// the given value if each object takes up overhead bytes besides its data.
+func sizeTagAutoGen(value *fixtures.Tag, overhead int) int {
+	size := overhead + 4
+	size += 4 + len(value.Name)
+
+	return size
+}
+
+func (schema *TagAutoGenSchema) WriteName(writer *goschema.SchemaWriter, value string, context int) {
+	offset := writer.Offset()
+	writer.Seek(int64(schema.NameOffset), io.SeekStart)
+	writer.WriteUInt32(uint32(offset))
+	writer.Seek(offset, io.SeekStart)
+	writer.WriteUInt32(uint32(len(value)))
+	writer.WriteString(value)
+}
+
+func (schema *TagAutoGenSchema) forwardWriteName(writer *goschema.SchemaWriter, value string, context int) {
+	writer.WriteUInt32(uint32(len(value)))
+	writer.WriteString(value)
+
+}
+
+func (schema *TagAutoGenSchema) ReadNameInto(reader *goschema.SchemaReader, value *string, context int) error {
+	if schema.NameOffset == -1 {
+		var tmp string
+		*value = tmp
+		return nil
+	}
+	offset := reader.Offset()
+	reader.Seek(int64(schema.NameOffset), io.SeekStart)
+	fieldOffset := reader.ReadUInt32()
+	reader.Seek(int64(fieldOffset), io.SeekStart)
+	v1Length := reader.ReadStringLength()
+	*value = string(reader.ReadString(v1Length))
+
+	reader.Seek(offset, io.SeekStart)
+	return reader.Err()
+}
+
+func (view TagAutoGenView) GetName(context int) (string, error) {
+	var value string
+	reader := view.reader
+	originalBase := reader.Base()
+	offset := reader.GlobalOffset()
+	reader.View(reader.Local(view.base))
+	err := view.schema.ReadNameInto(reader, &value, context)
+	reader.View(reader.Local(originalBase))
+	reader.Seek(reader.Local(offset), io.SeekStart)
+	return value, err
+}
+
+func (schema *TagAutoGenSchema) GetName(reader *goschema.SchemaReader, context int) (string, error) {
+	offset := reader.Offset()
+	view, err := schema.NakedView(reader)
+	reader.Seek(offset, io.SeekStart)
+	if err != nil {
+		var value string
+		return value, err
+	}
+	return view.GetName(context)
+}
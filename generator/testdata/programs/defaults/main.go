@@ -0,0 +1,47 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+
+	"example.com/generated/out"
+
+	"github.com/chasingcarrots/gobinary"
+	"github.com/chasingcarrots/goschema"
+	"github.com/chasingcarrots/goschema/generator/testdata/fixtures"
+)
+
+// Account is the older version of fixtures.Account without settings.
+type Account struct {
+	Gold int32
+}
+
+func check(err error) {
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
+
+// main reads an account without settings with the generated code and checks
+// that the parent's defaults override those of the missing settings.
+func main() {
+	var dbBuf gobinary.WriteBuffer
+	dbWriter := goschema.MakeSchemaDBWriter(gobinary.NewStreamWriter(&dbBuf))
+	writer := goschema.MakeByteSchemaWriter(&dbWriter, nil)
+	check(goschema.Marshal(&writer, &Account{Gold: 5}))
+	dbWriter.Close()
+
+	schemaDB := goschema.MakeSchemaDB()
+	check(schemaDB.Fill(bytes.NewReader(dbBuf.Bytes())))
+	reader := goschema.MakeByteSchemaReader(&schemaDB, writer.Bytes())
+	var result fixtures.Account
+	check(out.ReadAccountSchema(&reader).SingleRead(&reader, &result, 0))
+	expected := fixtures.Account{Gold: 5, Settings: fixtures.AccountSettings{Slots: 32, Pages: 2}}
+	if result != expected {
+		fmt.Printf("read %+v\n", result)
+		os.Exit(1)
+	}
+	fmt.Println("ok")
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"reflect"
+
+	"example.com/generated/out"
+
+	"github.com/chasingcarrots/gobinary"
+	"github.com/chasingcarrots/goschema"
+	"github.com/chasingcarrots/goschema/generator/testdata/fixtures"
+)
+
+func document() fixtures.Document {
+	return fixtures.Document{
+		Title:    "doc",
+		Main:     &fixtures.Tag{Name: "main"},
+		Tags:     []fixtures.Tag{{Name: "a"}, {Name: "b"}},
+		ByName:   map[string]fixtures.Tag{"c": {Name: "c"}},
+		Sections: []fixtures.Section{{Tags: []fixtures.Tag{{Name: "d"}}}},
+	}
+}
+
+func check(err error) {
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
+
+// main writes a document whose nested hooks change its size to a forward
+// writer and reads it back.
+func main() {
+	doc := document()
+	var dbBuf gobinary.WriteBuffer
+	dbWriter := goschema.MakeSchemaDBWriter(gobinary.NewStreamWriter(&dbBuf))
+	var data bytes.Buffer
+	writer := goschema.MakeForwardSchemaWriter(&dbWriter, &data)
+	out.WriteDocumentSchema(&writer).SingleWrite(&writer, &doc, 0)
+	dbWriter.Close()
+	check(writer.Err())
+
+	schemaDB := goschema.MakeSchemaDB()
+	check(schemaDB.Fill(bytes.NewReader(dbBuf.Bytes())))
+	reader := goschema.MakeByteSchemaReader(&schemaDB, data.Bytes())
+	var result fixtures.Document
+	check(out.ReadDocumentSchema(&reader).SingleRead(&reader, &result, 0))
+	if !reflect.DeepEqual(doc, result) {
+		fmt.Printf("wrote %+v\nread  %+v\n", doc, result)
+		os.Exit(1)
+	}
+
+	// computing the size does not run the hooks
+	sized := document()
+	schema := out.NewDocumentSchema()
+	schema.EncodedSize(&sized)
+	if !reflect.DeepEqual(sized, document()) {
+		fmt.Printf("EncodedSize changed the value to %+v\n", sized)
+		os.Exit(1)
+	}
+
+	// once the hooks have run, the size is that of the data written without
+	// running them again, which does not include the index of the schema
+	schema.RunBeforeWriteHooks(&sized, 0)
+	size := schema.EncodedSize(&sized)
+	data.Reset()
+	var preparedDB gobinary.WriteBuffer
+	dbWriter = goschema.MakeSchemaDBWriter(gobinary.NewStreamWriter(&preparedDB))
+	writer = goschema.MakeForwardSchemaWriter(&dbWriter, &data)
+	schema.SingleWritePrepared(&writer, &sized, 0)
+	check(writer.Err())
+	if size != data.Len() || !reflect.DeepEqual(sized, doc) {
+		fmt.Printf("encoded size %v, wrote %v bytes of %+v\n", size, data.Len(), sized)
+		os.Exit(1)
+	}
+	fmt.Println("ok")
+}
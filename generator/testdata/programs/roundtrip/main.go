@@ -0,0 +1,148 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"reflect"
+
+	"example.com/generated/out"
+
+	"github.com/chasingcarrots/gobinary"
+	"github.com/chasingcarrots/goschema"
+	"github.com/chasingcarrots/goschema/generator/testdata/fixtures"
+)
+
+// values holds one value of each fixture that is written and read back.
+type values struct {
+	Document fixtures.Document
+	Node     fixtures.Node
+	Catalog  fixtures.Catalog
+	Scalars  fixtures.Scalars
+}
+
+// sample returns the values to write. Lists are never read as nil, so all of
+// them are empty or have elements, and maps have at most one entry, so that
+// every writer writes them in the same order.
+func sample() values {
+	leaf := func(value int32) fixtures.Node {
+		return fixtures.Node{Value: value, Children: []fixtures.Node{}}
+	}
+	counts := []int32{1, -2}
+	lookup := map[string]int16{"a": -3}
+	return values{
+		Document: fixtures.Document{
+			Title:    "doc",
+			Main:     &fixtures.Tag{Name: "main"},
+			Tags:     []fixtures.Tag{{Name: "a"}, {Name: "b"}},
+			ByName:   map[string]fixtures.Tag{"c": {Name: "c"}},
+			Sections: []fixtures.Section{{Tags: []fixtures.Tag{{Name: "d"}}}},
+		},
+		Node: fixtures.Node{Value: 1, Children: []fixtures.Node{{Value: 2, Children: []fixtures.Node{leaf(3)}}, leaf(4)}},
+		Catalog: fixtures.Catalog{
+			Title:    "catalog",
+			Featured: &fixtures.Item{Name: "featured", Count: 1, Weight: 2},
+			Items:    []fixtures.Item{{Name: "a", Count: 2}, {Name: "b", Weight: -1}},
+			Links:    []*fixtures.Item{{Name: "c"}, nil},
+			ByName:   map[string]fixtures.Item{"d": {Name: "d", Count: 3}},
+			Groups:   map[int32][]fixtures.Item{7: {{Name: "e"}, {Name: "f"}}},
+			Labels:   []string{"x", ""},
+		},
+		Scalars: fixtures.Scalars{
+			Flag:     true,
+			Int:      -1 << 40,
+			Int8:     -8,
+			Int16:    -16,
+			Int32:    -32,
+			Int64:    -64,
+			UInt:     1 << 40,
+			UInt8:    8,
+			UInt16:   16,
+			UInt32:   32,
+			UInt64:   1 << 63,
+			Float32:  0.5,
+			Float64:  -0.25,
+			Text:     "text",
+			Position: fixtures.Vector2{X: 1, Y: 2},
+			Path:     []fixtures.Vector2{{X: 3}, {Y: 4}},
+			Origin:   &fixtures.Vector2{X: -1, Y: -2},
+			Counts:   &counts,
+			Lookup:   &lookup,
+		},
+	}
+}
+
+// write writes the sample values with the given kind of writer and returns the
+// values as they were written together with the schema DB and the data.
+func write(kind string, checksums bool) (values, []byte, []byte) {
+	var dbBuf gobinary.WriteBuffer
+	dbWriter := goschema.MakeSchemaDBWriter(gobinary.NewStreamWriter(&dbBuf))
+	var data func() []byte
+	var writer goschema.SchemaWriter
+	switch kind {
+	case "bytes":
+		writer = goschema.MakeByteSchemaWriter(&dbWriter, nil)
+		data = writer.Bytes
+	case "stream":
+		var buf gobinary.WriteBuffer
+		writer = goschema.MakeSchemaWriter(&dbWriter, gobinary.MakeStreamWriterView(gobinary.NewStreamWriter(&buf)))
+		data = buf.Bytes
+	case "forward":
+		var buf bytes.Buffer
+		writer = goschema.MakeForwardSchemaWriter(&dbWriter, &buf)
+		data = buf.Bytes
+	}
+	if checksums {
+		writer.EnableChecksums()
+	}
+	v := sample()
+	out.WriteDocumentSchema(&writer).SingleWrite(&writer, &v.Document, 0)
+	out.WriteNodeSchema(&writer).SingleWrite(&writer, &v.Node, 0)
+	out.WriteCatalogSchema(&writer).SingleWrite(&writer, &v.Catalog, 0)
+	out.WriteScalarsSchema(&writer).SingleWrite(&writer, &v.Scalars, 0)
+	dbWriter.Close()
+	check(writer.Err())
+	return v, dbBuf.Bytes(), data()
+}
+
+// read reads the values in the order in which write writes them.
+func read(reader *goschema.SchemaReader) values {
+	var v values
+	check(out.ReadDocumentSchema(reader).SingleRead(reader, &v.Document, 0))
+	check(out.ReadNodeSchema(reader).SingleRead(reader, &v.Node, 0))
+	check(out.ReadCatalogSchema(reader).SingleRead(reader, &v.Catalog, 0))
+	check(out.ReadScalarsSchema(reader).SingleRead(reader, &v.Scalars, 0))
+	return v
+}
+
+func check(err error) {
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
+
+// main writes the fixtures with every kind of writer, checks that the data is
+// the same and reads it back.
+func main() {
+	for _, checksums := range []bool{false, true} {
+		var expected []byte
+		for _, kind := range []string{"bytes", "stream", "forward"} {
+			written, dbData, data := write(kind, checksums)
+			if expected == nil {
+				expected = data
+			} else if !bytes.Equal(data, expected) {
+				fmt.Printf("%v writer with checksums %v wrote different data\n", kind, checksums)
+				os.Exit(1)
+			}
+			schemaDB := goschema.MakeSchemaDB()
+			check(schemaDB.Fill(bytes.NewReader(dbData)))
+			reader := goschema.MakeByteSchemaReader(&schemaDB, data)
+			if result := read(&reader); !reflect.DeepEqual(written, result) {
+				fmt.Printf("%v writer with checksums %v:\nwrote %+v\nread  %+v\n", kind, checksums, written, result)
+				os.Exit(1)
+			}
+		}
+	}
+	fmt.Println("ok")
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+
+	"example.com/generated/out"
+
+	"github.com/chasingcarrots/gobinary"
+	"github.com/chasingcarrots/goschema"
+	"github.com/chasingcarrots/goschema/generator/testdata/fixtures"
+)
+
+// Ranged and Ranges have the layout of the fixtures, but no constraints, so
+// that they can be used to write invalid data.
+type Ranged struct {
+	Level int32
+}
+
+type Ranges struct {
+	Ranges []Ranged
+	Ratio  float64
+}
+
+func check(err error) {
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
+
+// checkViolation checks that err is a *goschema.ValidationError of the given
+// field.
+func checkViolation(err error, field, message string) {
+	var validationErr *goschema.ValidationError
+	if !errors.As(err, &validationErr) || validationErr.Field != field {
+		fmt.Printf("%v: %v instead of a violation of %v\n", message, err, field)
+		os.Exit(1)
+	}
+}
+
+// main checks that invalid values fail to be written and read, and that
+// deprecated fields are not validated when writing.
+func main() {
+	invalid := []struct {
+		value fixtures.Ranges
+		field string
+	}{
+		{fixtures.Ranges{Ranges: []fixtures.Ranged{{Level: 3}, {Level: 11}}}, "Ranges.Level"},
+		{fixtures.Ranges{Ranges: []fixtures.Ranged{{Level: 0}}}, "Ranges.Level"},
+		{fixtures.Ranges{Ratio: 2}, "Ratio"},
+		{fixtures.Ranges{Ratio: math.NaN()}, "Ratio"},
+	}
+	for _, test := range invalid {
+		for _, forward := range []bool{false, true} {
+			var dbBuf gobinary.WriteBuffer
+			dbWriter := goschema.MakeSchemaDBWriter(gobinary.NewStreamWriter(&dbBuf))
+			var buf bytes.Buffer
+			writer := goschema.MakeByteSchemaWriter(&dbWriter, nil)
+			if forward {
+				writer = goschema.MakeForwardSchemaWriter(&dbWriter, &buf)
+			}
+			out.WriteRangesSchema(&writer).SingleWrite(&writer, &test.value, 0)
+			checkViolation(writer.Err(), test.field, fmt.Sprintf("writing %+v", test.value))
+		}
+
+		// data written without constraints fails to be read
+		unchecked := Ranges{Ratio: test.value.Ratio}
+		for _, ranged := range test.value.Ranges {
+			unchecked.Ranges = append(unchecked.Ranges, Ranged{Level: ranged.Level})
+		}
+		var dbBuf gobinary.WriteBuffer
+		dbWriter := goschema.MakeSchemaDBWriter(gobinary.NewStreamWriter(&dbBuf))
+		writer := goschema.MakeByteSchemaWriter(&dbWriter, nil)
+		check(goschema.Marshal(&writer, &unchecked))
+		dbWriter.Close()
+		schemaDB := goschema.MakeSchemaDB()
+		check(schemaDB.Fill(bytes.NewReader(dbBuf.Bytes())))
+		reader := goschema.MakeByteSchemaReader(&schemaDB, writer.Bytes())
+		var result fixtures.Ranges
+		err := out.ReadRangesSchema(&reader).SingleRead(&reader, &result, 0)
+		checkViolation(err, test.field, fmt.Sprintf("reading %+v", test.value))
+	}
+
+	for _, forward := range []bool{false, true} {
+		var dbBuf gobinary.WriteBuffer
+		dbWriter := goschema.MakeSchemaDBWriter(gobinary.NewStreamWriter(&dbBuf))
+		var buf bytes.Buffer
+		writer := goschema.MakeByteSchemaWriter(&dbWriter, nil)
+		if forward {
+			writer = goschema.MakeForwardSchemaWriter(&dbWriter, &buf)
+		}
+		value := fixtures.Ranged{Level: 3}
+		out.WriteRangedSchema(&writer).SingleWrite(&writer, &value, 0)
+		dbWriter.Close()
+		check(writer.Err())
+		data := writer.Bytes()
+		if forward {
+			data = buf.Bytes()
+		}
+
+		schemaDB := goschema.MakeSchemaDB()
+		check(schemaDB.Fill(bytes.NewReader(dbBuf.Bytes())))
+		reader := goschema.MakeByteSchemaReader(&schemaDB, data)
+		var result fixtures.Ranged
+		check(out.ReadRangedSchema(&reader).SingleRead(&reader, &result, 0))
+		if result != (fixtures.Ranged{Level: 3, Legacy: 1}) {
+			fmt.Printf("read %+v\n", result)
+			os.Exit(1)
+		}
+	}
+	fmt.Println("ok")
+}
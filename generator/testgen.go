@@ -0,0 +1,287 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// maxRandomDepth is the depth of nested objects from which on random values
+// have empty collections and nil pointers.
+const maxRandomDepth = 2
+
+const schemaTestTemplate = `package {{ .Package }}
+
+import (
+{{- range .Imports }}
+	{{ . }}
+{{- end }}
+)
+
+// random{{ .SchemaName }} creates a value whose serialized fields are random.
+func random{{ .SchemaName }}(rnd *rand.Rand, depth int) {{ .TargetType }} {
+	var value {{ .TargetType }}
+{{- range .Fields }}
+	value.{{ .FieldName }} = {{ .Code }}
+{{- end }}
+	return value
+}
+
+// encode{{ .SchemaName }} writes the value and returns the data and the schema DB.
+func encode{{ .SchemaName }}(tb testing.TB, value *{{ .TargetType }}) ([]byte, *goschema.SchemaDB) {
+	var schemaDBBuf gobinary.WriteBuffer
+	schemaDBWriter := goschema.MakeSchemaDBWriter(gobinary.NewStreamWriter(&schemaDBBuf))
+	writer := goschema.MakeByteSchemaWriter(&schemaDBWriter, nil)
+	var context {{ .WritingContextType }}
+	Write{{ .SchemaName }}Schema(&writer).SingleWrite(&writer, value, context)
+	if err := writer.Err(); err != nil {
+		tb.Fatal(err)
+	}
+	schemaDBWriter.Close()
+	schemaDB := goschema.MakeSchemaDB()
+	if err := schemaDB.Fill(bytes.NewReader(schemaDBBuf.Bytes())); err != nil {
+		tb.Fatal(err)
+	}
+	return writer.Bytes(), &schemaDB
+}
+
+func Test{{ .SchemaName }}RoundTrip(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		value := random{{ .SchemaName }}(rnd, 0)
+		data, schemaDB := encode{{ .SchemaName }}(t, &value)
+		reader := goschema.MakeByteSchemaReader(schemaDB, data)
+		var result {{ .TargetType }}
+		var context {{ .ReadingContextType }}
+		if err := Read{{ .SchemaName }}Schema(&reader).SingleRead(&reader, &result, context); err != nil {
+			t.Fatal(err)
+		}
+{{- if .Compare }}
+{{- range .Fields }}
+		if !reflect.DeepEqual(value.{{ .FieldName }}, result.{{ .FieldName }}) {
+			t.Fatalf("{{ .Name }} differs after a round trip:\n%#v\n%#v", value.{{ .FieldName }}, result.{{ .FieldName }})
+		}
+{{- end }}
+{{- else }}
+		// values are not compared since AfterSchemaRead or SetSchemaDefaults may change them
+{{- end }}
+	}
+}
+
+func Benchmark{{ .SchemaName }}Encode(b *testing.B) {
+	value := random{{ .SchemaName }}(rand.New(rand.NewSource(1)), 0)
+	var schemaDBBuf gobinary.WriteBuffer
+	schemaDBWriter := goschema.MakeSchemaDBWriter(gobinary.NewStreamWriter(&schemaDBBuf))
+	var buf []byte
+	var context {{ .WritingContextType }}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		// each write gets a copy since BeforeSchemaWrite may change the value
+		value := value
+		writer := goschema.MakeByteSchemaWriter(&schemaDBWriter, buf[:0])
+		Write{{ .SchemaName }}Schema(&writer).SingleWrite(&writer, &value, context)
+		buf = writer.Bytes()
+	}
+	b.SetBytes(int64(len(buf)))
+}
+
+func Benchmark{{ .SchemaName }}Decode(b *testing.B) {
+	value := random{{ .SchemaName }}(rand.New(rand.NewSource(1)), 0)
+	data, schemaDB := encode{{ .SchemaName }}(b, &value)
+	var context {{ .ReadingContextType }}
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		reader := goschema.MakeByteSchemaReader(schemaDB, data)
+		var result {{ .TargetType }}
+		if err := Read{{ .SchemaName }}Schema(&reader).SingleRead(&reader, &result, context); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+`
+
+var schemaTestTmpl = template.Must(template.New("SchemaTest").Parse(schemaTestTemplate))
+
+// GenerateTests makes Generate write a test file along with each schema. It
+// contains a round-trip test with random values and benchmarks for encoding and
+// decoding, and it is passed to the output writer under the name of the schema
+// with the suffix "_schema_test".
+func (c *Context) GenerateTests() {
+	c.generateTests = true
+}
+
+// generateTest returns the test file for a schema with the given fields.
+func (c *Context) generateTest(data *SchemaMetaData, fields []schemaField) *bytes.Buffer {
+	// the test has its own imports
+	testData := &SchemaMetaData{Imports: map[string]struct{}{
+		"bytes":                              {},
+		"math/rand":                          {},
+		"testing":                            {},
+		"github.com/chasingcarrots/gobinary": {},
+		"github.com/chasingcarrots/goschema": {},
+	}}
+	c.schemaStack = append(c.schemaStack, testData)
+	defer func() { c.schemaStack = c.schemaStack[:len(c.schemaStack)-1] }()
+
+	type testField struct {
+		Name, FieldName, Code string
+	}
+	testFields := make([]testField, len(fields))
+	for i, field := range fields {
+		structField, _ := data.Type.FieldByName(field.FieldName)
+		testFields[i] = testField{
+			Name:      field.Name,
+			FieldName: field.FieldName,
+			Code:      c.randomCode(structField.Type, structField.Tag),
+		}
+	}
+	compare := !c.hasReadHook(data.Type, make(map[reflect.Type]bool))
+	if compare {
+		testData.Imports["reflect"] = struct{}{}
+	}
+	lookup := Lookup{
+		"Package":            c.packageName(),
+		"SchemaName":         data.Name,
+		"TargetType":         c.GetTypeName(data.Type),
+		"WritingContextType": c.GetTypeName(c.writeContext),
+		"ReadingContextType": c.GetTypeName(c.readContext),
+		"Fields":             testFields,
+		"Compare":            compare,
+	}
+	lookup["Imports"] = importSpecs(testData.Imports)
+
+	var buf bytes.Buffer
+	schemaTestTmpl.Execute(&buf, lookup)
+	return &buf
+}
+
+// randomCode returns an expression for a random value of the given type that
+// satisfies the constraints declared by the tags. It may refer to the variables
+// rnd and depth.
+func (c *Context) randomCode(typ reflect.Type, tags reflect.StructTag) string {
+	typeName := c.GetTypeName(typ)
+	serializer := c.FindSerializer(Target{Type: typ, Tags: tags})
+	if _, ok := serializer.(*SchemaSerializer); ok {
+		return fmt.Sprintf("random%v(rnd, depth+1)", c.GetSchema(typ).Name)
+	}
+	_, notNil := tags.Lookup("schemaNotNil")
+	maxLen := 3
+	if value, ok := tags.Lookup("schemaMaxLen"); ok {
+		if n, err := strconv.Atoi(value); err == nil && n < maxLen {
+			maxLen = n
+		}
+	}
+	length := fmt.Sprintf("func() int { if depth >= %v { return 0 }; return rnd.Intn(%v) }()", maxRandomDepth, maxLen+1)
+	if typ.Kind() == reflect.String {
+		length = fmt.Sprintf("rnd.Intn(%v)", maxLen+1)
+	}
+
+	switch typ.Kind() {
+	case reflect.Bool:
+		return "rnd.Intn(2) == 1"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		min, hasMin := tags.Lookup("schemaMin")
+		max, hasMax := tags.Lookup("schemaMax")
+		if typ.Kind() == reflect.Float32 || typ.Kind() == reflect.Float64 {
+			switch {
+			case hasMin && hasMax:
+				return fmt.Sprintf("func() %v { lo, hi := float64(%v), float64(%v); return %v(lo + (hi-lo)*rnd.Float64()) }()", typeName, min, max, typeName)
+			case hasMin:
+				return fmt.Sprintf("%v(float64(%v) + rnd.ExpFloat64())", typeName, min)
+			case hasMax:
+				return fmt.Sprintf("%v(float64(%v) - rnd.ExpFloat64())", typeName, max)
+			}
+			return typeName + "(rnd.NormFloat64())"
+		}
+		if !hasMin && !hasMax {
+			return typeName + "(rnd.Uint64())"
+		}
+		// bounded values are drawn from the range between the bounds, where a
+		// missing bound is the limit of the type
+		kind := typ.Kind().String()
+		unsigned := "u" + kind
+		if typ.Kind() >= reflect.Uint {
+			unsigned = kind
+		}
+		if !hasMin || !hasMax {
+			c.schemaStack[len(c.schemaStack)-1].Imports["math"] = struct{}{}
+		}
+		limit := strings.ToUpper(kind[:1]) + kind[1:]
+		if !hasMin {
+			min = "math.Min" + limit
+			if typ.Kind() >= reflect.Uint {
+				min = "0"
+			}
+		}
+		if !hasMax {
+			max = "math.Max" + limit
+		}
+		// the arithmetic wraps around, so the offset from the lower bound may
+		// be converted to the type even if it does not fit into it
+		return fmt.Sprintf("func() %v { lo, hi := %v(%v), %v(%v); n := rnd.Uint64(); if span := uint64(%v(hi - lo)); span < ^uint64(0) { n %%= span + 1 }; return lo + %v(n) }()",
+			typeName, typeName, min, typeName, max, unsigned, typeName)
+	case reflect.String:
+		return fmt.Sprintf("func() %v { p := make([]byte, %v); rnd.Read(p); return %v(p) }()", typeName, length, typeName)
+	case reflect.Slice:
+		token := c.UniqueToken()
+		return fmt.Sprintf("func() %v { %vSlice := make(%v, %v); for %vI := range %vSlice { %vSlice[%vI] = %v }; return %vSlice }()",
+			typeName, token, typeName, length, token, token, token, token, c.randomCode(typ.Elem(), ""), token)
+	case reflect.Map:
+		token := c.UniqueToken()
+		return fmt.Sprintf("func() %v { %vMap := make(%v); for %vI := %v; %vI > 0; %vI-- { %vMap[%v] = %v }; return %vMap }()",
+			typeName, token, typeName, token, length, token, token, token, c.randomCode(typ.Key(), ""), c.randomCode(typ.Elem(), ""), token)
+	case reflect.Ptr:
+		token := c.UniqueToken()
+		code := fmt.Sprintf("%vValue := %v; return &%vValue", token, c.randomCode(typ.Elem(), ""), token)
+		if notNil {
+			return fmt.Sprintf("func() %v { %v }()", typeName, code)
+		}
+		return fmt.Sprintf("func() %v { if depth >= %v || rnd.Intn(4) == 0 { return nil }; %v }()", typeName, maxRandomDepth, code)
+	case reflect.Struct:
+		// structs that are serialized in place, e.g. by an InlineSerializer
+		var fields []string
+		for i := 0; i < typ.NumField(); i++ {
+			field := typ.Field(i)
+			if field.PkgPath == "" {
+				fields = append(fields, field.Name+": "+c.randomCode(field.Type, ""))
+			}
+		}
+		return typeName + "{" + strings.Join(fields, ", ") + "}"
+	}
+	return "*new(" + typeName + ")"
+}
+
+// hasReadHook returns whether reading a value of the given type calls an
+// AfterSchemaRead or SetSchemaDefaults method, which may change the value that
+// was read.
+func (c *Context) hasReadHook(typ reflect.Type, visited map[reflect.Type]bool) bool {
+	if visited[typ] {
+		return false
+	}
+	visited[typ] = true
+	switch typ.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Array:
+		return c.hasReadHook(typ.Elem(), visited)
+	case reflect.Map:
+		return c.hasReadHook(typ.Key(), visited) || c.hasReadHook(typ.Elem(), visited)
+	case reflect.Struct:
+		if _, ok := reflect.PtrTo(typ).MethodByName("AfterSchemaRead"); ok {
+			return true
+		}
+		if reflect.PtrTo(typ).Implements(schemaDefaulterType) {
+			return true
+		}
+		for i := 0; i < typ.NumField(); i++ {
+			if c.hasReadHook(typ.Field(i).Type, visited) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,16 @@
+package generator
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/chasingcarrots/goschema/generator/testdata/fixtures"
+)
+
+func TestGeneratedTests(t *testing.T) {
+	testGenerated(t, func(c *Context) {
+		c.RequestSchema(reflect.TypeOf(fixtures.Limits{}), "Limits")
+		c.RequestSchema(reflect.TypeOf(fixtures.Document{}), "Document")
+		c.RequestSchema(reflect.TypeOf(fixtures.Node{}), "Node")
+	})
+}
@@ -0,0 +1,82 @@
+package generator
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+
+	"github.com/chasingcarrots/goschema"
+)
+
+// constraint is a check of a field declared by a validation tag.
+type constraint struct {
+	Name       string // name of the field in the schema
+	Violation  string // condition under which the value violates the constraint
+	Tag        string // the tag that declares the constraint
+	Deprecated bool   // whether the field is only read, but not written
+}
+
+// constraints returns the constraints declared by the tags schemaMin,
+// schemaMax, schemaMaxLen and schemaNotNil on a field.
+func constraints(owner reflect.Type, field reflect.StructField, name string) ([]constraint, error) {
+	var result []constraint
+	access := "value." + field.Name
+	kind := field.Type.Kind()
+	numeric := kind >= reflect.Int && kind <= reflect.Float64
+	for _, bound := range []struct{ tag, operator string }{{"schemaMin", "<"}, {"schemaMax", ">"}} {
+		value, ok := field.Tag.Lookup(bound.tag)
+		if !ok {
+			continue
+		}
+		if !numeric {
+			return nil, fmt.Errorf("field %v of %v has %v, but is not a number", field.Name, owner.String(), bound.tag)
+		}
+		if err := checkLiteral(field.Type, value); err != nil {
+			return nil, fmt.Errorf("%v of field %v of %v: %v", bound.tag, field.Name, owner.String(), err)
+		}
+		violation := access + " " + bound.operator + " " + value
+		if kind == reflect.Float32 || kind == reflect.Float64 {
+			// NaN is neither below nor above a bound, but it is not within them either
+			violation += " || " + access + " != " + access
+		}
+		result = append(result, constraint{
+			Name:      name,
+			Violation: violation,
+			Tag:       bound.tag + ":" + strconv.Quote(value),
+		})
+	}
+	if value, ok := field.Tag.Lookup("schemaMaxLen"); ok {
+		if kind != reflect.String && kind != reflect.Slice && kind != reflect.Map {
+			return nil, fmt.Errorf("field %v of %v has schemaMaxLen, but is not a string, slice or map", field.Name, owner.String())
+		}
+		n, err := strconv.ParseUint(value, 10, 31)
+		if err != nil {
+			return nil, fmt.Errorf("schemaMaxLen of field %v of %v: %v is not a valid length", field.Name, owner.String(), value)
+		}
+		result = append(result, constraint{
+			Name:      name,
+			Violation: fmt.Sprintf("len(%v) > %v", access, n),
+			Tag:       "schemaMaxLen:" + strconv.Quote(value),
+		})
+	}
+	if _, ok := field.Tag.Lookup("schemaNotNil"); ok {
+		if kind != reflect.Ptr && kind != reflect.Slice && kind != reflect.Map {
+			return nil, fmt.Errorf("field %v of %v has schemaNotNil, but is not a pointer, slice or map", field.Name, owner.String())
+		}
+		result = append(result, constraint{
+			Name:      name,
+			Violation: access + " == nil",
+			Tag:       `schemaNotNil:""`,
+		})
+	}
+	return result, nil
+}
+
+// containsSchema returns whether values of the described type may contain
+// objects with schemata.
+func containsSchema(descriptor *goschema.TypeDescriptor) bool {
+	if descriptor == nil {
+		return false
+	}
+	return descriptor.Code == goschema.SchemaType || containsSchema(descriptor.Key) || containsSchema(descriptor.Elem)
+}
@@ -0,0 +1,19 @@
+package generator
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/chasingcarrots/goschema/generator/testdata/fixtures"
+)
+
+func TestValidation(t *testing.T) {
+	output := runGenerated(t, "validation", func(c *Context) {
+		c.RequestSchema(reflect.TypeOf(fixtures.Ranged{}), "Ranged")
+		c.RequestSchema(reflect.TypeOf(fixtures.Ranges{}), "Ranges")
+	})
+	if strings.TrimSpace(output) != "ok" {
+		t.Fatal(output)
+	}
+}
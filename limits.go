@@ -0,0 +1,144 @@
+package goschema
+
+import (
+	"fmt"
+	"io"
+)
+
+const maxInt = int(^uint(0) >> 1)
+
+// ReadLimits bound the resources that a SchemaReader may use for data from an
+// untrusted source. A limit of zero means that the resource is not limited.
+type ReadLimits struct {
+	MaxAllocation       int64 // bytes allocated for strings, lists and maps of each outermost object
+	MaxStringLength     int   // bytes of a single string
+	MaxCollectionLength int   // entries of a single list or map
+	MaxDepth            int   // number of objects nested in each other
+}
+
+// LimitError is reported when reading data would exceed one of the limits set
+// by SetReadLimits.
+type LimitError struct {
+	Limit string // name of the field of ReadLimits that would be exceeded
+	Value int64
+	Max   int64
+}
+
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("goschema: %v exceeded (%v > %v)", e.Limit, e.Value, e.Max)
+}
+
+// SetReadLimits sets the limits that generated code enforces when reading. The
+// total allocation is counted for each outermost object, such as an object read
+// by SingleRead or Unmarshal, so that a reader can be used for any number of
+// objects.
+func (sr *SchemaReader) SetReadLimits(limits ReadLimits) {
+	sr.limits = limits
+	sr.allocated = 0
+}
+
+// ReadLimits returns the limits set by SetReadLimits.
+func (sr *SchemaReader) ReadLimits() ReadLimits {
+	return sr.limits
+}
+
+// ReadCollectionLength reads the number of entries of a list or map, each of
+// which takes up elementSize bytes in memory. It returns 0 if the reader has
+// failed or if the collection exceeds the limits of the reader, in which case
+// the reader fails with a *LimitError.
+func (sr *SchemaReader) ReadCollectionLength(elementSize int) int {
+	length := int64(sr.ReadUInt32())
+	if !sr.available(length) {
+		return 0
+	}
+	if max := int64(sr.limits.MaxCollectionLength); max > 0 && length > max {
+		sr.err = &LimitError{Limit: "MaxCollectionLength", Value: length, Max: max}
+		return 0
+	}
+	if !sr.allocate(length * int64(elementSize)) {
+		return 0
+	}
+	return int(length)
+}
+
+// ReadStringLength reads the length of a string. Like ReadCollectionLength, it
+// returns 0 if the reader has failed or if the string exceeds the limits.
+func (sr *SchemaReader) ReadStringLength() int {
+	length := int64(sr.ReadUInt32())
+	if !sr.available(length) {
+		return 0
+	}
+	if max := int64(sr.limits.MaxStringLength); max > 0 && length > max {
+		sr.err = &LimitError{Limit: "MaxStringLength", Value: length, Max: max}
+		return 0
+	}
+	if !sr.allocate(length) {
+		return 0
+	}
+	return int(length)
+}
+
+// available reports whether the reader has not failed and whether a string or
+// collection with the given length can be present in the data. Since each
+// entry takes up at least one byte, lengths that exceed the remaining data are
+// rejected.
+func (sr *SchemaReader) available(length int64) bool {
+	if sr.Err() != nil {
+		return false
+	}
+	if length > int64(maxInt) {
+		sr.err = &LimitError{Limit: "MaxAllocation", Value: length, Max: int64(maxInt)}
+		return false
+	}
+	if br := sr.bytes; br != nil {
+		if length > int64(len(br.data))-br.position {
+			br.err = &OutOfRangeError{Offset: br.position, Length: int(length), Size: len(br.data)}
+			return false
+		}
+		return true
+	}
+	position := sr.GlobalOffset()
+	if position+length > sr.streamEnd && !sr.measureStream() {
+		// streams that cannot seek to their end are only bounded by the limits
+		return true
+	}
+	if position+length > sr.streamEnd {
+		sr.err = &OutOfRangeError{Offset: position, Length: int(length), Size: int(sr.streamEnd)}
+		return false
+	}
+	return true
+}
+
+// measureStream updates the end of the stream that the reader reads from, which
+// may have grown since it was last measured. It reports whether the stream
+// supports this.
+func (sr *SchemaReader) measureStream() bool {
+	offset := sr.Offset()
+	if _, err := sr.Seek(0, io.SeekEnd); err != nil {
+		sr.Seek(offset, io.SeekStart)
+		return false
+	}
+	sr.streamEnd = sr.GlobalOffset()
+	_, err := sr.Seek(offset, io.SeekStart)
+	return err == nil
+}
+
+// allocate accounts for size bytes and reports whether they are within the
+// allocation limit.
+func (sr *SchemaReader) allocate(size int64) bool {
+	sr.allocated += size
+	if max := sr.limits.MaxAllocation; max > 0 && sr.allocated > max {
+		sr.err = &LimitError{Limit: "MaxAllocation", Value: sr.allocated, Max: max}
+		return false
+	}
+	return true
+}
+
+// EndObject marks the end of an object started with BeginObject. It is only
+// needed to keep track of the nesting depth and of verified checksums.
+func (sr *SchemaReader) EndObject() {
+	sr.depth--
+	if sr.depth < sr.verifiedDepth {
+		sr.verifiedDepth = 0
+	}
+}
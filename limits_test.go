@@ -0,0 +1,42 @@
+package goschema
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/chasingcarrots/gobinary"
+)
+
+type limitedText struct {
+	Text string
+}
+
+func TestMaxAllocationPerObject(t *testing.T) {
+	text := limitedText{Text: strings.Repeat("x", 100)}
+	data, schemaDB := marshalWithSchemaDB(t, &text, &text, &text, &limitedText{Text: strings.Repeat("y", 101)})
+	reader := MakeByteSchemaReader(schemaDB, data)
+	reader.SetReadLimits(ReadLimits{MaxAllocation: 100})
+	for i := 0; i < 3; i++ {
+		var result limitedText
+		if err := Unmarshal(&reader, &result); err != nil {
+			t.Fatalf("object %v: %v", i, err)
+		}
+	}
+	var result limitedText
+	err := Unmarshal(&reader, &result)
+	if limitErr, ok := err.(*LimitError); !ok || limitErr.Limit != "MaxAllocation" {
+		t.Fatalf("got %v", err)
+	}
+}
+
+func TestStreamLengthsAreBounded(t *testing.T) {
+	data := []byte{0xff, 0xff, 0xff, 0x7f, 1, 2, 3}
+	reader := MakeSchemaReader(nil, gobinary.MakeStreamReaderView(gobinary.NewStreamReader(bytes.NewReader(data))))
+	if length := reader.ReadStringLength(); length != 0 {
+		t.Fatalf("read length %v", length)
+	}
+	if _, ok := reader.Err().(*OutOfRangeError); !ok {
+		t.Fatalf("got %v", reader.Err())
+	}
+}
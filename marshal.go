@@ -0,0 +1,488 @@
+package goschema
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+)
+
+// Marshal writes a struct, or a pointer to one, without generated code. Like
+// Write<Name>Schema followed by SingleWrite, it writes the index of the schema
+// and the object. The data and the schema descriptors are the same as those of
+// generated code, provided that every struct type is requested from the
+// generator under its Go name and no custom serializers are involved, so that
+// switching to generated code later does not change the format. Marshal
+// supports the tags schemaIgnore, schemaName and schemaDefault, and it fails
+// for fields with tags that only the generator supports. It does not call
+// BeforeSchemaWrite.
+func Marshal(w *SchemaWriter, v interface{}) error {
+	value := reflect.ValueOf(v)
+	if value.Kind() == reflect.Ptr && !value.IsNil() {
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return fmt.Errorf("goschema: cannot marshal %T", v)
+	}
+	rt, err := reflectTypeOf(value.Type())
+	if err != nil {
+		return err
+	}
+	if err := w.Err(); err != nil {
+		return err
+	}
+	rt.writeSchema(w)
+	originalBase := w.Base()
+	rt.nakedWrite(w, value)
+	w.View(w.Local(originalBase))
+	return w.Err()
+}
+
+// Unmarshal reads an object written by Marshal, or by generated code, into the
+// struct that v points to. Like generated code, it calls SetSchemaDefaults, but
+// not AfterSchemaRead.
+func Unmarshal(r *SchemaReader, v interface{}) error {
+	value := reflect.ValueOf(v)
+	if value.Kind() != reflect.Ptr || value.IsNil() || value.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("goschema: cannot unmarshal into %T", v)
+	}
+	rt, err := reflectTypeOf(value.Elem().Type())
+	if err != nil {
+		return err
+	}
+	schema := rt.readSchema(r)
+	originalBase := r.Base()
+	err = schema.nakedRead(r, value.Elem())
+	r.View(r.Local(originalBase))
+	return err
+}
+
+// lookupReflectType returns the *reflectType of a struct type nested in a type
+// that has been analyzed already.
+func lookupReflectType(typ reflect.Type) *reflectType {
+	rt, _ := reflectTypes.Load(typ)
+	return rt.(*reflectType)
+}
+
+// writeSchema writes the index of the schema, like Write<Name>Schema.
+func (rt *reflectType) writeSchema(w *SchemaWriter) {
+	schemaEntry, ok := w.FindSchema(rt.id)
+	schemaIdx := schemaEntry.Index()
+	if !ok {
+		schemaIdx = w.RegisterSchema(rt.native)
+	}
+	w.WriteUInt32(uint32(schemaIdx))
+}
+
+func (rt *reflectType) nakedWrite(w *SchemaWriter, value reflect.Value) {
+	if w.Err() != nil {
+		return
+	}
+	if w.IsForward() {
+		rt.forwardNakedWrite(w, value)
+		return
+	}
+	startOffset := w.BeginObject()
+	w.Seek(int64(rt.headerSize), io.SeekCurrent)
+	for i := range rt.fields {
+		field := &rt.fields[i]
+		offset := w.Offset()
+		w.Seek(int64(rt.entries[i].Offset), io.SeekStart)
+		if field.inPlace {
+			writeValue(w, value.Field(field.index))
+			w.Seek(offset, io.SeekStart)
+		} else {
+			w.WriteUInt32(uint32(offset))
+			w.Seek(offset, io.SeekStart)
+			writeValue(w, value.Field(field.index))
+		}
+	}
+	w.EndObject(startOffset)
+}
+
+// forwardNakedWrite produces the same data as nakedWrite without seeking.
+func (rt *reflectType) forwardNakedWrite(w *SchemaWriter, value reflect.Value) {
+	overhead := w.ObjectOverhead()
+	startOffset := w.BeginSizedObject(rt.size(value, overhead) - overhead)
+	reference := int(rt.headerSize)
+	for i := range rt.fields {
+		field := &rt.fields[i]
+		if field.inPlace {
+			writeValue(w, value.Field(field.index))
+		} else {
+			w.WriteUInt32(uint32(reference))
+			reference += valueSize(value.Field(field.index), overhead)
+		}
+	}
+	for i := range rt.fields {
+		if field := &rt.fields[i]; !field.inPlace {
+			writeValue(w, value.Field(field.index))
+		}
+	}
+	w.EndObject(startOffset)
+}
+
+// size returns the number of bytes written by nakedWrite if each object takes
+// up overhead bytes besides its data.
+func (rt *reflectType) size(value reflect.Value, overhead int) int {
+	size := overhead + int(rt.headerSize)
+	for i := range rt.fields {
+		if field := &rt.fields[i]; !field.inPlace {
+			size += valueSize(value.Field(field.index), overhead)
+		}
+	}
+	return size
+}
+
+// writeValue writes a value that is not itself an object, like the code of
+// the default serializers.
+func writeValue(w *SchemaWriter, value reflect.Value) {
+	switch value.Kind() {
+	case reflect.Bool:
+		w.WriteBool(value.Bool())
+	case reflect.Int:
+		w.WriteInt(int(value.Int()))
+	case reflect.Int8:
+		w.WriteInt8(int8(value.Int()))
+	case reflect.Int16:
+		w.WriteInt16(int16(value.Int()))
+	case reflect.Int32:
+		w.WriteInt32(int32(value.Int()))
+	case reflect.Int64:
+		w.WriteInt64(value.Int())
+	case reflect.Uint:
+		w.WriteUInt(uint(value.Uint()))
+	case reflect.Uint8:
+		w.WriteUInt8(uint8(value.Uint()))
+	case reflect.Uint16:
+		w.WriteUInt16(uint16(value.Uint()))
+	case reflect.Uint32:
+		w.WriteUInt32(uint32(value.Uint()))
+	case reflect.Uint64:
+		w.WriteUInt64(value.Uint())
+	case reflect.Float32:
+		w.WriteFloat32(float32(value.Float()))
+	case reflect.Float64:
+		w.WriteFloat64(value.Float())
+	case reflect.String:
+		w.WriteUInt32(uint32(value.Len()))
+		w.WriteString(value.String())
+	case reflect.Slice, reflect.Array:
+		elem := writeElemCode(w, value.Type().Elem())
+		viewBase := w.Base()
+		w.WriteUInt32(uint32(value.Len()))
+		for i := 0; i < value.Len(); i++ {
+			writeElem(w, elem, value.Index(i))
+		}
+		w.View(w.Local(viewBase))
+	case reflect.Ptr:
+		elem := writeElemCode(w, value.Type().Elem())
+		viewBase := w.Base()
+		w.WriteBool(!value.IsNil())
+		if !value.IsNil() {
+			writeElem(w, elem, value.Elem())
+		}
+		w.View(w.Local(viewBase))
+	case reflect.Map:
+		key := writeElemCode(w, value.Type().Key())
+		elem := writeElemCode(w, value.Type().Elem())
+		viewBase := w.Base()
+		w.WriteUInt32(uint32(value.Len()))
+		iter := value.MapRange()
+		for iter.Next() {
+			writeElem(w, key, iter.Key())
+			writeElem(w, elem, iter.Value())
+		}
+		w.View(w.Local(viewBase))
+	case reflect.Struct:
+		rt := lookupReflectType(value.Type())
+		rt.writeSchema(w)
+		viewBase := w.Base()
+		rt.nakedWrite(w, value)
+		w.View(w.Local(viewBase))
+	}
+}
+
+// writeElemCode writes the type code of the elements of a list, pointer or map
+// and, if they are objects, the index of their schema, which it returns.
+func writeElemCode(w *SchemaWriter, typ reflect.Type) *reflectType {
+	code, _ := reflectTypeCode(typ)
+	w.WriteUInt8(uint8(code))
+	if code != SchemaType {
+		return nil
+	}
+	rt := lookupReflectType(typ)
+	rt.writeSchema(w)
+	return rt
+}
+
+func writeElem(w *SchemaWriter, rt *reflectType, value reflect.Value) {
+	if rt != nil {
+		rt.nakedWrite(w, value)
+		return
+	}
+	writeValue(w, value)
+}
+
+// valueSize returns the number of bytes that writeValue writes.
+func valueSize(value reflect.Value, overhead int) int {
+	switch value.Kind() {
+	case reflect.String:
+		return 4 + value.Len()
+	case reflect.Slice, reflect.Array:
+		elem := value.Type().Elem()
+		size := 1 + 4 + elemCodeSize(elem)
+		if code, _ := reflectTypeCode(elem); isFixedSize(code) {
+			return size + value.Len()*int(elem.Size())
+		}
+		for i := 0; i < value.Len(); i++ {
+			size += elemSize(value.Index(i), overhead)
+		}
+		return size
+	case reflect.Ptr:
+		size := 1 + elemCodeSize(value.Type().Elem()) + 1
+		if !value.IsNil() {
+			size += elemSize(value.Elem(), overhead)
+		}
+		return size
+	case reflect.Map:
+		size := 1 + 1 + 4 + elemCodeSize(value.Type().Key()) + elemCodeSize(value.Type().Elem())
+		iter := value.MapRange()
+		for iter.Next() {
+			size += elemSize(iter.Key(), overhead) + elemSize(iter.Value(), overhead)
+		}
+		return size
+	case reflect.Struct:
+		return 4 + lookupReflectType(value.Type()).size(value, overhead)
+	}
+	return int(value.Type().Size())
+}
+
+// elemCodeSize returns the size of the schema index that writeElemCode writes
+// after the type code.
+func elemCodeSize(typ reflect.Type) int {
+	if typ.Kind() == reflect.Struct {
+		return 4
+	}
+	return 0
+}
+
+func elemSize(value reflect.Value, overhead int) int {
+	if value.Kind() == reflect.Struct {
+		return lookupReflectType(value.Type()).size(value, overhead)
+	}
+	return valueSize(value, overhead)
+}
+
+// readSchema reads the index of a schema, like Read<Name>Schema.
+func (rt *reflectType) readSchema(r *SchemaReader) *reflectSchema {
+	schemaIdx := int(r.ReadUInt32())
+	existingSchema, schemaEntries := r.FindSchema(schemaIdx)
+	schema, ok := existingSchema.(*reflectSchema)
+	if !ok || schema.reflectType != rt {
+		schema = rt.newSchema()
+		r.VerifySchemaName(schemaIdx, rt.name)
+		if fingerprint, ok := r.SchemaFingerprint(schemaIdx); !ok || fingerprint != rt.fingerprint {
+			schema.Fill(schemaEntries)
+		}
+		r.RegisterSchema(schemaIdx, schema)
+	}
+	return schema
+}
+
+func (schema *reflectSchema) nakedRead(r *SchemaReader, value reflect.Value) error {
+	nextOffset, err := r.BeginObject(schema.name)
+	if err != nil {
+		return err
+	}
+	defer r.EndObject()
+	if schema.defaulter {
+		// missing nested values are initialized before the parent's defaults
+		for i := range schema.fields {
+			if schema.offsets[i] == -1 && isDefaulter(schema.fields[i].typ) {
+				setSchemaDefaults(value.Field(schema.fields[i].index))
+			}
+		}
+		value.Addr().Interface().(SchemaDefaulter).SetSchemaDefaults()
+	}
+	for i := range schema.fields {
+		if err := schema.readField(r, i, value.Field(schema.fields[i].index)); err != nil {
+			return err
+		}
+	}
+	r.Seek(r.Local(nextOffset), io.SeekStart)
+	return r.Err()
+}
+
+// isDefaulter returns whether values of the given type are initialized with
+// SetSchemaDefaults when they are missing.
+func isDefaulter(typ reflect.Type) bool {
+	return typ.Kind() != reflect.Ptr && reflect.PtrTo(typ).Implements(schemaDefaulterType)
+}
+
+// setSchemaDefaults zeroes the value and calls its SetSchemaDefaults.
+func setSchemaDefaults(value reflect.Value) {
+	value.Set(reflect.Zero(value.Type()))
+	value.Addr().Interface().(SchemaDefaulter).SetSchemaDefaults()
+}
+
+func (schema *reflectSchema) readField(r *SchemaReader, i int, value reflect.Value) error {
+	field := &schema.fields[i]
+	if schema.offsets[i] == -1 {
+		switch {
+		case field.defaultTag != "":
+			// composite defaults are parsed again so that reads do not share them
+			defaultValue, _ := parseDefault(field.typ, field.defaultTag)
+			value.Set(defaultValue)
+		case schema.defaulter:
+			// keep the value set by SetSchemaDefaults
+		case isDefaulter(field.typ):
+			setSchemaDefaults(value)
+		default:
+			value.Set(reflect.Zero(field.typ))
+		}
+		return nil
+	}
+	offset := r.Offset()
+	r.Seek(int64(schema.offsets[i]), io.SeekStart)
+	if code := schema.types[i]; code != schema.entries[i].Type {
+		// Fill only accepts other types for numbers that can be widened
+		switch value.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			value.SetInt(r.ReadWidenedInt(code))
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			value.SetUint(r.ReadWidenedUInt(code))
+		case reflect.Float32, reflect.Float64:
+			value.SetFloat(r.ReadWidenedFloat(code))
+		}
+		r.Seek(offset, io.SeekStart)
+		return r.Err()
+	}
+	if !field.inPlace {
+		fieldOffset := r.ReadUInt32()
+		r.Seek(int64(fieldOffset), io.SeekStart)
+	}
+	if err := readValue(r, value); err != nil {
+		return err
+	}
+	r.Seek(offset, io.SeekStart)
+	return r.Err()
+}
+
+// readValue reads a value written by writeValue into an addressable value.
+func readValue(r *SchemaReader, value reflect.Value) error {
+	typ := value.Type()
+	switch value.Kind() {
+	case reflect.Bool:
+		value.SetBool(r.ReadBool())
+	case reflect.Int:
+		value.SetInt(int64(r.ReadInt()))
+	case reflect.Int8:
+		value.SetInt(int64(r.ReadInt8()))
+	case reflect.Int16:
+		value.SetInt(int64(r.ReadInt16()))
+	case reflect.Int32:
+		value.SetInt(int64(r.ReadInt32()))
+	case reflect.Int64:
+		value.SetInt(r.ReadInt64())
+	case reflect.Uint:
+		value.SetUint(r.ReadUInt())
+	case reflect.Uint8:
+		value.SetUint(uint64(r.ReadUInt8()))
+	case reflect.Uint16:
+		value.SetUint(uint64(r.ReadUInt16()))
+	case reflect.Uint32:
+		value.SetUint(uint64(r.ReadUInt32()))
+	case reflect.Uint64:
+		value.SetUint(r.ReadUInt64())
+	case reflect.Float32:
+		value.SetFloat(float64(r.ReadFloat32()))
+	case reflect.Float64:
+		value.SetFloat(r.ReadFloat64())
+	case reflect.String:
+		length := r.ReadStringLength()
+		value.SetString(r.ReadString(length))
+	case reflect.Slice, reflect.Array:
+		_ = r.ReadUInt8() // ignore typecode
+		elem := readElemSchema(r, typ.Elem())
+		viewBase := r.Base()
+		entries := r.ReadCollectionLength(int(typ.Elem().Size()))
+		list := value
+		if value.Kind() == reflect.Slice {
+			list = reflect.MakeSlice(typ, entries, entries)
+		} else {
+			value.Set(reflect.Zero(typ))
+		}
+		for i := 0; i < entries; i++ {
+			target := reflect.New(typ.Elem()).Elem()
+			if i < list.Len() {
+				target = list.Index(i)
+			}
+			if err := readElem(r, elem, target); err != nil {
+				return err
+			}
+		}
+		if value.Kind() == reflect.Slice {
+			value.Set(list)
+		}
+		r.View(r.Local(viewBase))
+	case reflect.Ptr:
+		_ = r.ReadUInt8() // ignore typecode
+		elem := readElemSchema(r, typ.Elem())
+		viewBase := r.Base()
+		if r.ReadBool() {
+			target := reflect.New(typ.Elem())
+			if err := readElem(r, elem, target.Elem()); err != nil {
+				return err
+			}
+			value.Set(target)
+		} else {
+			value.Set(reflect.Zero(typ))
+		}
+		r.View(r.Local(viewBase))
+	case reflect.Map:
+		_ = r.ReadUInt8() // ignore typecode
+		key := readElemSchema(r, typ.Key())
+		_ = r.ReadUInt8() // ignore typecode
+		elem := readElemSchema(r, typ.Elem())
+		viewBase := r.Base()
+		entries := r.ReadCollectionLength(int(typ.Key().Size() + typ.Elem().Size()))
+		m := reflect.MakeMap(typ)
+		for i := 0; i < entries; i++ {
+			mapKey := reflect.New(typ.Key()).Elem()
+			if err := readElem(r, key, mapKey); err != nil {
+				return err
+			}
+			mapValue := reflect.New(typ.Elem()).Elem()
+			if err := readElem(r, elem, mapValue); err != nil {
+				return err
+			}
+			m.SetMapIndex(mapKey, mapValue)
+		}
+		value.Set(m)
+		r.View(r.Local(viewBase))
+	case reflect.Struct:
+		schema := lookupReflectType(typ).readSchema(r)
+		viewBase := r.Base()
+		if err := schema.nakedRead(r, value); err != nil {
+			return err
+		}
+		r.View(r.Local(viewBase))
+	}
+	return nil
+}
+
+// readElemSchema reads the index of the schema of the elements of a list,
+// pointer or map if they are objects.
+func readElemSchema(r *SchemaReader, typ reflect.Type) *reflectSchema {
+	if typ.Kind() != reflect.Struct {
+		return nil
+	}
+	return lookupReflectType(typ).readSchema(r)
+}
+
+func readElem(r *SchemaReader, schema *reflectSchema, value reflect.Value) error {
+	if schema != nil {
+		return schema.nakedRead(r, value)
+	}
+	return readValue(r, value)
+}
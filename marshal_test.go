@@ -0,0 +1,68 @@
+package goschema
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/chasingcarrots/gobinary"
+)
+
+// marshalValues marshals the values and returns the data together with the
+// schema DB that describes it.
+func marshalValues(tb testing.TB, values ...interface{}) (data, schemaDB []byte) {
+	var dbBuf gobinary.WriteBuffer
+	dbWriter := MakeSchemaDBWriter(gobinary.NewStreamWriter(&dbBuf))
+	writer := MakeByteSchemaWriter(&dbWriter, nil)
+	for _, v := range values {
+		if err := Marshal(&writer, v); err != nil {
+			tb.Fatal(err)
+		}
+	}
+	dbWriter.Close()
+	return writer.Bytes(), dbBuf.Bytes()
+}
+
+// marshalWithSchemaDB is like marshalValues, but returns the filled schema DB.
+func marshalWithSchemaDB(tb testing.TB, values ...interface{}) ([]byte, *SchemaDB) {
+	data, dbData := marshalValues(tb, values...)
+	schemaDB := MakeSchemaDB()
+	if err := schemaDB.Fill(bytes.NewReader(dbData)); err != nil {
+		tb.Fatal(err)
+	}
+	return data, &schemaDB
+}
+
+type inventorySettings struct {
+	Slots, Pages int32
+}
+
+func (s *inventorySettings) SetSchemaDefaults() { s.Slots, s.Pages = 16, 2 }
+
+type inventory struct {
+	Gold     int32
+	Settings inventorySettings
+}
+
+func (inv *inventory) SetSchemaDefaults() { inv.Settings.Slots = 32 }
+
+// writeOldInventory writes an older version of inventory without settings.
+func writeOldInventory(t *testing.T) ([]byte, *SchemaDB) {
+	type inventory struct {
+		Gold int32
+	}
+	return marshalWithSchemaDB(t, &inventory{Gold: 5})
+}
+
+func TestUnmarshalNestedDefaults(t *testing.T) {
+	data, schemaDB := writeOldInventory(t)
+	reader := MakeByteSchemaReader(schemaDB, data)
+	var result inventory
+	if err := Unmarshal(&reader, &result); err != nil {
+		t.Fatal(err)
+	}
+	// the parent's defaults override those of the nested struct
+	expected := inventory{Gold: 5, Settings: inventorySettings{Slots: 32, Pages: 2}}
+	if result != expected {
+		t.Fatalf("read %+v", result)
+	}
+}
@@ -0,0 +1,64 @@
+package goschema
+
+import (
+	"os"
+	"syscall"
+)
+
+// MappedFile is a file that is mapped into memory read-only.
+type MappedFile struct {
+	data []byte
+}
+
+// MapFile maps the file at the given path into memory. The mapping stays valid
+// after the file has been closed, until Close is called on the MappedFile.
+func MapFile(path string) (*MappedFile, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+	size := info.Size()
+	if size == 0 {
+		return &MappedFile{}, nil
+	}
+	if int64(int(size)) != size {
+		return nil, &os.PathError{Op: "mmap", Path: path, Err: syscall.EFBIG}
+	}
+	data, err := syscall.Mmap(int(file.Fd()), 0, int(size), syscall.PROT_READ, syscall.MAP_SHARED)
+	if err != nil {
+		return nil, &os.PathError{Op: "mmap", Path: path, Err: err}
+	}
+	return &MappedFile{data: data}, nil
+}
+
+// Bytes returns the mapped memory. It must not be modified.
+func (mf *MappedFile) Bytes() []byte {
+	return mf.data
+}
+
+// Close unmaps the file. Neither the mapped memory nor strings aliasing it may
+// be used afterwards.
+func (mf *MappedFile) Close() error {
+	if mf.data == nil {
+		return nil
+	}
+	data := mf.data
+	mf.data = nil
+	return syscall.Munmap(data)
+}
+
+// MakeMappedSchemaReader creates a SchemaReader that reads from a mapped file.
+// If aliasStrings is set, strings that are read point into the mapped memory
+// instead of being copied; they must not be used after the file is closed.
+func MakeMappedSchemaReader(schemaDB *SchemaDB, file *MappedFile, aliasStrings bool) SchemaReader {
+	reader := MakeByteSchemaReader(schemaDB, file.Bytes())
+	if aliasStrings {
+		reader.AliasStrings()
+	}
+	return reader
+}
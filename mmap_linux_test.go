@@ -0,0 +1,89 @@
+package goschema_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"unsafe"
+
+	"github.com/chasingcarrots/goschema"
+	"github.com/chasingcarrots/goschema/generator/testdata/fixtures"
+	"github.com/chasingcarrots/goschema/generator/testdata/generated"
+)
+
+// within reports whether the data of s lies within data.
+func within(s string, data []byte) bool {
+	start := uintptr(unsafe.Pointer(unsafe.SliceData(data)))
+	address := uintptr(unsafe.Pointer(unsafe.StringData(s)))
+	return address >= start && address+uintptr(len(s)) <= start+uintptr(len(data))
+}
+
+func TestMappedSchemaReader(t *testing.T) {
+	catalog := sampleCatalog()
+	schemaDBData, data := writeWith("bytes", false, func(writer *goschema.SchemaWriter) {
+		generated.WriteCatalogSchema(writer).SingleWrite(writer, &catalog, 0)
+	})
+	schemaDB := goschema.MakeSchemaDB()
+	if err := schemaDB.Fill(bytes.NewReader(schemaDBData)); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "catalog.bin")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, aliasStrings := range []bool{false, true} {
+		file, err := goschema.MapFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(file.Bytes(), data) {
+			t.Fatal("the mapped memory differs from the file")
+		}
+		reader := goschema.MakeMappedSchemaReader(&schemaDB, file, aliasStrings)
+		var result fixtures.Catalog
+		if err := generated.ReadCatalogSchema(&reader).SingleRead(&reader, &result, 0); err != nil {
+			t.Fatal(err)
+		}
+		expected := catalog
+		expected.Hidden = 0
+		if !reflect.DeepEqual(result, expected) {
+			t.Errorf("aliasStrings %v: read %+v", aliasStrings, result)
+		}
+		if within(result.Title, file.Bytes()) != aliasStrings {
+			t.Errorf("aliasStrings %v: the title aliases the mapped memory: %v", aliasStrings, !aliasStrings)
+		}
+		if err := file.Close(); err != nil {
+			t.Fatal(err)
+		}
+		if file.Bytes() != nil {
+			t.Error("the file is still mapped")
+		}
+		// closing twice is harmless
+		if err := file.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestMapEmptyFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "empty.bin")
+	if err := os.WriteFile(path, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	file, err := goschema.MapFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(file.Bytes()) != 0 {
+		t.Errorf("mapped %v bytes", len(file.Bytes()))
+	}
+	if err := file.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := goschema.MapFile(filepath.Join(t.TempDir(), "missing.bin")); !os.IsNotExist(err) {
+		t.Errorf("mapping a missing file failed with %v", err)
+	}
+}
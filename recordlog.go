@@ -0,0 +1,236 @@
+package goschema
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/chasingcarrots/gobinary"
+)
+
+const recordLogMagic = "GSRL"
+
+// the trailer of a record log ends with the size of the data, the size of the
+// index, the number of records, and the magic bytes
+const recordLogFooterSize = 8 + 8 + 4 + len(recordLogMagic)
+
+// MaxRecordKeyLength is the maximum length of the key of a record in bytes.
+const MaxRecordKeyLength = math.MaxUint16
+
+var ErrInvalidRecordLog = errors.New("goschema: invalid record log")
+var ErrRecordKeyTooLong = errors.New("goschema: record key is longer than MaxRecordKeyLength")
+
+// RecordLogWriter writes a sequence of records to a SchemaWriter and appends
+// an index of all records when it is closed. Each record is usually a schema
+// index followed by a single object, as written by Write<Name>Schema and
+// SingleWrite, but records are not restricted to that. All records share the
+// schema descriptors of the SchemaDBWriter that the SchemaWriter uses.
+type RecordLogWriter struct {
+	writer  *SchemaWriter
+	start   int64
+	offsets []int64
+	keys    []string
+}
+
+func MakeRecordLogWriter(writer *SchemaWriter) RecordLogWriter {
+	return RecordLogWriter{
+		writer: writer,
+		start:  writer.GlobalOffset(),
+	}
+}
+
+// NextRecord starts a new record with an optional key and returns the writer
+// that the record should be written with. If the key is longer than
+// MaxRecordKeyLength, the writer fails with ErrRecordKeyTooLong and the record
+// is stored without a key.
+func (lw *RecordLogWriter) NextRecord(key string) *SchemaWriter {
+	if len(key) > MaxRecordKeyLength {
+		lw.writer.Fail(ErrRecordKeyTooLong)
+		key = ""
+	}
+	lw.offsets = append(lw.offsets, lw.writer.GlobalOffset()-lw.start)
+	lw.keys = append(lw.keys, key)
+	return lw.writer
+}
+
+// Len returns the number of records written so far.
+func (lw *RecordLogWriter) Len() int {
+	return len(lw.offsets)
+}
+
+// Close writes the index of the records. It does not close the SchemaDBWriter.
+func (lw *RecordLogWriter) Close() {
+	w := lw.writer
+	indexStart := w.GlobalOffset()
+	for i := range lw.offsets {
+		w.WriteUInt64(uint64(lw.offsets[i]))
+		w.WriteUInt16(uint16(len(lw.keys[i])))
+		w.WriteString(lw.keys[i])
+	}
+	indexEnd := w.GlobalOffset()
+	w.WriteUInt64(uint64(indexStart - lw.start))
+	w.WriteUInt64(uint64(indexEnd - indexStart))
+	w.WriteUInt32(uint32(len(lw.offsets)))
+	w.WriteString(recordLogMagic)
+}
+
+// RecordLog gives random access to the records of a record log.
+type RecordLog struct {
+	reader  SchemaReader
+	start   int64 // global offset of the first record
+	offsets []int64
+	keys    []string
+	lookup  map[string]int
+	next    int
+}
+
+// OpenRecordLog reads the index of the record log at the end of the stream.
+func OpenRecordLog(schemaDB *SchemaDB, stream io.ReadSeeker) (*RecordLog, error) {
+	size, err := stream.Seek(0, io.SeekEnd)
+	if err != nil {
+		return nil, err
+	}
+	log, err := readRecordLogIndex(io.NewSectionReader(readerAtSeeker{stream}, 0, size), size)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := stream.Seek(log.start, io.SeekStart); err != nil {
+		return nil, err
+	}
+	log.reader = MakeSchemaReader(schemaDB, gobinary.MakeStreamReaderView(gobinary.NewStreamReader(stream)))
+	log.start = log.reader.GlobalOffset()
+	return log, nil
+}
+
+// OpenByteRecordLog reads the index of a record log that is held in memory.
+// The records are read with a reader over the byte slice.
+func OpenByteRecordLog(schemaDB *SchemaDB, data []byte) (*RecordLog, error) {
+	log, err := readRecordLogIndex(bytesReaderAt(data), int64(len(data)))
+	if err != nil {
+		return nil, err
+	}
+	log.reader = MakeByteSchemaReader(schemaDB, data)
+	return log, nil
+}
+
+func readRecordLogIndex(source io.ReaderAt, size int64) (*RecordLog, error) {
+	if size < int64(recordLogFooterSize) {
+		return nil, ErrInvalidRecordLog
+	}
+	footer := make([]byte, recordLogFooterSize)
+	if _, err := source.ReadAt(footer, size-int64(recordLogFooterSize)); err != nil {
+		return nil, err
+	}
+	if string(footer[20:]) != recordLogMagic {
+		return nil, ErrInvalidRecordLog
+	}
+	dataSize := int64(binary.LittleEndian.Uint64(footer[0:8]))
+	indexSize := int64(binary.LittleEndian.Uint64(footer[8:16]))
+	count := int(binary.LittleEndian.Uint32(footer[16:20]))
+	indexStart := size - int64(recordLogFooterSize) - indexSize
+	start := indexStart - dataSize
+	if dataSize < 0 || indexSize < 0 || start < 0 || indexStart < 0 || int64(count)*10 > indexSize {
+		return nil, ErrInvalidRecordLog
+	}
+
+	index := make([]byte, indexSize)
+	if _, err := source.ReadAt(index, indexStart); err != nil {
+		return nil, err
+	}
+	log := &RecordLog{
+		start:   start,
+		offsets: make([]int64, count),
+		keys:    make([]string, count),
+	}
+	for i := 0; i < count; i++ {
+		if len(index) < 10 {
+			return nil, ErrInvalidRecordLog
+		}
+		offset := int64(binary.LittleEndian.Uint64(index[0:8]))
+		keyLength := int(binary.LittleEndian.Uint16(index[8:10]))
+		index = index[10:]
+		if offset < 0 || offset > dataSize || len(index) < keyLength {
+			return nil, ErrInvalidRecordLog
+		}
+		log.offsets[i] = offset
+		log.keys[i] = string(index[:keyLength])
+		index = index[keyLength:]
+	}
+	return log, nil
+}
+
+// Len returns the number of records in the log.
+func (log *RecordLog) Len() int {
+	return len(log.offsets)
+}
+
+// Key returns the key of the i-th record.
+func (log *RecordLog) Key(i int) string {
+	return log.keys[i]
+}
+
+// Find returns the index of the last record with the given key.
+func (log *RecordLog) Find(key string) (int, bool) {
+	if log.lookup == nil {
+		log.lookup = make(map[string]int, len(log.keys))
+		for i, k := range log.keys {
+			log.lookup[k] = i
+		}
+	}
+	i, ok := log.lookup[key]
+	return i, ok
+}
+
+// ReadAt positions the reader of the log at the start of the i-th record and
+// returns it. Subsequent calls to Next continue after that record.
+func (log *RecordLog) ReadAt(i int) (*SchemaReader, error) {
+	if i < 0 || i >= len(log.offsets) {
+		return nil, fmt.Errorf("goschema: record %v out of range [0, %v)", i, len(log.offsets))
+	}
+	log.reader.View(log.reader.Local(log.start))
+	log.reader.Seek(log.offsets[i], io.SeekStart)
+	log.next = i + 1
+	return &log.reader, nil
+}
+
+// Next positions the reader at the start of the next record and returns it.
+// It returns io.EOF after the last record.
+func (log *RecordLog) Next() (*SchemaReader, error) {
+	if log.next >= len(log.offsets) {
+		return nil, io.EOF
+	}
+	return log.ReadAt(log.next)
+}
+
+// Rewind makes the next call to Next return the first record.
+func (log *RecordLog) Rewind() {
+	log.next = 0
+}
+
+type bytesReaderAt []byte
+
+func (b bytesReaderAt) ReadAt(p []byte, offset int64) (int, error) {
+	if offset < 0 || offset > int64(len(b)) {
+		return 0, io.EOF
+	}
+	n := copy(p, b[offset:])
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// readerAtSeeker implements io.ReaderAt on top of an io.ReadSeeker. It does
+// not restore the position of the stream.
+type readerAtSeeker struct {
+	stream io.ReadSeeker
+}
+
+func (r readerAtSeeker) ReadAt(p []byte, offset int64) (int, error) {
+	if _, err := r.stream.Seek(offset, io.SeekStart); err != nil {
+		return 0, err
+	}
+	return io.ReadFull(r.stream, p)
+}
@@ -0,0 +1,62 @@
+package goschema
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRecordLogKeys(t *testing.T) {
+	writer := MakeByteSchemaWriter(nil, nil)
+	recordLog := MakeRecordLogWriter(&writer)
+	for i, key := range []string{"a", strings.Repeat("b", MaxRecordKeyLength)} {
+		recordLog.NextRecord(key).WriteUInt32(uint32(i))
+	}
+	recordLog.Close()
+	if err := writer.Err(); err != nil {
+		t.Fatal(err)
+	}
+	log, err := OpenByteRecordLog(nil, writer.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	i, ok := log.Find(strings.Repeat("b", MaxRecordKeyLength))
+	if !ok || i != 1 {
+		t.Fatalf("found record %v, %v", i, ok)
+	}
+	reader, err := log.ReadAt(i)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value := reader.ReadUInt32(); value != 1 {
+		t.Fatalf("read %v", value)
+	}
+
+	writer = MakeByteSchemaWriter(nil, nil)
+	recordLog = MakeRecordLogWriter(&writer)
+	recordLog.NextRecord(strings.Repeat("c", MaxRecordKeyLength+1))
+	if err := writer.Err(); err != ErrRecordKeyTooLong {
+		t.Fatalf("got %v", err)
+	}
+}
+
+func TestRecordLogEmptyLastRecord(t *testing.T) {
+	writer := MakeByteSchemaWriter(nil, nil)
+	recordLog := MakeRecordLogWriter(&writer)
+	recordLog.NextRecord("a").WriteUInt32(1)
+	recordLog.NextRecord("empty")
+	recordLog.Close()
+	if err := writer.Err(); err != nil {
+		t.Fatal(err)
+	}
+	log, err := OpenByteRecordLog(nil, writer.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	i, ok := log.Find("empty")
+	if !ok || i != 1 {
+		t.Fatalf("found record %v, %v", i, ok)
+	}
+	if _, err := log.ReadAt(i); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,427 @@
+package goschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// reflectType describes how Marshal and Unmarshal serialize a struct type. It
+// mirrors the schema that the generator creates for the type with its default
+// serializers.
+type reflectType struct {
+	typ         reflect.Type
+	id          SchemaID
+	name        string
+	goType      string
+	fingerprint uint64
+	fields      []reflectField
+	entries     []SchemaEntry
+	headerSize  uint32
+	defaulter   bool // whether the type implements SchemaDefaulter
+	native      *reflectSchema
+}
+
+type reflectField struct {
+	index      int // index of the field in the struct
+	typ        reflect.Type
+	inPlace    bool   // whether the field is stored in the header
+	defaultTag string // value of the schemaDefault tag, empty if there is none
+}
+
+// reflectSchema is the Schema of a type that is serialized by Marshal and
+// Unmarshal. Its offsets and type codes are those of the data that is read.
+type reflectSchema struct {
+	*reflectType
+	offsets []int
+	types   []TypeCode
+}
+
+// tags that only generated code supports
+var generatorTags = []string{
+	"schemaRequired", "schemaAlias", "schemaDeprecated", "schemaDefaultFunc",
+	"schemaMin", "schemaMax", "schemaMaxLen", "schemaNotNil",
+}
+
+var schemaDefaulterType = reflect.TypeOf((*SchemaDefaulter)(nil)).Elem()
+
+// reflectTypes holds the *reflectType of each struct type that has been used
+// with Marshal or Unmarshal.
+var reflectTypes sync.Map
+
+// reflectRegistry serializes the analysis of new types and holds the IDs that
+// have been assigned to their schemata.
+var reflectRegistry struct {
+	sync.Mutex
+	ids          map[SchemaID]bool
+	fingerprints map[reflect.Type]uint64
+}
+
+// firstReflectID is the smallest ID of a schema of Marshal. They use the upper
+// half of the IDs so that they do not collide with those of generated schemata,
+// which are counted up from zero.
+const firstReflectID = 1 << 15
+
+// reflectTypeOf returns the *reflectType of a struct type, analyzing it and all
+// types nested in its fields if necessary.
+func reflectTypeOf(typ reflect.Type) (*reflectType, error) {
+	if rt, ok := reflectTypes.Load(typ); ok {
+		return rt.(*reflectType), nil
+	}
+	reflectRegistry.Lock()
+	defer reflectRegistry.Unlock()
+	if rt, ok := reflectTypes.Load(typ); ok {
+		return rt.(*reflectType), nil
+	}
+	if reflectRegistry.fingerprints == nil {
+		reflectRegistry.ids = make(map[SchemaID]bool)
+		reflectRegistry.fingerprints = make(map[reflect.Type]uint64)
+	}
+
+	building := make(map[reflect.Type]*reflectType)
+	var order []*reflectType
+	if err := buildReflectType(typ, building, &order); err != nil {
+		return nil, err
+	}
+	lookup := func(typ reflect.Type) *reflectType {
+		if rt, ok := building[typ]; ok {
+			return rt
+		}
+		rt, _ := reflectTypes.Load(typ)
+		return rt.(*reflectType)
+	}
+	if len(reflectRegistry.ids)+len(order) > math.MaxUint16+1-firstReflectID {
+		return nil, fmt.Errorf("goschema: too many types used with Marshal")
+	}
+	for _, rt := range order {
+		rt.id = reflectSchemaID(rt.goType)
+		rt.fingerprint, _ = reflectFingerprint(rt, nil, lookup)
+		rt.native = rt.newSchema()
+	}
+	for _, rt := range order {
+		reflectTypes.Store(rt.typ, rt)
+	}
+	return building[typ], nil
+}
+
+// reflectSchemaID assigns the ID of the schema of a Go type. It is derived from
+// the name of the type, so that it does not depend on the order in which types
+// are used. A type whose ID is taken by another one gets the next free ID.
+func reflectSchemaID(goType string) SchemaID {
+	hash := fnv.New32a()
+	hash.Write([]byte(goType))
+	id := SchemaID(hash.Sum32()) | firstReflectID
+	for reflectRegistry.ids[id] {
+		id = (id + 1) | firstReflectID
+	}
+	reflectRegistry.ids[id] = true
+	return id
+}
+
+// buildReflectType analyzes a struct type and the struct types nested in its
+// fields that have not been analyzed yet.
+func buildReflectType(typ reflect.Type, building map[reflect.Type]*reflectType, order *[]*reflectType) error {
+	if _, ok := building[typ]; ok {
+		return nil
+	}
+	if _, ok := reflectTypes.Load(typ); ok {
+		return nil
+	}
+	if typ.Name() == "" {
+		return fmt.Errorf("goschema: cannot marshal unnamed struct type %v", typ.String())
+	}
+	rt := &reflectType{
+		typ:       typ,
+		name:      typ.Name(),
+		goType:    typ.PkgPath() + "." + typ.Name(),
+		defaulter: reflect.PtrTo(typ).Implements(schemaDefaulterType),
+	}
+	building[typ] = rt
+	*order = append(*order, rt)
+
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if _, ignore := field.Tag.Lookup("schemaIgnore"); ignore {
+			continue
+		}
+		code, ok := reflectTypeCode(field.Type)
+		if !ok {
+			// the generator skips fields without a serializer as well
+			continue
+		}
+		if field.PkgPath != "" {
+			return fmt.Errorf("goschema: field %v of %v is unexported and not tagged with schemaIgnore", field.Name, typ.String())
+		}
+		for _, tag := range generatorTags {
+			if _, ok := field.Tag.Lookup(tag); ok {
+				return fmt.Errorf("goschema: field %v of %v has %v, which is only supported by generated code", field.Name, typ.String(), tag)
+			}
+		}
+		if err := buildNestedTypes(field.Type, building, order); err != nil {
+			return err
+		}
+
+		info := reflectField{index: i, typ: field.Type}
+		if value := field.Tag.Get("schemaDefault"); value != "" {
+			if _, err := parseDefault(field.Type, value); err != nil {
+				return fmt.Errorf("goschema: default value of field %v of %v: %v", field.Name, typ.String(), err)
+			}
+			info.defaultTag = value
+		}
+		info.inPlace = isFixedSize(code)
+		name := field.Tag.Get("schemaName")
+		if name == "" {
+			name = field.Name
+		}
+		rt.entries = append(rt.entries, SchemaEntry{
+			Name:       name,
+			Type:       code,
+			Offset:     rt.headerSize,
+			Descriptor: reflectDescriptor(field.Type),
+		})
+		rt.fields = append(rt.fields, info)
+		if info.inPlace {
+			rt.headerSize += uint32(field.Type.Size())
+		} else {
+			rt.headerSize += ReferenceSize
+		}
+	}
+	return nil
+}
+
+func buildNestedTypes(typ reflect.Type, building map[reflect.Type]*reflectType, order *[]*reflectType) error {
+	switch typ.Kind() {
+	case reflect.Struct:
+		return buildReflectType(typ, building, order)
+	case reflect.Ptr, reflect.Slice, reflect.Array:
+		return buildNestedTypes(typ.Elem(), building, order)
+	case reflect.Map:
+		if err := buildNestedTypes(typ.Key(), building, order); err != nil {
+			return err
+		}
+		return buildNestedTypes(typ.Elem(), building, order)
+	}
+	return nil
+}
+
+// reflectTypeCode returns the type code that the default serializers of the
+// generator use for a type. The second return value is false if none of them
+// can serialize the type.
+func reflectTypeCode(typ reflect.Type) (TypeCode, bool) {
+	switch typ.Kind() {
+	case reflect.Bool:
+		return BoolType, true
+	case reflect.Int:
+		return IntType, true
+	case reflect.Int8:
+		return Int8Type, true
+	case reflect.Int16:
+		return Int16Type, true
+	case reflect.Int32:
+		return Int32Type, true
+	case reflect.Int64:
+		return Int64Type, true
+	case reflect.Uint:
+		return UIntType, true
+	case reflect.Uint8:
+		return UInt8Type, true
+	case reflect.Uint16:
+		return UInt16Type, true
+	case reflect.Uint32:
+		return UInt32Type, true
+	case reflect.Uint64:
+		return UInt64Type, true
+	case reflect.Float32:
+		return Float32Type, true
+	case reflect.Float64:
+		return Float64Type, true
+	case reflect.String:
+		return StringType, true
+	case reflect.Struct:
+		return SchemaType, true
+	case reflect.Slice, reflect.Array:
+		_, ok := reflectTypeCode(typ.Elem())
+		return ListType, ok
+	case reflect.Ptr:
+		_, ok := reflectTypeCode(typ.Elem())
+		return PointerType, ok
+	case reflect.Map:
+		_, keyOK := reflectTypeCode(typ.Key())
+		_, elemOK := reflectTypeCode(typ.Elem())
+		return MapType, keyOK && elemOK
+	}
+	return 0, false
+}
+
+// isFixedSize reports whether values with the type code are stored in the
+// header of an object instead of being referenced.
+func isFixedSize(code TypeCode) bool {
+	return code != SchemaType && code != MapType && code != ListType && code != StringType && code != PointerType
+}
+
+func reflectDescriptor(typ reflect.Type) *TypeDescriptor {
+	code, _ := reflectTypeCode(typ)
+	descriptor := &TypeDescriptor{Code: code}
+	switch code {
+	case SchemaType:
+		descriptor.Schema = typ.Name()
+	case ListType, PointerType:
+		descriptor.Elem = reflectDescriptor(typ.Elem())
+	case MapType:
+		descriptor.Key = reflectDescriptor(typ.Key())
+		descriptor.Elem = reflectDescriptor(typ.Elem())
+	}
+	return descriptor
+}
+
+// reflectFingerprint computes the same fingerprint as the generator. Like
+// there, the second return value is the lowest index in the stack that the
+// fingerprint depends on.
+func reflectFingerprint(rt *reflectType, stack []reflect.Type, lookup func(reflect.Type) *reflectType) (uint64, int) {
+	if fp, ok := reflectRegistry.fingerprints[rt.typ]; ok {
+		return fp, len(stack)
+	}
+	stack = append(stack, rt.typ)
+	lowest := len(stack)
+
+	var description strings.Builder
+	fmt.Fprintf(&description, "schema %v\n", rt.name)
+	for i := range rt.fields {
+		signature, depth := reflectSignature(rt.fields[i].typ, stack, lookup)
+		if depth < lowest {
+			lowest = depth
+		}
+		fmt.Fprintf(&description, "%v %v\n", rt.entries[i].Name, signature)
+	}
+
+	hash := fnv.New64a()
+	hash.Write([]byte(description.String()))
+	fp := hash.Sum64()
+	if lowest == len(stack) {
+		reflectRegistry.fingerprints[rt.typ] = fp
+	}
+	return fp, lowest
+}
+
+func reflectSignature(typ reflect.Type, stack []reflect.Type, lookup func(reflect.Type) *reflectType) (string, int) {
+	typeCode, _ := reflectTypeCode(typ)
+	switch typeCode {
+	case SchemaType:
+		for i := range stack {
+			if stack[i] == typ {
+				return fmt.Sprintf("%v(%v)", typeCode, typ.Name()), i
+			}
+		}
+		fp, depth := reflectFingerprint(lookup(typ), stack, lookup)
+		return fmt.Sprintf("%v(%016x)", typeCode, fp), depth
+	case ListType, PointerType:
+		element, depth := reflectSignature(typ.Elem(), stack, lookup)
+		return fmt.Sprintf("%v(%v)", typeCode, element), depth
+	case MapType:
+		key, keyDepth := reflectSignature(typ.Key(), stack, lookup)
+		value, valueDepth := reflectSignature(typ.Elem(), stack, lookup)
+		if keyDepth < valueDepth {
+			valueDepth = keyDepth
+		}
+		return fmt.Sprintf("%v(%v,%v)", typeCode, key, value), valueDepth
+	}
+	return fmt.Sprint(typeCode), len(stack)
+}
+
+// parseDefault parses the value of a schemaDefault tag. Composite values are
+// given as JSON like for the generator, other values as Go literals. Unlike
+// the generator, names of constants are not supported.
+func parseDefault(typ reflect.Type, value string) (reflect.Value, error) {
+	result := reflect.New(typ).Elem()
+	invalid := fmt.Errorf("%v is not a valid %v", value, typ.String())
+	switch typ.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map, reflect.Struct, reflect.Ptr:
+		decoder := json.NewDecoder(strings.NewReader(value))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(result.Addr().Interface()); err != nil {
+			return result, err
+		}
+	case reflect.Bool:
+		if value != "true" && value != "false" {
+			return result, invalid
+		}
+		result.SetBool(value == "true")
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(value, 0, typ.Bits())
+		if err != nil {
+			return result, invalid
+		}
+		result.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(value, 0, typ.Bits())
+		if err != nil {
+			return result, invalid
+		}
+		result.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(value, typ.Bits())
+		if err != nil {
+			return result, invalid
+		}
+		result.SetFloat(f)
+	case reflect.String:
+		s, err := strconv.Unquote(value)
+		if err != nil {
+			return result, invalid
+		}
+		result.SetString(s)
+	}
+	return result, nil
+}
+
+func (rt *reflectType) newSchema() *reflectSchema {
+	schema := &reflectSchema{
+		reflectType: rt,
+		offsets:     make([]int, len(rt.entries)),
+		types:       make([]TypeCode, len(rt.entries)),
+	}
+	for i := range rt.entries {
+		schema.offsets[i] = int(rt.entries[i].Offset)
+		schema.types[i] = rt.entries[i].Type
+	}
+	return schema
+}
+
+func (schema *reflectSchema) Fill(entries []SchemaEntry) {
+	for i := range schema.offsets {
+		schema.offsets[i] = -1
+	}
+	for i := range entries {
+		for j := range schema.entries {
+			if entries[i].Name == schema.entries[j].Name && schema.entries[j].CanRead(&entries[i]) {
+				schema.offsets[j] = int(entries[i].Offset)
+				schema.types[j] = entries[i].Type
+			}
+		}
+	}
+}
+
+func (schema *reflectSchema) Describe() []SchemaEntry {
+	return schema.entries
+}
+
+func (schema *reflectSchema) ID() SchemaID {
+	return schema.id
+}
+
+func (schema *reflectSchema) Fingerprint() uint64 {
+	return schema.fingerprint
+}
+
+func (schema *reflectSchema) Name() string {
+	return schema.name
+}
+
+func (schema *reflectSchema) GoType() string {
+	return schema.goType
+}
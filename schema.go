@@ -4,14 +4,59 @@ type Schema interface {
 	Fill([]SchemaEntry)
 	Describe() []SchemaEntry
 	ID() SchemaID
+	// Fingerprint returns a hash of the name, the fields and the nested
+	// schemata of the schema, or 0 if the layout of its fields is not known.
+	Fingerprint() uint64
+	// Name returns the name of the schema.
+	Name() string
+	// GoType returns the package path and name of the type that the schema
+	// is generated for.
+	GoType() string
+}
+
+// DeprecatedDescriber is implemented by schemata that still read fields which
+// are no longer written. DescribeDeprecated returns the entries of these fields;
+// their offsets are zero.
+type DeprecatedDescriber interface {
+	DescribeDeprecated() []SchemaEntry
+}
+
+// describeDeprecated returns the deprecated entries of a schema, if it has any.
+func describeDeprecated(schema Schema) []SchemaEntry {
+	if describer, ok := schema.(DeprecatedDescriber); ok {
+		return describer.DescribeDeprecated()
+	}
+	return nil
 }
 
 type SchemaID uint16
 
 type SchemaEntry struct {
-	Name   string
-	Offset uint32
-	Type   TypeCode
+	Name       string
+	Offset     uint32
+	Type       TypeCode
+	Descriptor *TypeDescriptor // nil if the schema DB does not record it
+	Aliases    []string        // names the field had previously
+	Required   bool            // whether reading fails if the field is missing
+}
+
+// MissingFieldError is returned when a required field is not present in the
+// data that is read.
+type MissingFieldError struct {
+	Schema string
+	Field  string
+}
+
+func (e *MissingFieldError) Error() string {
+	return "goschema: required field " + e.Field + " of " + e.Schema + " is missing"
+}
+
+// SchemaDefaulter can be implemented by types with generated schemata to set up
+// their defaults in one place. NakedRead calls SetSchemaDefaults before reading
+// the fields of an object; fields that are missing from the data and have no
+// default value of their own keep the values that it sets.
+type SchemaDefaulter interface {
+	SetSchemaDefaults()
 }
 
 type Reference uint32
@@ -1,20 +1,49 @@
 package goschema
 
 import (
+	"errors"
 	"io"
+	"sync"
 
 	"github.com/chasingcarrots/gobinary"
 )
 
+// Schema DBs used to start with the number of schemata. Now they start with
+// schemaDBMarker in its place, followed by the version of the format and the
+// number of schemata. Version 1 adds the fingerprint of each schema, version 2
+// its name and Go type, version 3 the type descriptor of each field, version 4
+// the aliases of each field and whether it is required, and version 5 the
+// deprecated fields, which are read, but not written.
+const schemaDBMarker = 0xFFFF
+const schemaDBVersion = 5
+
+// the maximum nesting of type descriptors in a schema DB
+const maxTypeDescriptorDepth = 64
+
+var ErrUnsupportedSchemaDBVersion = errors.New("goschema: unsupported schema DB version")
+
+// SchemaDB holds the schema descriptors that data was written with. Once it
+// has been filled, it may be shared by readers in several goroutines; the
+// schemata that they register are guarded by a mutex.
 type SchemaDB struct {
-	rawSchemata map[int][]SchemaEntry
-	schemata    map[int]Schema
+	rawSchemata  map[int][]SchemaEntry
+	fingerprints map[int]uint64
+	names        map[int]string
+	goTypes      map[int]string
+	deprecated   map[int][]SchemaEntry
+	schemata     map[int]Schema
+	mutex        *sync.RWMutex // guards schemata
 }
 
 func MakeSchemaDB() SchemaDB {
 	return SchemaDB{
-		rawSchemata: make(map[int][]SchemaEntry),
-		schemata:    make(map[int]Schema),
+		rawSchemata:  make(map[int][]SchemaEntry),
+		fingerprints: make(map[int]uint64),
+		names:        make(map[int]string),
+		goTypes:      make(map[int]string),
+		deprecated:   make(map[int][]SchemaEntry),
+		schemata:     make(map[int]Schema),
+		mutex:        &sync.RWMutex{},
 	}
 }
 
@@ -23,28 +52,211 @@ func (sdb *SchemaDB) FindSchema(schemaIndex int) (Schema, []SchemaEntry) {
 	if !ok {
 		return nil, nil
 	}
+	sdb.mutex.RLock()
 	schema, ok := sdb.schemata[schemaIndex]
+	sdb.mutex.RUnlock()
 	if !ok {
 		return nil, raw
 	}
 	return schema, raw
 }
 
+// Fingerprint returns the fingerprint of the schema with the given index. The
+// second return value is false if the schema DB does not record one.
+func (sdb *SchemaDB) Fingerprint(schemaIndex int) (uint64, bool) {
+	fingerprint, ok := sdb.fingerprints[schemaIndex]
+	return fingerprint, ok
+}
+
+// NumSchemata returns the number of schemata in the schema DB.
+func (sdb *SchemaDB) NumSchemata() int {
+	return len(sdb.rawSchemata)
+}
+
+// SchemaName returns the name of the schema with the given index and the Go
+// type it was generated for. Both are empty if the schema DB does not record
+// them.
+func (sdb *SchemaDB) SchemaName(schemaIndex int) (name, goType string) {
+	return sdb.names[schemaIndex], sdb.goTypes[schemaIndex]
+}
+
+// DeprecatedEntries returns the entries of the fields of the schema with the
+// given index that were read, but no longer written, by the code that wrote the
+// schema DB.
+func (sdb *SchemaDB) DeprecatedEntries(schemaIndex int) []SchemaEntry {
+	return sdb.deprecated[schemaIndex]
+}
+
+// FindSchemaByName returns the index of the schema with the given name.
+func (sdb *SchemaDB) FindSchemaByName(name string) (int, bool) {
+	for i := 0; i < len(sdb.rawSchemata); i++ {
+		if sdb.names[i] == name {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
 func (sdb *SchemaDB) RegisterSchema(schemaIndex int, schema Schema) {
+	sdb.mutex.Lock()
 	sdb.schemata[schemaIndex] = schema
+	sdb.mutex.Unlock()
+}
+
+func (sdb *SchemaDB) Fill(reader io.Reader) error {
+	_, _, err := sdb.fill(reader)
+	return err
 }
 
-func (sdb *SchemaDB) Fill(reader io.Reader) {
-	hlr := gobinary.MakeHighLevelReader(reader)
+// fill reads a schema DB and returns its size in bytes and its version.
+// Truncated data is reported as ErrInvalidSchemaDB; empty data is an empty
+// schema DB.
+func (sdb *SchemaDB) fill(reader io.Reader) (int64, int, error) {
+	input := &errorReader{Reader: reader}
+	hlr := gobinary.MakeHighLevelReader(input)
+	size := int64(2)
+	version := 0
 	n := int(hlr.ReadUInt16())
+	if input.err != nil {
+		if input.read == 0 && input.err == io.EOF {
+			return 0, version, nil
+		}
+		return 0, version, input.err
+	}
+	if n == schemaDBMarker {
+		version = int(hlr.ReadUInt16())
+		if version > schemaDBVersion {
+			return 0, version, ErrUnsupportedSchemaDBVersion
+		}
+		n = int(hlr.ReadUInt16())
+		size += 4
+	}
 	for s := 0; s < n; s++ {
 		length := int(hlr.ReadUInt16())
-		schema := make([]SchemaEntry, length, length)
-		for i := 0; i < length; i++ {
-			schema[i].Name = hlr.ReadString(int(hlr.ReadUInt16()))
-			schema[i].Type = TypeCode(hlr.ReadUInt8())
-			schema[i].Offset = hlr.ReadUInt32()
+		size += 2
+		if version >= 1 {
+			sdb.fingerprints[s] = hlr.ReadUInt64()
+			size += 8
+		}
+		if version >= 2 {
+			sdb.names[s] = hlr.ReadString(int(hlr.ReadUInt16()))
+			sdb.goTypes[s] = hlr.ReadString(int(hlr.ReadUInt16()))
+			size += int64(2 + len(sdb.names[s]) + 2 + len(sdb.goTypes[s]))
+		}
+		if input.err != nil {
+			return 0, version, ErrInvalidSchemaDB
+		}
+		schema, entriesSize, err := readEntries(&hlr, input, version, length)
+		if err != nil {
+			return 0, version, err
+		}
+		size += entriesSize
+		if version >= 5 {
+			length := int(hlr.ReadUInt16())
+			if input.err != nil {
+				return 0, version, ErrInvalidSchemaDB
+			}
+			deprecated, deprecatedSize, err := readEntries(&hlr, input, version, length)
+			if err != nil {
+				return 0, version, err
+			}
+			if length > 0 {
+				sdb.deprecated[s] = deprecated
+			}
+			size += 2 + deprecatedSize
 		}
 		sdb.rawSchemata[s] = schema
 	}
+	return size, version, nil
+}
+
+// readEntries reads the given number of entries of a schema and returns them
+// together with the number of bytes read.
+func readEntries(hlr *gobinary.HighLevelReader, input *errorReader, version, length int) ([]SchemaEntry, int64, error) {
+	size := int64(0)
+	schema := make([]SchemaEntry, length, length)
+	for i := 0; i < length; i++ {
+		schema[i].Name = hlr.ReadString(int(hlr.ReadUInt16()))
+		schema[i].Type = TypeCode(hlr.ReadUInt8())
+		schema[i].Offset = hlr.ReadUInt32()
+		size += int64(2 + len(schema[i].Name) + 1 + 4)
+		if input.err != nil {
+			return nil, 0, ErrInvalidSchemaDB
+		}
+		if version >= 3 {
+			descriptor, descriptorSize, err := readTypeDescriptor(hlr, 0)
+			if err != nil {
+				return nil, 0, err
+			}
+			schema[i].Descriptor = descriptor
+			size += descriptorSize
+		}
+		if version >= 4 {
+			schema[i].Required = hlr.ReadBool()
+			numAliases := int(hlr.ReadUInt8())
+			size += 2
+			for a := 0; a < numAliases; a++ {
+				alias := hlr.ReadString(int(hlr.ReadUInt16()))
+				if input.err != nil {
+					return nil, 0, ErrInvalidSchemaDB
+				}
+				schema[i].Aliases = append(schema[i].Aliases, alias)
+				size += int64(2 + len(alias))
+			}
+		}
+	}
+	if input.err != nil {
+		return nil, 0, ErrInvalidSchemaDB
+	}
+	return schema, size, nil
+}
+
+// errorReader keeps the first error of a reader that ended before all of the
+// requested data was read.
+type errorReader struct {
+	io.Reader
+	read int64
+	err  error
+}
+
+func (r *errorReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	r.read += int64(n)
+	if err != nil && n < len(p) && r.err == nil {
+		r.err = err
+	}
+	return n, err
+}
+
+// readTypeDescriptor reads a type descriptor that is preceded by a flag that
+// tells whether it is present. It returns the descriptor and the number of
+// bytes read.
+func readTypeDescriptor(hlr *gobinary.HighLevelReader, depth int) (*TypeDescriptor, int64, error) {
+	if depth > maxTypeDescriptorDepth {
+		return nil, 0, ErrInvalidSchemaDB
+	}
+	if !hlr.ReadBool() {
+		return nil, 1, nil
+	}
+	descriptor := &TypeDescriptor{Code: TypeCode(hlr.ReadUInt8())}
+	size := int64(2)
+	var err error
+	var nestedSize int64
+	switch descriptor.Code {
+	case SchemaType:
+		descriptor.Schema = hlr.ReadString(int(hlr.ReadUInt16()))
+		size += int64(2 + len(descriptor.Schema))
+	case MapType:
+		if descriptor.Key, nestedSize, err = readTypeDescriptor(hlr, depth+1); err != nil {
+			return nil, 0, err
+		}
+		size += nestedSize
+		fallthrough
+	case ListType, PointerType:
+		if descriptor.Elem, nestedSize, err = readTypeDescriptor(hlr, depth+1); err != nil {
+			return nil, 0, err
+		}
+		size += nestedSize
+	}
+	return descriptor, size, nil
 }
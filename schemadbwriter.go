@@ -1,6 +1,7 @@
 package goschema
 
 import (
+	"errors"
 	"io"
 
 	"github.com/chasingcarrots/gobinary"
@@ -11,8 +12,21 @@ type SchemaDBWriter struct {
 	stream         *gobinary.StreamWriter
 	writer         gobinary.HighLevelWriter
 	originalOffset int64
+	// existing holds the schemata that were loaded by OpenSchemaDBWriter
+	existing []schemaRecord
+	count    int
 }
 
+// schemaRecord holds what the schema DB stores for each schema
+type schemaRecord struct {
+	entries      []SchemaEntry
+	deprecated   []SchemaEntry // fields that are read, but not written
+	fingerprint  uint64        // zero if the schema DB did not record it
+	name, goType string
+}
+
+var ErrInvalidSchemaDB = errors.New("goschema: invalid schema DB")
+
 func MakeSchemaDBWriter(stream *gobinary.StreamWriter) SchemaDBWriter {
 	dbWriter := SchemaDBWriter{
 		schemaIndex:    make(map[SchemaID]SchemaDataEntry),
@@ -20,11 +34,80 @@ func MakeSchemaDBWriter(stream *gobinary.StreamWriter) SchemaDBWriter {
 		originalOffset: stream.Offset(),
 		writer:         gobinary.MakeHighLevelWriter(stream),
 	}
-	// reserve 2 bytes for the number of schemas
-	dbWriter.writer.WriteUInt16(0)
+	dbWriter.writeHeader()
 	return dbWriter
 }
 
+func (sd *SchemaDBWriter) writeHeader() {
+	sd.writer.WriteUInt16(schemaDBMarker)
+	sd.writer.WriteUInt16(schemaDBVersion)
+	// reserve 2 bytes for the number of schemas
+	sd.writer.WriteUInt16(0)
+}
+
+// OpenSchemaDBWriter continues a schema DB that was written before, e.g. in an
+// earlier session. The stream must be positioned at the start of the schema DB;
+// if it is empty, a new schema DB is started. Schemata that are already present
+// keep their indices, and registering a schema whose descriptor and fingerprint
+// match an existing one yields that index. New schemata are appended after the
+// existing ones. Schema DBs in an older format are rewritten in the current one.
+func OpenSchemaDBWriter(stream io.ReadWriteSeeker) (SchemaDBWriter, error) {
+	start, err := stream.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return SchemaDBWriter{}, err
+	}
+	end, err := stream.Seek(0, io.SeekEnd)
+	if err != nil {
+		return SchemaDBWriter{}, err
+	}
+	if _, err := stream.Seek(start, io.SeekStart); err != nil {
+		return SchemaDBWriter{}, err
+	}
+	if end == start {
+		return MakeSchemaDBWriter(gobinary.NewStreamWriter(stream)), nil
+	}
+
+	db := MakeSchemaDB()
+	size, version, err := db.fill(stream)
+	if err != nil {
+		return SchemaDBWriter{}, err
+	}
+	if start+size > end {
+		return SchemaDBWriter{}, ErrInvalidSchemaDB
+	}
+	existing := make([]schemaRecord, len(db.rawSchemata))
+	for i := range existing {
+		existing[i].entries = db.rawSchemata[i]
+		existing[i].deprecated = db.deprecated[i]
+		existing[i].fingerprint = db.fingerprints[i]
+		existing[i].name, existing[i].goType = db.SchemaName(i)
+	}
+
+	rewrite := version != schemaDBVersion
+	if rewrite {
+		size = 0
+	}
+	if _, err := stream.Seek(start+size, io.SeekStart); err != nil {
+		return SchemaDBWriter{}, err
+	}
+	writer := gobinary.NewStreamWriter(stream)
+	dbWriter := SchemaDBWriter{
+		schemaIndex:    make(map[SchemaID]SchemaDataEntry),
+		stream:         writer,
+		originalOffset: start,
+		writer:         gobinary.MakeHighLevelWriter(writer),
+		existing:       existing,
+		count:          len(existing),
+	}
+	if rewrite {
+		dbWriter.writeHeader()
+		for i := range existing {
+			dbWriter.writeSchema(&existing[i])
+		}
+	}
+	return dbWriter, nil
+}
+
 func (sd *SchemaDBWriter) FindSchema(id SchemaID) (SchemaDataEntry, bool) {
 	entry, ok := sd.schemaIndex[id]
 	return entry, ok
@@ -35,28 +118,101 @@ func (sd *SchemaDBWriter) RegisterSchema(schema Schema) int {
 		return entry.index
 	}
 	entries := schema.Describe()
+	fingerprint := schema.Fingerprint()
+	for idx := range sd.existing {
+		existing := &sd.existing[idx]
+		if (existing.fingerprint == 0 || existing.fingerprint == fingerprint) && equalDescriptors(existing.entries, entries) {
+			sd.schemaIndex[schema.ID()] = SchemaDataEntry{
+				schema: schema,
+				index:  idx,
+			}
+			return idx
+		}
+	}
+	sd.writeSchema(&schemaRecord{
+		entries:     entries,
+		deprecated:  describeDeprecated(schema),
+		fingerprint: fingerprint,
+		name:        schema.Name(),
+		goType:      schema.GoType(),
+	})
+	idx := sd.count
+	sd.count++
+	sd.schemaIndex[schema.ID()] = SchemaDataEntry{
+		schema: schema,
+		index:  idx,
+	}
+	return idx
+}
+
+func (sd *SchemaDBWriter) writeSchema(schema *schemaRecord) {
+	entries := schema.entries
 	sd.writer.WriteUInt16(uint16(len(entries)))
+	sd.writer.WriteUInt64(schema.fingerprint)
+	sd.writer.WriteUInt16(uint16(len(schema.name)))
+	sd.writer.WriteString(schema.name)
+	sd.writer.WriteUInt16(uint16(len(schema.goType)))
+	sd.writer.WriteString(schema.goType)
+	sd.writeEntries(entries)
+	sd.writer.WriteUInt16(uint16(len(schema.deprecated)))
+	sd.writeEntries(schema.deprecated)
+}
+
+func (sd *SchemaDBWriter) writeEntries(entries []SchemaEntry) {
 	for i := range entries {
 		sd.writer.WriteUInt16(uint16(len(entries[i].Name)))
 		sd.writer.WriteString(entries[i].Name)
 		sd.writer.WriteUInt8(uint8(entries[i].Type))
 		sd.writer.WriteUInt32(entries[i].Offset)
+		sd.writeTypeDescriptor(entries[i].Descriptor)
+		sd.writer.WriteBool(entries[i].Required)
+		sd.writer.WriteUInt8(uint8(len(entries[i].Aliases)))
+		for _, alias := range entries[i].Aliases {
+			sd.writer.WriteUInt16(uint16(len(alias)))
+			sd.writer.WriteString(alias)
+		}
 	}
-	idx := len(sd.schemaIndex)
-	sd.schemaIndex[schema.ID()] = SchemaDataEntry{
-		schema: schema,
-		index:  idx,
+}
+
+func (sd *SchemaDBWriter) writeTypeDescriptor(descriptor *TypeDescriptor) {
+	sd.writer.WriteBool(descriptor != nil)
+	if descriptor == nil {
+		return
+	}
+	sd.writer.WriteUInt8(uint8(descriptor.Code))
+	switch descriptor.Code {
+	case SchemaType:
+		sd.writer.WriteUInt16(uint16(len(descriptor.Schema)))
+		sd.writer.WriteString(descriptor.Schema)
+	case MapType:
+		sd.writeTypeDescriptor(descriptor.Key)
+		fallthrough
+	case ListType, PointerType:
+		sd.writeTypeDescriptor(descriptor.Elem)
 	}
-	return idx
 }
 
 func (sd *SchemaDBWriter) Close() {
 	offset := sd.stream.Offset()
-	sd.stream.Seek(sd.originalOffset, io.SeekStart)
-	sd.writer.WriteUInt16(uint16(len(sd.schemaIndex)))
+	// the number of schemas follows the marker and the version
+	sd.stream.Seek(sd.originalOffset+4, io.SeekStart)
+	sd.writer.WriteUInt16(uint16(sd.count))
 	sd.stream.Seek(offset, io.SeekStart)
 }
 
+func equalDescriptors(a, b []SchemaEntry) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	// nested types are covered by the fingerprint
+	for i := range a {
+		if a[i].Name != b[i].Name || a[i].Type != b[i].Type || a[i].Offset != b[i].Offset {
+			return false
+		}
+	}
+	return true
+}
+
 type SchemaDataEntry struct {
 	schema Schema
 	index  int
@@ -0,0 +1,222 @@
+package goschema_test
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/chasingcarrots/gobinary"
+	"github.com/chasingcarrots/goschema"
+	"github.com/chasingcarrots/goschema/generator/testdata/generated"
+)
+
+// writeSchemaDBVersion writes the schemata in the given version of the schema
+// DB format, like an older build would have written them.
+func writeSchemaDBVersion(version int, schemata ...goschema.Schema) []byte {
+	var buf gobinary.WriteBuffer
+	writer := gobinary.MakeHighLevelWriter(gobinary.NewStreamWriter(&buf))
+	if version > 0 {
+		writer.WriteUInt16(0xFFFF)
+		writer.WriteUInt16(uint16(version))
+	}
+	writer.WriteUInt16(uint16(len(schemata)))
+	for _, schema := range schemata {
+		entries := schema.Describe()
+		writer.WriteUInt16(uint16(len(entries)))
+		if version >= 1 {
+			writer.WriteUInt64(schema.Fingerprint())
+		}
+		if version >= 2 {
+			writer.WriteUInt16(uint16(len(schema.Name())))
+			writer.WriteString(schema.Name())
+			writer.WriteUInt16(uint16(len(schema.GoType())))
+			writer.WriteString(schema.GoType())
+		}
+		writeEntriesVersion(&writer, version, entries)
+		if version >= 5 {
+			var deprecated []goschema.SchemaEntry
+			if describer, ok := schema.(goschema.DeprecatedDescriber); ok {
+				deprecated = describer.DescribeDeprecated()
+			}
+			writer.WriteUInt16(uint16(len(deprecated)))
+			writeEntriesVersion(&writer, version, deprecated)
+		}
+	}
+	return buf.Bytes()
+}
+
+func writeEntriesVersion(writer *gobinary.HighLevelWriter, version int, entries []goschema.SchemaEntry) {
+	for _, entry := range entries {
+		writer.WriteUInt16(uint16(len(entry.Name)))
+		writer.WriteString(entry.Name)
+		writer.WriteUInt8(uint8(entry.Type))
+		writer.WriteUInt32(entry.Offset)
+		if version >= 3 {
+			writeTypeDescriptor(writer, entry.Descriptor)
+		}
+		if version >= 4 {
+			writer.WriteBool(entry.Required)
+			writer.WriteUInt8(uint8(len(entry.Aliases)))
+			for _, alias := range entry.Aliases {
+				writer.WriteUInt16(uint16(len(alias)))
+				writer.WriteString(alias)
+			}
+		}
+	}
+}
+
+func writeTypeDescriptor(writer *gobinary.HighLevelWriter, descriptor *goschema.TypeDescriptor) {
+	writer.WriteBool(descriptor != nil)
+	if descriptor == nil {
+		return
+	}
+	writer.WriteUInt8(uint8(descriptor.Code))
+	switch descriptor.Code {
+	case goschema.SchemaType:
+		writer.WriteUInt16(uint16(len(descriptor.Schema)))
+		writer.WriteString(descriptor.Schema)
+	case goschema.MapType:
+		writeTypeDescriptor(writer, descriptor.Key)
+		fallthrough
+	case goschema.ListType, goschema.PointerType:
+		writeTypeDescriptor(writer, descriptor.Elem)
+	}
+}
+
+// openSchemaDBFile creates a file that holds data and positions it at its
+// start.
+func openSchemaDBFile(t *testing.T, data []byte) *os.File {
+	file, err := os.Create(filepath.Join(t.TempDir(), "schema.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { file.Close() })
+	if _, err := file.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
+
+// readSchemaDBFile reads the schema DB at the start of file.
+func readSchemaDBFile(t *testing.T, file *os.File) (goschema.SchemaDB, []byte) {
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	data, err := io.ReadAll(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	schemaDB := goschema.MakeSchemaDB()
+	if err := schemaDB.Fill(bytes.NewReader(data)); err != nil {
+		t.Fatal(err)
+	}
+	return schemaDB, data
+}
+
+func TestOpenSchemaDBWriterVersions(t *testing.T) {
+	item, ranged, catalog := generated.NewItemSchema(), generated.NewRangedSchema(), generated.NewCatalogSchema()
+	for version := 0; version <= 5; version++ {
+		file := openSchemaDBFile(t, writeSchemaDBVersion(version, item, ranged))
+		dbWriter, err := goschema.OpenSchemaDBWriter(file)
+		if err != nil {
+			t.Fatalf("version %v: %v", version, err)
+		}
+		if idx := dbWriter.RegisterSchema(ranged); idx != 1 {
+			t.Errorf("version %v: existing schema has index %v", version, idx)
+		}
+		if idx := dbWriter.RegisterSchema(catalog); idx != 2 {
+			t.Errorf("version %v: new schema has index %v", version, idx)
+		}
+		dbWriter.Close()
+
+		// the schema DB is rewritten in the current format
+		schemaDB, data := readSchemaDBFile(t, file)
+		if !bytes.Equal(data[:4], []byte{0xFF, 0xFF, 5, 0}) {
+			t.Errorf("version %v: header %v", version, data[:4])
+		}
+		if schemaDB.NumSchemata() != 3 {
+			t.Fatalf("version %v: %v schemata", version, schemaDB.NumSchemata())
+		}
+		for idx, schema := range []goschema.Schema{item, ranged, catalog} {
+			_, entries := schemaDB.FindSchema(idx)
+			expected := append([]goschema.SchemaEntry(nil), schema.Describe()...)
+			if idx < 2 && version < 4 {
+				// only the layout is known for older formats
+				for i := range expected {
+					expected[i] = goschema.SchemaEntry{Name: expected[i].Name, Type: expected[i].Type, Offset: expected[i].Offset}
+					if version >= 3 {
+						expected[i].Descriptor = schema.Describe()[i].Descriptor
+					}
+				}
+			}
+			if !reflect.DeepEqual(entries, expected) {
+				t.Errorf("version %v: schema %v has entries %+v", version, idx, entries)
+			}
+			name, _ := schemaDB.SchemaName(idx)
+			if (idx == 2 || version >= 2) && name != schema.Name() {
+				t.Errorf("version %v: schema %v has name %q", version, idx, name)
+			}
+			fingerprint, _ := schemaDB.Fingerprint(idx)
+			if (idx == 2 || version >= 1) && fingerprint != schema.Fingerprint() {
+				t.Errorf("version %v: schema %v has fingerprint %x", version, idx, fingerprint)
+			}
+		}
+		deprecated := schemaDB.DeprecatedEntries(1)
+		if version >= 5 && !reflect.DeepEqual(deprecated, ranged.DescribeDeprecated()) {
+			t.Errorf("version %v: deprecated entries %+v", version, deprecated)
+		} else if version < 5 && deprecated != nil {
+			t.Errorf("version %v: deprecated entries %+v", version, deprecated)
+		}
+	}
+}
+
+func TestOpenSchemaDBWriterAppends(t *testing.T) {
+	item, catalog := generated.NewItemSchema(), generated.NewCatalogSchema()
+	file := openSchemaDBFile(t, nil)
+	dbWriter, err := goschema.OpenSchemaDBWriter(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dbWriter.RegisterSchema(item)
+	dbWriter.Close()
+	_, before := readSchemaDBFile(t, file)
+
+	// registering a known schema leaves the schema DB unchanged
+	file.Seek(0, io.SeekStart)
+	dbWriter, err = goschema.OpenSchemaDBWriter(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if idx := dbWriter.RegisterSchema(item); idx != 0 {
+		t.Errorf("existing schema has index %v", idx)
+	}
+	dbWriter.Close()
+	_, data := readSchemaDBFile(t, file)
+	if !bytes.Equal(data, before) {
+		t.Errorf("schema DB changed from %v to %v", before, data)
+	}
+
+	// new schemata are appended after the existing ones
+	file.Seek(0, io.SeekStart)
+	dbWriter, err = goschema.OpenSchemaDBWriter(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if idx := dbWriter.RegisterSchema(catalog); idx != 1 {
+		t.Errorf("new schema has index %v", idx)
+	}
+	dbWriter.Close()
+	schemaDB, data := readSchemaDBFile(t, file)
+	if !bytes.Equal(data[6:len(before)], before[6:]) {
+		t.Error("existing schemata were rewritten")
+	}
+	if name, _ := schemaDB.SchemaName(1); schemaDB.NumSchemata() != 2 || name != catalog.Name() {
+		t.Errorf("%v schemata, the last is %q", schemaDB.NumSchemata(), name)
+	}
+}
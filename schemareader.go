@@ -1,6 +1,8 @@
 package goschema
 
 import (
+	"fmt"
+	"hash/crc32"
 	"io"
 
 	"github.com/chasingcarrots/gobinary"
@@ -9,7 +11,42 @@ import (
 type SchemaReader struct {
 	gobinary.HighLevelReader
 	gobinary.StreamReaderView
-	schemaDB *SchemaDB
+	schemaDB       *SchemaDB
+	checksumBuffer []byte
+	bytes          *byteReader
+	err            error
+	verifyNames    bool
+	limits         ReadLimits
+	allocated      int64 // bytes allocated for the current outermost object
+	depth          int   // number of objects that are currently read
+	streamEnd      int64 // global offset of the end of the stream when it was last measured
+	// the outermost open object whose checksum has been verified, if any; the
+	// checksums of objects within it are not verified again
+	verifiedDepth              int
+	verifiedStart, verifiedEnd int64
+}
+
+// SchemaNameError is reported when the name that the schema DB records for a
+// schema differs from the name of the schema that is used to read it.
+type SchemaNameError struct {
+	Index    int
+	Expected string
+	Actual   string
+}
+
+func (e *SchemaNameError) Error() string {
+	return fmt.Sprintf("goschema: schema %v is %v, expected %v", e.Index, e.Actual, e.Expected)
+}
+
+// SchemaIndexError is reported when the data refers to a schema that the
+// schema DB does not contain.
+type SchemaIndexError struct {
+	Index       int
+	NumSchemata int
+}
+
+func (e *SchemaIndexError) Error() string {
+	return fmt.Sprintf("goschema: schema index %v is out of range, the schema DB has %v schemata", e.Index, e.NumSchemata)
 }
 
 func MakeSchemaReader(schemaDB *SchemaDB, streamView gobinary.StreamReaderView) SchemaReader {
@@ -24,14 +61,130 @@ func MakeSchemaReader(schemaDB *SchemaDB, streamView gobinary.StreamReaderView)
 // the offset denoted by the reference. It returns offset in global coordinates
 // that the reader should return to after reading what is referenced.
 func (sr *SchemaReader) ReadReference() int64 {
-	current := sr.StreamReaderView.Offset()
+	current := sr.Offset()
 	ref := sr.ReadUInt32()
 	sr.Seek(int64(ref), io.SeekStart)
 	return current + ReferenceSize
 }
 
+// BeginObject reads the length of an object and moves the view to the start
+// of the object's data. If the object carries a checksum, it is verified
+// unless the object lies within an enclosing object that was verified already.
+// The name of the schema is only used for error reporting. BeginObject returns
+// the global offset at which the object ends. Each successful call must be
+// followed by a call to EndObject once the object has been read.
+func (sr *SchemaReader) BeginObject(schemaName string) (int64, error) {
+	objectOffset := sr.GlobalOffset()
+	length := sr.ReadUInt32()
+	if err := sr.Err(); err != nil {
+		return 0, err
+	}
+	if max := sr.limits.MaxDepth; max > 0 && sr.depth >= max {
+		sr.err = &LimitError{Limit: "MaxDepth", Value: int64(sr.depth + 1), Max: int64(max)}
+		return 0, sr.err
+	}
+	if sr.depth == 0 {
+		sr.allocated = 0
+	}
+	startOffset := sr.GlobalOffset()
+	endOffset := startOffset + int64(length&^ChecksumFlag)
+	sr.ViewHere()
+	if length&ChecksumFlag == 0 {
+		sr.depth++
+		return endOffset, nil
+	}
+
+	if sr.verifiedDepth > 0 && objectOffset >= sr.verifiedStart && endOffset+ChecksumSize <= sr.verifiedEnd {
+		sr.depth++
+		return endOffset + ChecksumSize, nil
+	}
+	data, err := sr.readChecksummed(int(length &^ ChecksumFlag))
+	if err != nil {
+		return 0, err
+	}
+	stored := sr.ReadUInt32()
+	computed := crc32.Checksum(data, castagnoliTable)
+	if stored != computed {
+		return 0, &ChecksumError{
+			Schema:   schemaName,
+			Offset:   objectOffset,
+			Expected: stored,
+			Actual:   computed,
+		}
+	}
+	sr.Seek(0, io.SeekStart)
+	sr.depth++
+	if sr.verifiedDepth == 0 {
+		sr.verifiedDepth = sr.depth
+		sr.verifiedStart, sr.verifiedEnd = objectOffset, endOffset+ChecksumSize
+	}
+	return endOffset + ChecksumSize, nil
+}
+
+// readChecksummed returns the next size bytes. The data is only valid until
+// the next call.
+func (sr *SchemaReader) readChecksummed(size int) ([]byte, error) {
+	if sr.bytes != nil {
+		data := sr.bytes.next(size)
+		return data, sr.bytes.err
+	}
+	if cap(sr.checksumBuffer) < size {
+		if max := sr.limits.MaxAllocation; max > 0 && int64(size) > max {
+			sr.err = &LimitError{Limit: "MaxAllocation", Value: int64(size), Max: max}
+			return nil, sr.err
+		}
+		sr.checksumBuffer = make([]byte, size)
+	}
+	data := sr.checksumBuffer[:size]
+	if _, err := io.ReadFull(sr, data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// FindSchema returns the schema registered for the given index and the entries
+// that the schema DB records for it. If the schema DB does not contain the
+// index, the reader fails with a *SchemaIndexError.
 func (sr *SchemaReader) FindSchema(schemaIndex int) (Schema, []SchemaEntry) {
-	return sr.schemaDB.FindSchema(schemaIndex)
+	schema, entries := sr.schemaDB.FindSchema(schemaIndex)
+	if entries == nil && sr.err == nil {
+		sr.err = &SchemaIndexError{Index: schemaIndex, NumSchemata: sr.schemaDB.NumSchemata()}
+	}
+	return schema, entries
+}
+
+// Err returns the first error that occurred while reading.
+func (sr *SchemaReader) Err() error {
+	if sr.err != nil {
+		return sr.err
+	}
+	if sr.bytes == nil {
+		return nil
+	}
+	return sr.bytes.err
+}
+
+// VerifySchemaNames makes the reader check the names that the schema DB
+// records against the names of the schemata that read the data. Since renaming
+// a type changes the name of its schema, this is disabled by default.
+func (sr *SchemaReader) VerifySchemaNames() {
+	sr.verifyNames = true
+}
+
+// VerifySchemaName is called by generated code whenever the schema with the
+// given index is first used. If verification is enabled and the schema DB
+// records a different name, the reader fails with a *SchemaNameError.
+func (sr *SchemaReader) VerifySchemaName(schemaIndex int, name string) {
+	if !sr.verifyNames || sr.err != nil {
+		return
+	}
+	if actual, _ := sr.schemaDB.SchemaName(schemaIndex); actual != "" && actual != name {
+		sr.err = &SchemaNameError{Index: schemaIndex, Expected: name, Actual: actual}
+	}
+}
+
+func (sr *SchemaReader) SchemaFingerprint(schemaIndex int) (uint64, bool) {
+	return sr.schemaDB.Fingerprint(schemaIndex)
 }
 
 func (sr *SchemaReader) RegisterSchema(schemaIndex int, schema Schema) {
@@ -47,5 +200,8 @@ func (sr *SchemaReader) ReadUInt() uint64 {
 }
 
 func (sr *SchemaReader) Read(p []byte) (int, error) {
+	if sr.bytes != nil {
+		return sr.bytes.Read(p)
+	}
 	return sr.Reader.Read(p)
 }
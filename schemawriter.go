@@ -1,6 +1,8 @@
 package goschema
 
 import (
+	"io"
+
 	"github.com/chasingcarrots/gobinary"
 )
 
@@ -8,14 +10,109 @@ type SchemaWriter struct {
 	gobinary.HighLevelWriter
 	gobinary.StreamWriterView
 	schemaData *SchemaDBWriter
+	checksums  *checksumMirror
+	forward    *forwardStream
+	bytes      *byteWriter
+	stream     *streamErrors
+	err        error
 }
 
 func MakeSchemaWriter(schemaData *SchemaDBWriter, streamView gobinary.StreamWriterView) SchemaWriter {
+	stream := &streamErrors{view: streamView}
 	return SchemaWriter{
 		schemaData:       schemaData,
 		StreamWriterView: streamView,
-		HighLevelWriter:  gobinary.MakeHighLevelWriter(streamView),
+		HighLevelWriter:  gobinary.MakeHighLevelWriter(stream),
+		stream:           stream,
+	}
+}
+
+// streamErrors keeps the first error returned by the stream of a SchemaWriter,
+// e.g. ErrBlockFlushed of a CompressedWriter, since the writing methods do not
+// return errors.
+type streamErrors struct {
+	view gobinary.StreamWriterView
+	err  error
+}
+
+func (se *streamErrors) Write(p []byte) (int, error) {
+	n, err := se.view.Write(p)
+	if err != nil && se.err == nil {
+		se.err = err
+	}
+	return n, err
+}
+
+// EnableChecksums makes the writer store a CRC32C checksum after the data of
+// each object. To compute the checksum once the object is complete, the writer
+// keeps a copy of the data of the outermost object that is currently written,
+// unless it writes to a byte slice.
+func (sw *SchemaWriter) EnableChecksums() {
+	if sw.checksums != nil {
+		return
+	}
+	if sw.bytes != nil {
+		// the data is in memory already
+		sw.checksums = &checksumMirror{buffer: sw.bytes}
+		return
 	}
+	sw.checksums = &checksumMirror{
+		view:   sw.StreamWriterView,
+		output: sw.stream,
+		origin: sw.GlobalOffset(),
+	}
+	sw.HighLevelWriter = gobinary.MakeHighLevelWriter(sw.checksums)
+}
+
+// IsForward returns whether the writer cannot seek and thus requires objects
+// to be written front to back.
+func (sw *SchemaWriter) IsForward() bool {
+	return sw.forward != nil
+}
+
+// Err returns the first error that was recorded by Fail or that occurred while
+// writing to the stream, such as ErrBlockFlushed, or to the output of a forward
+// writer.
+func (sw *SchemaWriter) Err() error {
+	if sw.err != nil {
+		return sw.err
+	}
+	if sw.stream != nil && sw.stream.err != nil {
+		return sw.stream.err
+	}
+	if sw.forward == nil {
+		return nil
+	}
+	return sw.forward.err
+}
+
+// Fail records an error that makes the written data invalid, such as a
+// *ValidationError. Only the first error is kept, and generated code does not
+// write any further objects once an error has occurred.
+func (sw *SchemaWriter) Fail(err error) {
+	if sw.err == nil {
+		sw.err = err
+	}
+}
+
+// FailedIn reports whether writing has failed. It is called by generated code
+// after writing a field that may contain other objects and prepends the name
+// of the field to the path of a *ValidationError.
+func (sw *SchemaWriter) FailedIn(field string) bool {
+	if sw.err == nil {
+		return sw.Err() != nil
+	}
+	sw.err = PrefixFieldPath(sw.err, field)
+	return true
+}
+
+// ObjectOverhead returns the number of bytes written for each object in
+// addition to its data.
+func (sw *SchemaWriter) ObjectOverhead() int {
+	if sw.checksums != nil {
+		return 4 + ChecksumSize
+	}
+	return 4
 }
 
 func (sw *SchemaWriter) FindSchema(id SchemaID) (SchemaDataEntry, bool) {
@@ -26,6 +123,53 @@ func (sw *SchemaWriter) RegisterSchema(schema Schema) int {
 	return sw.schemaData.RegisterSchema(schema)
 }
 
+// BeginObject reserves space for the length of an object and moves the view
+// to the start of the object's data. It returns the global offset of the data
+// which has to be passed to EndObject.
+func (sw *SchemaWriter) BeginObject() int64 {
+	sw.WriteUInt32(0) // reserved for size
+	sw.ViewHere()
+	if sw.checksums != nil {
+		sw.checksums.begin()
+	}
+	return sw.GlobalOffset()
+}
+
+// BeginSizedObject is like BeginObject, but writes the length of the object's
+// data right away so that EndObject does not need to seek back.
+func (sw *SchemaWriter) BeginSizedObject(length int) int64 {
+	if sw.checksums != nil {
+		sw.WriteUInt32(uint32(length) | ChecksumFlag)
+		sw.ViewHere()
+		sw.checksums.begin()
+	} else {
+		sw.WriteUInt32(uint32(length))
+		sw.ViewHere()
+	}
+	return sw.GlobalOffset()
+}
+
+// EndObject writes the length of the object whose data started at the given
+// global offset and ends at the current position, followed by its checksum if
+// checksums are enabled. For objects started with BeginSizedObject on a
+// forward writer, only the checksum is written.
+func (sw *SchemaWriter) EndObject(startOffset int64) {
+	endOffset := sw.GlobalOffset()
+	if sw.forward == nil {
+		length := uint32(endOffset - startOffset)
+		if sw.checksums != nil {
+			length |= ChecksumFlag
+		}
+		sw.Seek(sw.Local(startOffset-4), io.SeekStart)
+		sw.WriteUInt32(length)
+		sw.Seek(sw.Local(endOffset), io.SeekStart)
+	}
+	if sw.checksums != nil {
+		sw.WriteUInt32(sw.checksums.end(startOffset, endOffset))
+		sw.checksums.reset(sw.GlobalOffset())
+	}
+}
+
 func (sw *SchemaWriter) WriteInt(value int) {
 	sw.WriteInt64(int64(value))
 }
@@ -35,5 +179,11 @@ func (sw *SchemaWriter) WriteUInt(value uint) {
 }
 
 func (sw *SchemaWriter) Write(p []byte) (int, error) {
+	if sw.bytes != nil {
+		return sw.bytes.Write(p)
+	}
+	if sw.checksums != nil {
+		return sw.checksums.Write(p)
+	}
 	return sw.StreamWriterView.Write(p)
 }
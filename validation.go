@@ -0,0 +1,23 @@
+package goschema
+
+// ValidationError is reported when the value of a field violates a constraint
+// declared by one of the tags schemaMin, schemaMax, schemaMaxLen and
+// schemaNotNil.
+type ValidationError struct {
+	Schema     string // name of the schema that declares the field
+	Field      string // path from the outermost object to the field, e.g. "Items.Name"
+	Constraint string // the violated tag, e.g. `schemaMax:"100"`
+}
+
+func (e *ValidationError) Error() string {
+	return "goschema: " + e.Field + " violates " + e.Constraint + " of schema " + e.Schema
+}
+
+// PrefixFieldPath prepends the name of a field to the path of a
+// *ValidationError and returns all other errors unchanged.
+func PrefixFieldPath(err error, field string) error {
+	if validationErr, ok := err.(*ValidationError); ok {
+		validationErr.Field = field + "." + validationErr.Field
+	}
+	return err
+}
@@ -0,0 +1,84 @@
+package goschema
+
+// widenings maps the type code of a number to the type codes of the numbers
+// that it can represent exactly.
+var widenings = map[TypeCode][]TypeCode{
+	Int16Type:   {Int8Type, UInt8Type},
+	Int32Type:   {Int8Type, Int16Type, UInt8Type, UInt16Type},
+	Int64Type:   {Int8Type, Int16Type, Int32Type, IntType, UInt8Type, UInt16Type, UInt32Type},
+	IntType:     {Int8Type, Int16Type, Int32Type, Int64Type, UInt8Type, UInt16Type, UInt32Type},
+	UInt16Type:  {UInt8Type},
+	UInt32Type:  {UInt8Type, UInt16Type},
+	UInt64Type:  {UInt8Type, UInt16Type, UInt32Type, UIntType},
+	UIntType:    {UInt8Type, UInt16Type, UInt32Type, UInt64Type},
+	Float32Type: {Int8Type, Int16Type, UInt8Type, UInt16Type},
+	Float64Type: {Float32Type, Int8Type, Int16Type, Int32Type, UInt8Type, UInt16Type, UInt32Type},
+}
+
+// CanWiden reports whether a number stored with type code from can be read
+// as a number with type code to without losing information.
+func CanWiden(from, to TypeCode) bool {
+	for _, code := range widenings[to] {
+		if code == from {
+			return true
+		}
+	}
+	return false
+}
+
+// CanRead reports whether a field described by the entry can be read from data
+// described by the stored entry, either because their types match or because
+// the stored number can be widened.
+func (entry *SchemaEntry) CanRead(stored *SchemaEntry) bool {
+	if stored.Type == entry.Type {
+		return stored.Descriptor.Matches(entry.Descriptor)
+	}
+	return CanWiden(stored.Type, entry.Type)
+}
+
+// ReadWidenedInt reads an integer that was stored with the given type code.
+func (sr *SchemaReader) ReadWidenedInt(code TypeCode) int64 {
+	switch code {
+	case Int8Type:
+		return int64(sr.ReadInt8())
+	case Int16Type:
+		return int64(sr.ReadInt16())
+	case Int32Type:
+		return int64(sr.ReadInt32())
+	case UInt8Type:
+		return int64(sr.ReadUInt8())
+	case UInt16Type:
+		return int64(sr.ReadUInt16())
+	case UInt32Type:
+		return int64(sr.ReadUInt32())
+	}
+	return sr.ReadInt64()
+}
+
+// ReadWidenedUInt reads an unsigned integer that was stored with the given
+// type code.
+func (sr *SchemaReader) ReadWidenedUInt(code TypeCode) uint64 {
+	switch code {
+	case UInt8Type:
+		return uint64(sr.ReadUInt8())
+	case UInt16Type:
+		return uint64(sr.ReadUInt16())
+	case UInt32Type:
+		return uint64(sr.ReadUInt32())
+	}
+	return sr.ReadUInt64()
+}
+
+// ReadWidenedFloat reads a number that was stored with the given type code as
+// a floating point number.
+func (sr *SchemaReader) ReadWidenedFloat(code TypeCode) float64 {
+	switch code {
+	case Float32Type:
+		return float64(sr.ReadFloat32())
+	case Float64Type:
+		return sr.ReadFloat64()
+	case UInt8Type, UInt16Type, UInt32Type:
+		return float64(sr.ReadWidenedUInt(code))
+	}
+	return float64(sr.ReadWidenedInt(code))
+}
@@ -0,0 +1,96 @@
+package goschema
+
+import "testing"
+
+func TestCanWiden(t *testing.T) {
+	tests := []struct {
+		from, to TypeCode
+		widen    bool
+	}{
+		{Int8Type, Int16Type, true},
+		{UInt8Type, Int16Type, true},
+		{Int16Type, Int8Type, false},
+		{UInt16Type, Int16Type, false},
+		{Int32Type, Int64Type, true},
+		{UInt32Type, Int64Type, true},
+		{UInt64Type, Int64Type, false},
+		{IntType, Int64Type, true},
+		{Int64Type, IntType, true},
+		{UIntType, UInt64Type, true},
+		{Int8Type, UInt16Type, false},
+		{Int16Type, Float32Type, true},
+		{Int32Type, Float32Type, false},
+		{Int32Type, Float64Type, true},
+		{Int64Type, Float64Type, false},
+		{Float32Type, Float64Type, true},
+		{Float64Type, Float32Type, false},
+		{Int32Type, Int32Type, false},
+		{StringType, Int64Type, false},
+	}
+	for _, test := range tests {
+		if widen := CanWiden(test.from, test.to); widen != test.widen {
+			t.Errorf("CanWiden(%v, %v) = %v", test.from, test.to, widen)
+		}
+	}
+}
+
+func TestCanRead(t *testing.T) {
+	list := func(elem TypeCode) *TypeDescriptor {
+		return &TypeDescriptor{Code: ListType, Elem: &TypeDescriptor{Code: elem}}
+	}
+	tests := []struct {
+		name          string
+		entry, stored SchemaEntry
+		read          bool
+	}{
+		{"same type", SchemaEntry{Type: Int32Type}, SchemaEntry{Type: Int32Type}, true},
+		{"widened", SchemaEntry{Type: Int64Type}, SchemaEntry{Type: Int32Type}, true},
+		{"narrowed", SchemaEntry{Type: Int32Type}, SchemaEntry{Type: Int64Type}, false},
+		{"same elements", SchemaEntry{Type: ListType, Descriptor: list(Int32Type)}, SchemaEntry{Type: ListType, Descriptor: list(Int32Type)}, true},
+		{"other elements", SchemaEntry{Type: ListType, Descriptor: list(Int64Type)}, SchemaEntry{Type: ListType, Descriptor: list(Int32Type)}, false},
+		{"unknown elements", SchemaEntry{Type: ListType, Descriptor: list(Int64Type)}, SchemaEntry{Type: ListType}, true},
+	}
+	for _, test := range tests {
+		if read := test.entry.CanRead(&test.stored); read != test.read {
+			t.Errorf("%v: CanRead = %v", test.name, read)
+		}
+	}
+}
+
+func TestReadWidened(t *testing.T) {
+	tests := []struct {
+		code  TypeCode
+		write func(w *SchemaWriter)
+		value float64
+	}{
+		{Int8Type, func(w *SchemaWriter) { w.WriteInt8(-8) }, -8},
+		{Int16Type, func(w *SchemaWriter) { w.WriteInt16(-1600) }, -1600},
+		{Int32Type, func(w *SchemaWriter) { w.WriteInt32(-320000) }, -320000},
+		{UInt8Type, func(w *SchemaWriter) { w.WriteUInt8(200) }, 200},
+		{UInt16Type, func(w *SchemaWriter) { w.WriteUInt16(60000) }, 60000},
+		{UInt32Type, func(w *SchemaWriter) { w.WriteUInt32(4000000000) }, 4000000000},
+		{Float32Type, func(w *SchemaWriter) { w.WriteFloat32(0.5) }, 0.5},
+	}
+	for _, test := range tests {
+		writer := MakeByteSchemaWriter(nil, nil)
+		test.write(&writer)
+		data := writer.Bytes()
+
+		if test.code != Float32Type {
+			reader := MakeByteSchemaReader(nil, data)
+			if value := reader.ReadWidenedInt(test.code); value != int64(test.value) || reader.Err() != nil {
+				t.Errorf("ReadWidenedInt(%v) = %v, %v", test.code, value, reader.Err())
+			}
+		}
+		if test.value >= 0 && test.code != Float32Type {
+			reader := MakeByteSchemaReader(nil, data)
+			if value := reader.ReadWidenedUInt(test.code); value != uint64(test.value) || reader.Err() != nil {
+				t.Errorf("ReadWidenedUInt(%v) = %v, %v", test.code, value, reader.Err())
+			}
+		}
+		reader := MakeByteSchemaReader(nil, data)
+		if value := reader.ReadWidenedFloat(test.code); value != test.value || reader.Err() != nil {
+			t.Errorf("ReadWidenedFloat(%v) = %v, %v", test.code, value, reader.Err())
+		}
+	}
+}